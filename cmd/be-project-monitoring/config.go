@@ -10,4 +10,47 @@ type config struct {
 	ShutdownTimeout int    `required:"true" default:"30" split_words:"true" desc:"Время до принудительного завершения сервиса после получения сигнала выхода (s)"`
 
 	GHTOKEN string `required:"true" default:"" desc:"Github API token"`
+
+	GitlabURL   string `split_words:"true" desc:"Адрес self-hosted Gitlab, например https://gitlab.example.com"`
+	GitlabToken string `split_words:"true" desc:"Токен доступа к API Gitlab"`
+	GiteaURL    string `split_words:"true" desc:"Адрес self-hosted Gitea, например https://git.example.com"`
+	GiteaToken  string `split_words:"true" desc:"Токен доступа к API Gitea"`
+
+	GitMirrorDir       string   `split_words:"true" desc:"Каталог локальных зеркал репозиториев, если задан, коммиты считаются по истории git"`
+	GitLocalRoot       string   `split_words:"true" desc:"Каталог, из которого разрешено читать локальные репозитории file://"`
+	GitExcludePatterns []string `split_words:"true" desc:"Шаблоны файлов, строки которых не учитываются, например go.sum,vendor/"`
+
+	SMTPHost     string `split_words:"true" desc:"SMTP сервер для отправки уведомлений"`
+	SMTPPort     int    `split_words:"true" default:"587" desc:"Порт SMTP сервера"`
+	SMTPUsername string `split_words:"true" desc:"Пользователь SMTP сервера"`
+	SMTPPassword string `split_words:"true" desc:"Пароль SMTP сервера"`
+	SMTPFrom     string `split_words:"true" desc:"Адрес отправителя уведомлений"`
+
+	TelegramToken  string `split_words:"true" desc:"Токен telegram бота для отправки уведомлений"`
+	TelegramAPIURL string `split_words:"true" desc:"Адрес telegram bot API"`
+	WebhookSecret  string `split_words:"true" desc:"Секрет для подписи исходящих вебхуков"`
+
+	NotifyInterval    int `split_words:"true" default:"60" desc:"Интервал отправки уведомлений (s)"`
+	NotifyDigestHour  int `split_words:"true" default:"9" desc:"Час отправки ежедневной сводки уведомлений"`
+	NotifyMaxAttempts int `split_words:"true" default:"5" desc:"Число попыток отправки уведомления"`
+
+	ReminderInterval   int `split_words:"true" default:"3600" desc:"Интервал проверки сроков проектов и задач (s)"`
+	ReminderDaysBefore int `split_words:"true" default:"3" desc:"За сколько дней напоминать о сроке"`
+
+	GithubSyncInterval int `split_words:"true" default:"60" desc:"Интервал проверки проектов для синхронизации статистики Github (s)"`
+	GithubSyncPeriod   int `split_words:"true" default:"3600" desc:"Как часто обновлять статистику Github проекта (s)"`
+	IssueSyncInterval  int `split_words:"true" default:"300" desc:"Интервал синхронизации задач с Github Issues (s)"`
+
+	GithubWebhookSecret string `split_words:"true" desc:"Секрет для проверки подписи входящих вебхуков Github"`
+
+	StorageType         string `split_words:"true" default:"local" desc:"Хранилище файлов: local, s3"`
+	StorageDir          string `split_words:"true" default:"./data/files" desc:"Каталог локального хранилища файлов"`
+	StorageBaseURL      string `split_words:"true" default:"http://localhost:8080/api/storage" desc:"Адрес скачивания файлов локального хранилища"`
+	StorageSecret       string `split_words:"true" desc:"Секрет для подписи ссылок локального хранилища"`
+	S3Endpoint          string `envconfig:"S3_ENDPOINT" desc:"Адрес S3 совместимого хранилища"`
+	S3Region            string `envconfig:"S3_REGION" desc:"Регион S3 хранилища"`
+	S3Bucket            string `envconfig:"S3_BUCKET" desc:"Бакет S3 хранилища"`
+	S3AccessKey         string `envconfig:"S3_ACCESS_KEY" desc:"Ключ доступа S3 хранилища"`
+	S3SecretKey         string `envconfig:"S3_SECRET_KEY" desc:"Секретный ключ S3 хранилища"`
+	FileCleanupInterval int    `split_words:"true" default:"3600" desc:"Интервал удаления неиспользуемых файлов (s)"`
 }
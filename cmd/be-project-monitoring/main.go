@@ -4,14 +4,21 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"be-project-monitoring/internal/api"
 	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
 	"be-project-monitoring/internal/domain/service"
+	"be-project-monitoring/internal/notifier"
 	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/scheduler"
+	"be-project-monitoring/internal/storage"
+	"be-project-monitoring/internal/vcs"
 
 	"github.com/google/go-github/v49/github"
 	"github.com/kelseyhightower/envconfig"
@@ -57,11 +64,72 @@ func main() {
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	githubCl := github.NewClient(tc)
-	svc := service.NewService(repo, githubCl)
-	api.New(
+	apiOpts := []api.OptionFunc{
 		api.WithLogger(sugaredLogger),
-		api.WithService(svc),
-		api.WithShutdownTimeout(cfg.ShutdownTimeout)).Run(g)
+		api.WithShutdownTimeout(cfg.ShutdownTimeout),
+		api.WithGithubWebhookSecret(cfg.GithubWebhookSecret),
+	}
+	var fileStorage storage.Storage
+	switch cfg.StorageType {
+	case "s3":
+		fileStorage, err = storage.NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket,
+			cfg.S3AccessKey, cfg.S3SecretKey, &http.Client{Timeout: 5 * time.Minute})
+	default:
+		var localStorage *storage.LocalStorage
+		localStorage, err = storage.NewLocalStorage(cfg.StorageDir, cfg.StorageBaseURL, cfg.StorageSecret)
+		fileStorage = localStorage
+		apiOpts = append(apiOpts, api.WithStorageHandler(localStorage))
+	}
+	if err != nil {
+		sugaredLogger.Fatal(err.Error())
+	}
+
+	providers := vcs.Providers{
+		vcs.GithubHost: vcs.NewGithubProvider(githubCl),
+	}
+	// the local git analysis clones the repositories with the tokens of their hosts
+	tokens := map[string]string{vcs.GithubHost: cfg.GHTOKEN}
+	vcsClient := &http.Client{Timeout: time.Minute}
+	if cfg.GitlabURL != "" {
+		gitlab, err := vcs.NewGitlabProvider(cfg.GitlabURL, cfg.GitlabToken, vcsClient)
+		if err != nil {
+			sugaredLogger.Fatal(err.Error())
+		}
+		providers[gitlab.Host()] = gitlab
+		tokens[gitlab.Host()] = cfg.GitlabToken
+	}
+	if cfg.GiteaURL != "" {
+		gitea, err := vcs.NewGiteaProvider(cfg.GiteaURL, cfg.GiteaToken, vcsClient)
+		if err != nil {
+			sugaredLogger.Fatal(err.Error())
+		}
+		providers[gitea.Host()] = gitea
+		tokens[gitea.Host()] = cfg.GiteaToken
+	}
+	if cfg.GitMirrorDir != "" {
+		local, err := vcs.NewLocalGitProvider(cfg.GitMirrorDir, cfg.GitLocalRoot, tokens, cfg.GitExcludePatterns)
+		if err != nil {
+			sugaredLogger.Fatal(err.Error())
+		}
+		providers[vcs.LocalHost] = local
+	}
+	svc := service.NewService(repo, providers, fileStorage, sugaredLogger)
+	api.New(append(apiOpts, api.WithService(svc))...).Run(g)
+	notifier.NewDispatcher(repo, notifierOptions(cfg, sugaredLogger)...).Run(g)
+	scheduler.New(
+		scheduler.WithLogger(sugaredLogger),
+		scheduler.WithJob("deadline-reminders", time.Duration(cfg.ReminderInterval)*time.Second,
+			func(ctx context.Context) error {
+				return svc.SendDeadlineReminders(ctx, time.Now(), cfg.ReminderDaysBefore)
+			}),
+		scheduler.WithJob("file-cleanup", time.Duration(cfg.FileCleanupInterval)*time.Second,
+			svc.CleanupOrphanedFiles),
+		scheduler.WithJob("github-sync", time.Duration(cfg.GithubSyncInterval)*time.Second,
+			func(ctx context.Context) error {
+				return svc.SyncGithubStats(ctx, time.Now(), time.Duration(cfg.GithubSyncPeriod)*time.Second)
+			}),
+		scheduler.WithJob("issue-sync", time.Duration(cfg.IssueSyncInterval)*time.Second,
+			svc.SyncAllIssues)).Run(g)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	g.Add(func() error {
@@ -83,3 +151,33 @@ func main() {
 
 	sugaredLogger.Error("successful shutdown", zap.Error(g.Run()))
 }
+
+func notifierOptions(cfg *config, logger *zap.SugaredLogger) []notifier.OptionFunc {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	channels := make(map[model.DeliveryChannel]notifier.Channel)
+	if cfg.SMTPHost != "" {
+		channels[model.ChannelEmail] = notifier.NewSMTPChannel(cfg.SMTPHost, cfg.SMTPPort,
+			cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+	if cfg.TelegramToken != "" {
+		channels[model.ChannelTelegram] = notifier.NewTelegramChannel(cfg.TelegramAPIURL, cfg.TelegramToken, httpClient)
+	}
+	channels[model.ChannelWebhook] = notifier.NewWebhookChannel(cfg.WebhookSecret, httpClient)
+
+	opts := []notifier.OptionFunc{
+		notifier.WithLogger(logger),
+		notifier.WithInterval(time.Duration(cfg.NotifyInterval) * time.Second),
+		notifier.WithDigestHour(cfg.NotifyDigestHour),
+		notifier.WithMaxAttempts(cfg.NotifyMaxAttempts),
+	}
+	for name := range model.DeliveryChannels {
+		ch, ok := channels[name]
+		if !ok && cfg.Env != "production" {
+			ch, ok = notifier.NewFakeChannel(name, logger), true
+		}
+		if ok {
+			opts = append(opts, notifier.WithChannel(ch))
+		}
+	}
+	return opts
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	activitySeriesResp struct {
+		User   model.ShortUser     `json:"user"`
+		Points []activityPointResp `json:"points"`
+	}
+	activityPointResp struct {
+		Start     time.Time `json:"start"`
+		Commits   int       `json:"commits"`
+		Additions int       `json:"additions"`
+		Deletions int       `json:"deletions"`
+		TasksDone int       `json:"tasksDone"`
+	}
+
+	activityFlagResp struct {
+		ProjectID      int                    `json:"projectId"`
+		ProjectName    string                 `json:"projectName"`
+		DueDate        time.Time              `json:"dueDate"`
+		User           model.ShortUser        `json:"user"`
+		Kind           model.ActivityFlagKind `json:"kind"`
+		LastActivityAt *time.Time             `json:"lastActivityAt"`
+		InactiveDays   int                    `json:"inactiveDays"`
+		LastDaysShare  float64                `json:"lastDaysShare"`
+	}
+)
+
+// getActivityTimeline returns the activity series of the participants for the charts,
+// by weeks or by days with ?granularity=day
+func (s *Server) getActivityTimeline(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	granularity := model.ActivityWeek
+	if query := c.Query("granularity"); query != "" {
+		granularity = model.ActivityGranularity(strings.ToUpper(query))
+	}
+
+	timeline, err := s.svc.GetActivityTimeline(c.Request.Context(), projectID, granularity, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := make([]activitySeriesResp, 0, len(timeline))
+	for _, series := range timeline {
+		seriesResp := activitySeriesResp{
+			User:   series.ShortUser,
+			Points: make([]activityPointResp, 0, len(series.Points)),
+		}
+		for _, point := range series.Points {
+			seriesResp.Points = append(seriesResp.Points, activityPointResp{
+				Start:     point.Start,
+				Commits:   point.Commits,
+				Additions: point.Additions,
+				Deletions: point.Deletions,
+				TasksDone: point.TasksDone,
+			})
+		}
+		resp = append(resp, seriesResp)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getActivityFlags shows the inactive and the last minute students of the PM's projects
+func (s *Server) getActivityFlags(c *gin.Context) {
+	s.sendActivityFlags(c, c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+}
+
+// getAllActivityFlags is the same list for all the projects
+func (s *Server) getAllActivityFlags(c *gin.Context) {
+	s.sendActivityFlags(c, uuid.Nil)
+}
+
+func (s *Server) sendActivityFlags(c *gin.Context, ownerID uuid.UUID) {
+	inactiveDays, _ := strconv.Atoi(c.Query("inactiveDays"))
+	lastDays, _ := strconv.Atoi(c.Query("lastDays"))
+
+	flags, err := s.svc.GetActivityFlags(c.Request.Context(), ownerID, time.Now(), inactiveDays, lastDays)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := make([]activityFlagResp, 0, len(flags))
+	for _, flag := range flags {
+		flagResp := activityFlagResp{
+			ProjectID:     flag.Project.ID,
+			ProjectName:   flag.Project.Name,
+			DueDate:       flag.Project.ActiveTo,
+			User:          flag.User,
+			Kind:          flag.Kind,
+			InactiveDays:  flag.InactiveDays,
+			LastDaysShare: math.Round(flag.LastDaysShare*100) / 100,
+		}
+		if flag.LastActivityAt.Valid {
+			lastActivityAt := flag.LastActivityAt.Time
+			flagResp.LastActivityAt = &lastActivityAt
+		}
+		resp = append(resp, flagResp)
+	}
+	c.JSON(http.StatusOK, resp)
+}
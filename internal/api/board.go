@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	MoveTaskReq struct {
+		TaskID    int    `json:"taskId"`
+		Status    string `json:"status"`
+		Position  int    `json:"position"`
+		Version   int    `json:"version"`
+		ProjectID int    `json:"-"`
+	}
+
+	UpdateBoardColumnReq struct {
+		Status    string `json:"status"`
+		WIPLimit  *int   `json:"wipLimit"`
+		ProjectID int    `json:"-"`
+	}
+
+	boardResp struct {
+		ProjectID int               `json:"projectId"`
+		Columns   []boardColumnResp `json:"columns"`
+	}
+
+	boardColumnResp struct {
+		Status   string     `json:"status"`
+		WIPLimit *int       `json:"wipLimit"`
+		Count    int        `json:"count"`
+		Tasks    []TaskResp `json:"tasks"`
+	}
+)
+
+func (s *Server) getBoard(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	board, err := s.svc.GetBoard(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeBoardResponse(board))
+}
+
+func (s *Server) moveTask(c *gin.Context) {
+	moveReq := &MoveTaskReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(moveReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	moveReq.ProjectID = projectID
+
+	task, err := s.svc.MoveTask(c.Request.Context(), moveReq)
+	switch {
+	case errors.Is(err, ierr.ErrTaskVersionConflict), errors.Is(err, ierr.ErrWIPLimitExceeded):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{errField: err.Error()})
+		return
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeTaskResponse(*task))
+}
+
+func (s *Server) updateBoardColumn(c *gin.Context) {
+	columnReq := &UpdateBoardColumnReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(columnReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	columnReq.ProjectID = projectID
+
+	board, err := s.svc.UpdateBoardColumn(c.Request.Context(), columnReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeBoardResponse(board))
+}
+
+func makeBoardResponse(board *model.Board) boardResp {
+	resp := boardResp{
+		ProjectID: board.ProjectID,
+		Columns:   make([]boardColumnResp, 0, len(board.Columns)),
+	}
+	for _, column := range board.Columns {
+		columnResp := boardColumnResp{
+			Status: string(column.Status),
+			Count:  len(column.Tasks),
+			Tasks:  makeTasksResponses(column.Tasks),
+		}
+		if column.WIPLimit.Valid {
+			wipLimit := int(column.WIPLimit.Int64)
+			columnResp.WIPLimit = &wipLimit
+		}
+		resp.Columns = append(resp.Columns, columnResp)
+	}
+	return resp
+}
@@ -4,12 +4,63 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"be-project-monitoring/internal/domain"
 	"be-project-monitoring/internal/domain/model"
 )
 
+type (
+	// ChecklistReq creates or changes the checklist. Items are the items of a new checklist,
+	// ItemIDs set the order of the items of an existing one
+	ChecklistReq struct {
+		ID        int                `json:"-"`
+		ProjectID int                `json:"-"`
+		Name      string             `json:"name"`
+		Position  *int               `json:"position"`
+		Items     []ChecklistItemReq `json:"items"`
+		ItemIDs   []int              `json:"itemIds"`
+	}
+	// ChecklistItemReq is a point of the checklist, Locked is taken into account for PMs only
+	ChecklistItemReq struct {
+		ID            int        `json:"id"`
+		ChecklistID   int        `json:"-"`
+		ProjectID     int        `json:"-"`
+		UserID        uuid.UUID  `json:"-"`
+		Name          string     `json:"name"`
+		Checked       bool       `json:"checked"`
+		ParticipantID *int       `json:"participantId"`
+		DueDate       *time.Time `json:"dueDate"`
+		Locked        bool       `json:"locked"`
+	}
+
+	checklistResp struct {
+		ID       int                 `json:"id"`
+		Name     string              `json:"name"`
+		Position int                 `json:"position"`
+		Progress int                 `json:"progress"`
+		Items    []checklistItemResp `json:"items"`
+		// TemplateID is set for the checklists made of a template
+		TemplateID *int64 `json:"templateId,omitempty"`
+	}
+	checklistItemResp struct {
+		ID            int        `json:"id"`
+		ChecklistID   int        `json:"checklistId"`
+		ProjectID     int        `json:"projectId"`
+		Name          string     `json:"name"`
+		Position      int        `json:"position"`
+		Checked       bool       `json:"checked"`
+		ParticipantID *int64     `json:"participantId,omitempty"`
+		DueDate       *time.Time `json:"dueDate,omitempty"`
+		Locked        bool       `json:"locked"`
+		CheckedBy     *uuid.UUID `json:"checkedBy,omitempty"`
+		CheckedAt     *time.Time `json:"checkedAt,omitempty"`
+	}
+)
+
 func (s *Server) getProjectChecklist(c *gin.Context) {
 	projectID, err := strconv.Atoi(c.Param("projectId"))
 	if err != nil {
@@ -23,7 +74,7 @@ func (s *Server) getProjectChecklist(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, checklist)
+	c.JSON(http.StatusOK, makeChecklistItemResponses(checklist))
 }
 
 func (s *Server) addProjectChecklist(c *gin.Context) {
@@ -33,20 +84,21 @@ func (s *Server) addProjectChecklist(c *gin.Context) {
 		return
 	}
 
-	var checklist []model.Checklist
+	var checklist []ChecklistItemReq
 
 	if err := json.NewDecoder(c.Request.Body).Decode(&checklist); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
-	res, err := s.svc.AddProjectChecklist(c.Request.Context(), projectID, checklist)
+	res, err := s.svc.AddProjectChecklist(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, checklist)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, makeChecklistItemResponses(res))
 }
 
 func (s *Server) updateProjectChecklist(c *gin.Context) {
@@ -56,20 +108,22 @@ func (s *Server) updateProjectChecklist(c *gin.Context) {
 		return
 	}
 
-	checklist := &model.Checklist{}
+	checklist := &ChecklistItemReq{}
 
 	if err := json.NewDecoder(c.Request.Body).Decode(checklist); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
+	checklist.ProjectID = projectID
+	checklist.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
 
-	res, err := s.svc.UpdateProjectChecklist(c.Request.Context(), projectID, checklist)
+	res, err := s.svc.UpdateProjectChecklist(c.Request.Context(), checklist)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusOK, makeChecklistItemResponses(res))
 
 }
 func (s *Server) removeProjectChecklist(c *gin.Context) {
@@ -79,18 +133,232 @@ func (s *Server) removeProjectChecklist(c *gin.Context) {
 		return
 	}
 
-	checklist := &model.Checklist{}
+	checklist := &ChecklistItemReq{}
 
 	if err := json.NewDecoder(c.Request.Body).Decode(&checklist); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
-	res, err := s.svc.DeleteProjectChecklist(c.Request.Context(), projectID, checklist.ID)
+	res, err := s.svc.DeleteProjectChecklist(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, checklist.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistItemResponses(res))
+}
+
+func (s *Server) getChecklists(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	checklists, err := s.svc.GetChecklists(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistResponses(checklists))
+}
+
+func (s *Server) createChecklist(c *gin.Context) {
+	checklistReq := &ChecklistReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(checklistReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	checklistReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+
+	checklist, err := s.svc.CreateChecklist(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), checklistReq)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, res)
+	c.JSON(http.StatusCreated, makeChecklistResponse(*checklist))
+}
+
+func (s *Server) updateChecklist(c *gin.Context) {
+	checklistReq := &ChecklistReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(checklistReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if checklistReq.ProjectID, checklistReq.ID, err = parseChecklistParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	checklist, err := s.svc.UpdateChecklist(c.Request.Context(), checklistReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistResponse(*checklist))
+}
+
+func (s *Server) deleteChecklist(c *gin.Context) {
+	projectID, checklistID, err := parseChecklistParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteChecklist(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, checklistID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (s *Server) addChecklistItem(c *gin.Context) {
+	itemReq := &ChecklistItemReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(itemReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if itemReq.ProjectID, itemReq.ChecklistID, err = parseChecklistParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	itemReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	checklist, err := s.svc.AddChecklistItem(c.Request.Context(), itemReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeChecklistResponse(*checklist))
+}
+
+// updateChecklistItem saves the item, students can neither change nor check the locked ones
+func (s *Server) updateChecklistItem(c *gin.Context) {
+	itemReq := &ChecklistItemReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(itemReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if itemReq.ProjectID, itemReq.ChecklistID, err = parseChecklistParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if itemReq.ID, err = strconv.Atoi(c.Param("itemId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	itemReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	checklist, err := s.svc.UpdateChecklistItem(c.Request.Context(), itemReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistResponse(*checklist))
+}
+
+func (s *Server) deleteChecklistItem(c *gin.Context) {
+	projectID, checklistID, err := parseChecklistParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	checklist, err := s.svc.DeleteChecklistItem(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, checklistID, itemID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistResponse(*checklist))
+}
+
+func parseChecklistParams(c *gin.Context) (int, int, error) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	checklistID, err := strconv.Atoi(c.Param("checklistId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return projectID, checklistID, nil
+}
+
+func makeChecklistResponses(checklists []model.Checklist) []checklistResp {
+	res := make([]checklistResp, 0, len(checklists))
+	for _, checklist := range checklists {
+		res = append(res, makeChecklistResponse(checklist))
+	}
+	return res
+}
+
+func makeChecklistResponse(checklist model.Checklist) checklistResp {
+	resp := checklistResp{
+		ID:       checklist.ID,
+		Name:     checklist.Name,
+		Position: checklist.Position,
+		Progress: checklist.Progress().Percent(),
+		Items:    makeChecklistItemResponses(checklist.Items),
+	}
+	if checklist.TemplateID.Valid {
+		templateID := checklist.TemplateID.Int64
+		resp.TemplateID = &templateID
+	}
+	return resp
+}
+
+func makeChecklistItemResponses(items []model.ChecklistItem) []checklistItemResp {
+	res := make([]checklistItemResp, 0, len(items))
+	for _, item := range items {
+		itemResp := checklistItemResp{
+			ID:          item.ID,
+			ChecklistID: item.ChecklistID,
+			ProjectID:   item.ProjectID,
+			Name:        item.Name,
+			Position:    item.Position,
+			Checked:     item.Checked,
+			Locked:      item.Locked,
+		}
+		if item.ParticipantID.Valid {
+			participantID := item.ParticipantID.Int64
+			itemResp.ParticipantID = &participantID
+		}
+		if item.DueDate.Valid {
+			dueDate := item.DueDate.Time
+			itemResp.DueDate = &dueDate
+		}
+		if item.CheckedBy.Valid {
+			checkedBy := item.CheckedBy.UUID
+			itemResp.CheckedBy = &checkedBy
+		}
+		if item.CheckedAt.Valid {
+			checkedAt := item.CheckedAt.Time
+			itemResp.CheckedAt = &checkedAt
+		}
+		res = append(res, itemResp)
+	}
+	return res
 }
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	// ChecklistTemplateReq creates or replaces the template, with Propagate set
+	// the changes are applied to the checklists made of the template
+	ChecklistTemplateReq struct {
+		ID        int                        `json:"-"`
+		OwnerID   uuid.UUID                  `json:"-"`
+		Name      string                     `json:"name"`
+		Course    string                     `json:"course"`
+		Items     []ChecklistTemplateItemReq `json:"items"`
+		Propagate bool                       `json:"propagate"`
+	}
+	// ChecklistTemplateItemReq without ID adds a new item to the template
+	ChecklistTemplateItemReq struct {
+		ID      int        `json:"id"`
+		Name    string     `json:"name"`
+		Locked  bool       `json:"locked"`
+		DueDate *time.Time `json:"dueDate"`
+	}
+	// ApplyChecklistTemplateReq selects the projects either by ids or by the course,
+	// the course of the template is used if neither is given
+	ApplyChecklistTemplateReq struct {
+		UserID     uuid.UUID `json:"-"`
+		TemplateID int       `json:"-"`
+		Course     string    `json:"course"`
+		ProjectIDs []int     `json:"projectIds"`
+	}
+
+	checklistTemplateResp struct {
+		ID        int                         `json:"id"`
+		Name      string                      `json:"name"`
+		Course    string                      `json:"course"`
+		Items     []checklistTemplateItemResp `json:"items"`
+		CreatedAt time.Time                   `json:"createdAt"`
+		UpdatedAt time.Time                   `json:"updatedAt"`
+	}
+	checklistTemplateItemResp struct {
+		ID      int        `json:"id"`
+		Name    string     `json:"name"`
+		Locked  bool       `json:"locked"`
+		DueDate *time.Time `json:"dueDate,omitempty"`
+	}
+	templateApplicationResp struct {
+		Applied []int `json:"applied"`
+		Skipped []int `json:"skipped"`
+	}
+)
+
+func (s *Server) getChecklistTemplates(c *gin.Context) {
+	templates, err := s.svc.GetChecklistTemplates(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]checklistTemplateResp, 0, len(templates))
+	for _, template := range templates {
+		res = append(res, makeChecklistTemplateResponse(template))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) getChecklistTemplate(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("templateId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	template, err := s.svc.GetChecklistTemplate(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), templateID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistTemplateResponse(*template))
+}
+
+func (s *Server) createChecklistTemplate(c *gin.Context) {
+	templateReq := &ChecklistTemplateReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(templateReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	templateReq.OwnerID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	template, err := s.svc.CreateChecklistTemplate(c.Request.Context(), templateReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeChecklistTemplateResponse(*template))
+}
+
+func (s *Server) updateChecklistTemplate(c *gin.Context) {
+	templateReq := &ChecklistTemplateReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(templateReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if templateReq.ID, err = strconv.Atoi(c.Param("templateId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	templateReq.OwnerID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	template, err := s.svc.UpdateChecklistTemplate(c.Request.Context(), templateReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeChecklistTemplateResponse(*template))
+}
+
+func (s *Server) deleteChecklistTemplate(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("templateId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteChecklistTemplate(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), templateID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// applyChecklistTemplate adds the checklist made of the template to the course or the selected projects
+func (s *Server) applyChecklistTemplate(c *gin.Context) {
+	applyReq := &ApplyChecklistTemplateReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(applyReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if applyReq.TemplateID, err = strconv.Atoi(c.Param("templateId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	applyReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	application, err := s.svc.ApplyChecklistTemplate(c.Request.Context(), applyReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, templateApplicationResp{
+		Applied: application.Applied,
+		Skipped: application.Skipped,
+	})
+}
+
+func makeChecklistTemplateResponse(template model.ChecklistTemplate) checklistTemplateResp {
+	resp := checklistTemplateResp{
+		ID:        template.ID,
+		Name:      template.Name,
+		Course:    template.Course.String,
+		Items:     make([]checklistTemplateItemResp, 0, len(template.Items)),
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+	for _, item := range template.Items {
+		itemResp := checklistTemplateItemResp{
+			ID:     item.ID,
+			Name:   item.Name,
+			Locked: item.Locked,
+		}
+		if item.DueDate.Valid {
+			dueDate := item.DueDate.Time
+			itemResp.DueDate = &dueDate
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+	return resp
+}
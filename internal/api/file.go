@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxUploadBodySize limits the multipart body, the file size is checked by its kind in the service
+const maxUploadBodySize = 64 << 20
+
+type (
+	UploadFileReq struct {
+		Name      string
+		Size      int64
+		Content   io.Reader
+		ProjectID int
+		TaskID    int
+	}
+	fileResp struct {
+		ID          int       `json:"id"`
+		Name        string    `json:"name"`
+		ContentType string    `json:"contentType"`
+		Size        int64     `json:"size"`
+		Kind        string    `json:"kind"`
+		URL         string    `json:"url"`
+		TaskID      int       `json:"taskId,omitempty"`
+		CreatedAt   time.Time `json:"createdAt"`
+	}
+	fileURLResp struct {
+		URL string `json:"url"`
+	}
+)
+
+func (s *Server) uploadProjectAvatar(c *gin.Context) {
+	s.uploadFile(c, s.svc.UploadProjectAvatar)
+}
+
+func (s *Server) uploadTaskAttachment(c *gin.Context) {
+	s.uploadFile(c, s.svc.UploadTaskAttachment)
+}
+
+func (s *Server) getTaskAttachments(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	files, err := s.svc.GetTaskAttachments(c.Request.Context(), projectID, taskID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]fileResp, 0, len(files))
+	for _, file := range files {
+		res = append(res, makeFileResponse(file))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) deleteTaskAttachment(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	fileID, err := strconv.Atoi(c.Param("fileId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteTaskAttachment(c.Request.Context(), projectID, taskID, fileID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// downloadFile redirects to a short-lived signed url of the file
+func (s *Server) downloadFile(c *gin.Context) {
+	url, ok := s.getSignedFileURL(c)
+	if !ok {
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+func (s *Server) getFileURL(c *gin.Context) {
+	url, ok := s.getSignedFileURL(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, fileURLResp{URL: url})
+}
+
+func (s *Server) getSignedFileURL(c *gin.Context) (string, bool) {
+	fileID, err := strconv.Atoi(c.Param("fileId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return "", false
+	}
+
+	url, err := s.svc.GetFileURL(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), fileID)
+	switch {
+	case errors.Is(err, ierr.ErrFileNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{errField: err.Error()})
+		return "", false
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return "", false
+	}
+	return url, true
+}
+
+// uploadFile reads the "file" field of the multipart form and passes it to upload
+func (s *Server) uploadFile(c *gin.Context, upload func(ctx context.Context, fileReq *UploadFileReq) (*model.File, error)) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	var taskID int
+	if c.Param("taskId") != "" {
+		if taskID, err = strconv.Atoi(c.Param("taskId")); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+			return
+		}
+	}
+
+	fileReq, closeFile, err := s.parseFormFile(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	defer closeFile()
+	fileReq.ProjectID = projectID
+	fileReq.TaskID = taskID
+
+	file, err := upload(c.Request.Context(), fileReq)
+	if err != nil {
+		abortWithUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeFileResponse(*file))
+}
+
+// parseFormFile opens the "file" field of the multipart form, the returned func closes it
+func (s *Server) parseFormFile(c *gin.Context) (*UploadFileReq, func(), error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBodySize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := header.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &UploadFileReq{
+		Name:    header.Filename,
+		Size:    header.Size,
+		Content: content,
+	}, func() {
+		if err := content.Close(); err != nil {
+			s.logger.Errorf("failed to close uploaded file: %v", err)
+		}
+	}, nil
+}
+
+func abortWithUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ierr.ErrFileTooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{errField: err.Error()})
+	case errors.Is(err, ierr.ErrFileTypeNotAllowed):
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{errField: err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+	}
+}
+
+func makeFileResponse(file model.File) fileResp {
+	return fileResp{
+		ID:          file.ID,
+		Name:        file.Name,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		Kind:        string(file.Kind),
+		URL:         FileURL(file.ID),
+		TaskID:      int(file.TaskID.Int64),
+		CreatedAt:   file.CreatedAt,
+	}
+}
+
+// FileURL is the stable url of the file, it redirects to a signed download url
+func FileURL(id int) string {
+	return fmt.Sprintf("/api/files/%d", id)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type githubSyncResp struct {
+	RepositoryID  int              `json:"repositoryId"`
+	RepositoryURL string           `json:"repositoryUrl"`
+	ProjectID     int              `json:"projectId"`
+	ProjectName   string           `json:"projectName,omitempty"`
+	Status        model.SyncStatus `json:"status"`
+	Attempts      int              `json:"attempts"`
+	NextSyncAt    time.Time        `json:"nextSyncAt"`
+	AttemptedAt   *time.Time       `json:"attemptedAt,omitempty"`
+	SyncedAt      *time.Time       `json:"syncedAt,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+// getGithubSyncs returns the state of the statistics of every repository of the project
+// which /commits and /report are built of
+func (s *Server) getGithubSyncs(c *gin.Context) {
+	syncs, err := s.svc.GetGithubSyncs(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGithubSyncResponses(syncs))
+}
+
+// resyncGithubStats syncs the statistics of the repositories of the project right away
+func (s *Server) resyncGithubStats(c *gin.Context) {
+	syncs, err := s.svc.ResyncGithubStats(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGithubSyncResponses(syncs))
+}
+
+func (s *Server) getAllGithubSyncs(c *gin.Context) {
+	syncs, err := s.svc.GetAllGithubSyncs(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGithubSyncResponses(syncs))
+}
+
+func makeGithubSyncResponses(syncs []model.GithubSync) []githubSyncResp {
+	resp := make([]githubSyncResp, 0, len(syncs))
+	for _, sync := range syncs {
+		resp = append(resp, makeGithubSyncResponse(sync))
+	}
+	return resp
+}
+
+func makeGithubSyncResponse(sync model.GithubSync) githubSyncResp {
+	resp := githubSyncResp{
+		RepositoryID:  sync.RepositoryID,
+		RepositoryURL: sync.RepositoryURL,
+		ProjectID:     sync.ProjectID,
+		ProjectName:   sync.ProjectName,
+		Status:        sync.Status,
+		Attempts:      sync.Attempts,
+		NextSyncAt:    sync.NextSyncAt,
+		Error:         sync.Error.String,
+	}
+	if sync.AttemptedAt.Valid {
+		resp.AttemptedAt = &sync.AttemptedAt.Time
+	}
+	if sync.SyncedAt.Valid {
+		resp.SyncedAt = &sync.SyncedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,353 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	RubricReq struct {
+		ID       int            `json:"-"`
+		OwnerID  uuid.UUID      `json:"-"`
+		Name     string         `json:"name"`
+		Course   string         `json:"course"`
+		Criteria []CriterionReq `json:"criteria"`
+	}
+	// CriterionReq without ID adds a new criterion to the rubric
+	CriterionReq struct {
+		ID           int     `json:"id"`
+		Name         string  `json:"name"`
+		Weight       float64 `json:"weight"`
+		MaxPoints    float64 `json:"maxPoints"`
+		Scope        string  `json:"scope"`
+		Metric       string  `json:"metric"`
+		MetricTarget float64 `json:"metricTarget"`
+	}
+	UpdateGradesReq struct {
+		UserID    uuid.UUID  `json:"-"`
+		RubricID  int        `json:"-"`
+		ProjectID int        `json:"-"`
+		Grades    []GradeReq `json:"grades"`
+	}
+	// GradeReq sets the points of the project criterion, or of the participant if ParticipantID is set
+	GradeReq struct {
+		CriterionID   int     `json:"criterionId"`
+		ParticipantID int     `json:"participantId"`
+		Points        float64 `json:"points"`
+		Comment       string  `json:"comment"`
+	}
+	GradeBookReq struct {
+		UserID   uuid.UUID
+		RubricID int
+		Course   string
+		Group    string
+		Format   model.GradeBookFormat
+	}
+
+	rubricResp struct {
+		ID        int             `json:"id"`
+		Name      string          `json:"name"`
+		Course    string          `json:"course"`
+		Criteria  []criterionResp `json:"criteria"`
+		CreatedAt time.Time       `json:"createdAt"`
+	}
+	criterionResp struct {
+		ID           int     `json:"id"`
+		Name         string  `json:"name"`
+		Weight       float64 `json:"weight"`
+		MaxPoints    float64 `json:"maxPoints"`
+		Scope        string  `json:"scope"`
+		Metric       string  `json:"metric"`
+		MetricTarget float64 `json:"metricTarget"`
+	}
+	gradeResp struct {
+		CriterionID   int        `json:"criterionId"`
+		ParticipantID int        `json:"participantId,omitempty"`
+		Points        float64    `json:"points"`
+		Comment       string     `json:"comment"`
+		Auto          bool       `json:"auto"`
+		GradedBy      *uuid.UUID `json:"gradedBy,omitempty"`
+		UpdatedAt     time.Time  `json:"updatedAt"`
+	}
+	gradeBookResp struct {
+		Rubric rubricResp         `json:"rubric"`
+		Rows   []gradeBookRowResp `json:"rows"`
+	}
+	gradeBookRowResp struct {
+		ProjectID   int             `json:"projectId"`
+		ProjectName string          `json:"projectName"`
+		Participant ParticipantResp `json:"participant"`
+		Points      map[int]float64 `json:"points"`
+		Total       float64         `json:"total"`
+	}
+)
+
+func (s *Server) getRubrics(c *gin.Context) {
+	rubrics, err := s.svc.GetRubrics(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]rubricResp, 0, len(rubrics))
+	for _, rubric := range rubrics {
+		res = append(res, makeRubricResponse(rubric))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) getRubric(c *gin.Context) {
+	rubricID, err := strconv.Atoi(c.Param("rubricId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	rubric, err := s.svc.GetRubric(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), rubricID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeRubricResponse(*rubric))
+}
+
+func (s *Server) createRubric(c *gin.Context) {
+	rubricReq := &RubricReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(rubricReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	rubricReq.OwnerID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	rubric, err := s.svc.CreateRubric(c.Request.Context(), rubricReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeRubricResponse(*rubric))
+}
+
+// updateRubric replaces the rubric criteria: the ones with ID are updated, the ones
+// without ID are added and the missing ones are deleted with their grades
+func (s *Server) updateRubric(c *gin.Context) {
+	rubricReq := &RubricReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(rubricReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if rubricReq.ID, err = strconv.Atoi(c.Param("rubricId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	rubricReq.OwnerID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	rubric, err := s.svc.UpdateRubric(c.Request.Context(), rubricReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeRubricResponse(*rubric))
+}
+
+func (s *Server) deleteRubric(c *gin.Context) {
+	rubricID, err := strconv.Atoi(c.Param("rubricId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteRubric(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), rubricID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (s *Server) getProjectGrades(c *gin.Context) {
+	rubricID, projectID, err := parseRubricProjectParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	grades, err := s.svc.GetProjectGrades(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), rubricID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGradesResponse(grades))
+}
+
+func (s *Server) updateProjectGrades(c *gin.Context) {
+	gradesReq := &UpdateGradesReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(gradesReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if gradesReq.RubricID, gradesReq.ProjectID, err = parseRubricProjectParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	gradesReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	grades, err := s.svc.UpdateProjectGrades(c.Request.Context(), gradesReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGradesResponse(grades))
+}
+
+// autofillGrades fills the criteria bound to a metric, the points put by hand are kept
+func (s *Server) autofillGrades(c *gin.Context) {
+	rubricID, projectID, err := parseRubricProjectParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	grades, err := s.svc.AutofillGrades(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), rubricID, projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeGradesResponse(grades))
+}
+
+// getGradeBook returns the grade book of the rubric as json, or as a base64 encoded file
+// if the "format" query parameter is set. The projects are taken from the "course"
+// query parameter (the rubric course by default), the students can be filtered by "group".
+func (s *Server) getGradeBook(c *gin.Context) {
+	rubricID, err := strconv.Atoi(c.Param("rubricId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	bookReq := &GradeBookReq{
+		UserID:   c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		RubricID: rubricID,
+		Course:   c.Query("course"),
+		Group:    c.Query("group"),
+		Format:   model.GradeBookFormat(c.Query("format")),
+	}
+
+	if bookReq.Format != "" {
+		data, err := s.svc.ExportGradeBook(c.Request.Context(), bookReq)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, struct {
+			Data string `json:"data"`
+			Name string `json:"name"`
+		}{
+			Data: base64.StdEncoding.EncodeToString(data),
+			Name: fmt.Sprintf("gradebook-%v.%s", rubricID, bookReq.Format),
+		})
+		return
+	}
+
+	book, err := s.svc.GetGradeBook(c.Request.Context(), bookReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := gradeBookResp{
+		Rubric: makeRubricResponse(book.Rubric),
+		Rows:   make([]gradeBookRowResp, 0, len(book.Rows)),
+	}
+	for _, row := range book.Rows {
+		resp.Rows = append(resp.Rows, gradeBookRowResp{
+			ProjectID:   row.Project.ID,
+			ProjectName: row.Project.Name,
+			Participant: ParticipantResp{
+				ID:        row.Participant.ID,
+				Role:      string(row.Participant.Role),
+				ProjectID: row.Project.ID,
+				User:      row.Participant.ShortUser,
+			},
+			Points: row.Points,
+			Total:  row.Total,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseRubricProjectParams(c *gin.Context) (int, int, error) {
+	rubricID, err := strconv.Atoi(c.Param("rubricId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return rubricID, projectID, nil
+}
+
+func makeRubricResponse(rubric model.Rubric) rubricResp {
+	resp := rubricResp{
+		ID:        rubric.ID,
+		Name:      rubric.Name,
+		Course:    rubric.Course.String,
+		Criteria:  make([]criterionResp, 0, len(rubric.Criteria)),
+		CreatedAt: rubric.CreatedAt,
+	}
+	for _, criterion := range rubric.Criteria {
+		resp.Criteria = append(resp.Criteria, criterionResp{
+			ID:           criterion.ID,
+			Name:         criterion.Name,
+			Weight:       criterion.Weight,
+			MaxPoints:    criterion.MaxPoints,
+			Scope:        string(criterion.Scope),
+			Metric:       string(criterion.Metric),
+			MetricTarget: criterion.MetricTarget,
+		})
+	}
+	return resp
+}
+
+func makeGradesResponse(grades []model.Grade) []gradeResp {
+	res := make([]gradeResp, 0, len(grades))
+	for _, grade := range grades {
+		resp := gradeResp{
+			CriterionID:   grade.CriterionID,
+			ParticipantID: int(grade.ParticipantID.Int64),
+			Points:        grade.Points,
+			Comment:       grade.Comment.String,
+			Auto:          grade.Auto,
+			UpdatedAt:     grade.UpdatedAt,
+		}
+		if grade.GradedBy.Valid {
+			resp.GradedBy = &grade.GradedBy.UUID
+		}
+		res = append(res, resp)
+	}
+	return res
+}
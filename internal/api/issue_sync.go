@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// IssueSyncReq turns the sync of the project tasks with the issues of the repository on or off
+	IssueSyncReq struct {
+		ProjectID    int  `json:"-"`
+		RepositoryID int  `json:"repositoryId"`
+		Enabled      bool `json:"enabled"`
+	}
+
+	GetIssueSyncLogReq struct {
+		ProjectID int
+		TaskID    int
+		Offset    int
+		Limit     int
+	}
+
+	issueSyncResp struct {
+		RepositoryID  int        `json:"repositoryId"`
+		RepositoryURL string     `json:"repositoryUrl"`
+		Enabled       bool       `json:"enabled"`
+		SyncedAt      *time.Time `json:"syncedAt"`
+	}
+
+	issueSyncEntryResp struct {
+		ID        int                      `json:"id,omitempty"`
+		TaskID    *int64                   `json:"taskId,omitempty"`
+		Number    int                      `json:"number,omitempty"`
+		Direction model.IssueSyncDirection `json:"direction"`
+		Action    model.IssueSyncAction    `json:"action"`
+		Conflict  bool                     `json:"conflict"`
+		Message   string                   `json:"message"`
+		CreatedAt time.Time                `json:"createdAt"`
+	}
+
+	issueSyncRunResp struct {
+		DryRun  bool                 `json:"dryRun"`
+		Entries []issueSyncEntryResp `json:"entries"`
+	}
+)
+
+func (s *Server) getIssueSync(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	sync, err := s.svc.GetIssueSync(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeIssueSyncResponse(*sync))
+}
+
+func (s *Server) setIssueSync(c *gin.Context) {
+	syncReq := &IssueSyncReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(syncReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	syncReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+
+	sync, err := s.svc.SetIssueSync(c.Request.Context(), syncReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeIssueSyncResponse(*sync))
+}
+
+// runIssueSync syncs the tasks with the issues right away, with ?dryRun=true it only
+// returns the changes the sync would make
+func (s *Server) runIssueSync(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	entries, err := s.svc.SyncIssues(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int), dryRun)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, issueSyncRunResp{
+		DryRun:  dryRun,
+		Entries: makeIssueSyncEntryResponses(entries),
+	})
+}
+
+func (s *Server) getIssueSyncLog(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	logReq := &GetIssueSyncLogReq{ProjectID: projectID}
+	logReq.TaskID, _ = strconv.Atoi(c.Query("taskId"))
+	logReq.Offset, _ = strconv.Atoi(c.Query("offset"))
+	logReq.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	entries, err := s.svc.GetIssueSyncLog(c.Request.Context(), logReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeIssueSyncEntryResponses(entries))
+}
+
+func makeIssueSyncResponse(sync model.IssueSync) issueSyncResp {
+	resp := issueSyncResp{
+		RepositoryID:  sync.RepositoryID,
+		RepositoryURL: sync.RepositoryURL,
+		Enabled:       sync.Enabled,
+	}
+	if sync.SyncedAt.Valid {
+		resp.SyncedAt = &sync.SyncedAt.Time
+	}
+	return resp
+}
+
+func makeIssueSyncEntryResponses(entries []model.IssueSyncEntry) []issueSyncEntryResp {
+	resp := make([]issueSyncEntryResp, 0, len(entries))
+	for _, entry := range entries {
+		entryResp := issueSyncEntryResp{
+			ID:        entry.ID,
+			Number:    entry.Number,
+			Direction: entry.Direction,
+			Action:    entry.Action,
+			Conflict:  entry.Conflict,
+			Message:   entry.Message,
+			CreatedAt: entry.CreatedAt,
+		}
+		if entry.TaskID.Valid {
+			entryResp.TaskID = &entry.TaskID.Int64
+		}
+		resp = append(resp, entryResp)
+	}
+	return resp
+}
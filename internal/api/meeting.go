@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	// MeetingReq creates or replaces the meeting record, Attendees are the ids
+	// of the participants who were present and Kind is MEETING by default
+	MeetingReq struct {
+		ID          int             `json:"-"`
+		ProjectID   int             `json:"-"`
+		UserID      uuid.UUID       `json:"-"`
+		Kind        string          `json:"kind"`
+		Title       string          `json:"title"`
+		HeldAt      time.Time       `json:"heldAt"`
+		Notes       string          `json:"notes"`
+		Decisions   string          `json:"decisions"`
+		Attendees   []int           `json:"attendees"`
+		ActionItems []ActionItemReq `json:"actionItems"`
+	}
+	// ActionItemReq without an ID is a new item, it becomes a task of the project
+	ActionItemReq struct {
+		ID            int        `json:"id"`
+		Text          string     `json:"text"`
+		ParticipantID *int       `json:"participantId"`
+		DueDate       *time.Time `json:"dueDate"`
+	}
+
+	meetingResp struct {
+		ID          int                   `json:"id"`
+		Kind        string                `json:"kind"`
+		Title       string                `json:"title"`
+		HeldAt      time.Time             `json:"heldAt"`
+		Notes       string                `json:"notes"`
+		Decisions   string                `json:"decisions"`
+		CreatedBy   *uuid.UUID            `json:"createdBy,omitempty"`
+		CreatedAt   time.Time             `json:"createdAt"`
+		UpdatedAt   time.Time             `json:"updatedAt"`
+		Attendees   []meetingAttendeeResp `json:"attendees"`
+		ActionItems []actionItemResp      `json:"actionItems"`
+	}
+	meetingAttendeeResp struct {
+		ParticipantID int  `json:"participantId"`
+		Present       bool `json:"present"`
+	}
+	actionItemResp struct {
+		ID            int        `json:"id"`
+		Text          string     `json:"text"`
+		ParticipantID *int64     `json:"participantId,omitempty"`
+		DueDate       *time.Time `json:"dueDate,omitempty"`
+		TaskID        *int64     `json:"taskId,omitempty"`
+	}
+	attendanceResp struct {
+		Participant ParticipantResp `json:"participant"`
+		Meetings    int             `json:"meetings"`
+		Attended    int             `json:"attended"`
+	}
+)
+
+func (s *Server) getMeetings(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	meetings, err := s.svc.GetMeetings(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]meetingResp, 0, len(meetings))
+	for _, meeting := range meetings {
+		res = append(res, makeMeetingResponse(meeting))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) getMeeting(c *gin.Context) {
+	projectID, meetingID, err := parseMeetingParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	meeting, err := s.svc.GetMeeting(c.Request.Context(), projectID, meetingID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeMeetingResponse(*meeting))
+}
+
+func (s *Server) createMeeting(c *gin.Context) {
+	meetingReq := &MeetingReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(meetingReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	meetingReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	meetingReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	meeting, err := s.svc.CreateMeeting(c.Request.Context(), meetingReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeMeetingResponse(*meeting))
+}
+
+func (s *Server) updateMeeting(c *gin.Context) {
+	meetingReq := &MeetingReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(meetingReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if meetingReq.ProjectID, meetingReq.ID, err = parseMeetingParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	meetingReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	meeting, err := s.svc.UpdateMeeting(c.Request.Context(), meetingReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeMeetingResponse(*meeting))
+}
+
+func (s *Server) deleteMeeting(c *gin.Context) {
+	projectID, meetingID, err := parseMeetingParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteMeeting(c.Request.Context(), projectID, meetingID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// getMeetingAttendance returns how many meetings every participant has attended
+func (s *Server) getMeetingAttendance(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	attendance, err := s.svc.GetMeetingAttendance(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]attendanceResp, 0, len(attendance))
+	for _, a := range attendance {
+		res = append(res, attendanceResp{
+			Participant: makePeerParticipantResponse(a.Participant),
+			Meetings:    a.Meetings,
+			Attended:    a.Attended,
+		})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func parseMeetingParams(c *gin.Context) (int, int, error) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	meetingID, err := strconv.Atoi(c.Param("meetingId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return projectID, meetingID, nil
+}
+
+func makeMeetingResponse(meeting model.Meeting) meetingResp {
+	resp := meetingResp{
+		ID:          meeting.ID,
+		Kind:        string(meeting.Kind),
+		Title:       meeting.Title,
+		HeldAt:      meeting.HeldAt,
+		Notes:       meeting.Notes,
+		Decisions:   meeting.Decisions,
+		CreatedAt:   meeting.CreatedAt,
+		UpdatedAt:   meeting.UpdatedAt,
+		Attendees:   make([]meetingAttendeeResp, 0, len(meeting.Attendees)),
+		ActionItems: make([]actionItemResp, 0, len(meeting.ActionItems)),
+	}
+	if meeting.CreatedBy.Valid {
+		resp.CreatedBy = &meeting.CreatedBy.UUID
+	}
+	for _, attendee := range meeting.Attendees {
+		resp.Attendees = append(resp.Attendees, meetingAttendeeResp{
+			ParticipantID: attendee.ParticipantID,
+			Present:       attendee.Present,
+		})
+	}
+	for _, item := range meeting.ActionItems {
+		itemResp := actionItemResp{ID: item.ID, Text: item.Text}
+		if item.ParticipantID.Valid {
+			participantID := item.ParticipantID.Int64
+			itemResp.ParticipantID = &participantID
+		}
+		if item.DueDate.Valid {
+			dueDate := item.DueDate.Time
+			itemResp.DueDate = &dueDate
+		}
+		if item.TaskID.Valid {
+			taskID := item.TaskID.Int64
+			itemResp.TaskID = &taskID
+		}
+		resp.ActionItems = append(resp.ActionItems, itemResp)
+	}
+	return resp
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
@@ -34,6 +35,7 @@ func (s *Server) authMiddleware(toAllow ...model.UserRole) func(c *gin.Context)
 		}
 
 		c.Set(string(domain.UserIDCtx), id)
+		c.Request = c.Request.WithContext(context.WithValue(ctx, domain.UserIDCtx, id))
 	}
 }
 
@@ -85,6 +87,14 @@ func (s *Server) verifyParticipantRoleMiddleware(toAllow ...model.ParticipantRol
 		}
 	}
 }
+func (s *Server) parseProjectIDParam(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	c.Set(string(domain.ProjectIDCtx), projectID)
+}
 func getTokenFromHeader(tokenHeader string) (string, error) {
 	if tokenHeader == "" {
 		return "", ierr.ErrTokenHeaderIsEmpty
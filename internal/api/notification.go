@@ -0,0 +1,197 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	GetNotificationsReq struct {
+		UserID uuid.UUID
+		Unread bool
+		Offset int
+		Limit  int
+	}
+
+	notificationsResp struct {
+		Notifications []model.Notification `json:"notifications"`
+		Unread        int                  `json:"unread"`
+	}
+)
+
+func (s *Server) getNotifications(c *gin.Context) {
+	notificationReq := &GetNotificationsReq{
+		UserID: c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+	}
+	notificationReq.Unread, _ = strconv.ParseBool(c.Query("unread"))
+	notificationReq.Offset, _ = strconv.Atoi(c.Query("offset"))
+	notificationReq.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	notifications, unread, err := s.svc.GetNotifications(c.Request.Context(), notificationReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationsResp{
+		Notifications: notifications,
+		Unread:        unread,
+	})
+}
+
+func (s *Server) getUnreadNotificationCount(c *gin.Context) {
+	unread, err := s.svc.GetUnreadNotificationCount(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		Unread int `json:"unread"`
+	}{
+		Unread: unread,
+	})
+}
+
+// markNotificationsRead marks the listed notifications as read, all of them if the list is empty
+func (s *Server) markNotificationsRead(c *gin.Context) {
+	readReq := &struct {
+		IDs []int `json:"ids"`
+	}{}
+	if err := json.NewDecoder(c.Request.Body).Decode(readReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	userID := c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+	if err := s.svc.MarkNotificationsRead(c.Request.Context(), userID, readReq.IDs); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	s.getUnreadNotificationCount(c)
+}
+
+func (s *Server) getNotificationPreferences(c *gin.Context) {
+	preferences, err := s.svc.GetNotificationPreferences(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (s *Server) updateNotificationPreferences(c *gin.Context) {
+	var preferences []model.NotificationPreference
+	if err := json.NewDecoder(c.Request.Body).Decode(&preferences); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res, err := s.svc.UpdateNotificationPreferences(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), preferences)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) getUserChannels(c *gin.Context) {
+	channels, err := s.svc.GetUserChannels(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, channels)
+}
+
+func (s *Server) updateUserChannels(c *gin.Context) {
+	var channels []model.UserChannel
+	if err := json.NewDecoder(c.Request.Body).Decode(&channels); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res, err := s.svc.UpdateUserChannels(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), channels)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) getTaskWatchers(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	watchers, err := s.svc.GetTaskWatchers(c.Request.Context(), projectID, taskID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, watchers)
+}
+
+func (s *Server) watchTask(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.WatchTask(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		projectID, taskID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	s.getTaskWatchers(c)
+}
+
+func (s *Server) unwatchTask(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.UnwatchTask(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		projectID, taskID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	s.getTaskWatchers(c)
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	// PeerRoundReq opens a round, MaxScore is 5 by default and ClosesAt is optional
+	PeerRoundReq struct {
+		ProjectID int        `json:"-"`
+		UserID    uuid.UUID  `json:"-"`
+		Name      string     `json:"name"`
+		Criteria  []string   `json:"criteria"`
+		MaxScore  int        `json:"maxScore"`
+		ClosesAt  *time.Time `json:"closesAt"`
+	}
+	SubmitPeerReviewsReq struct {
+		UserID    uuid.UUID       `json:"-"`
+		ProjectID int             `json:"-"`
+		RoundID   int             `json:"-"`
+		Reviews   []PeerReviewReq `json:"reviews"`
+	}
+	// PeerReviewReq has a score for every criterion of the round, in the same order
+	PeerReviewReq struct {
+		RevieweeID int     `json:"revieweeId"`
+		Scores     []int64 `json:"scores"`
+		Comment    string  `json:"comment"`
+	}
+
+	peerRoundResp struct {
+		ID       int        `json:"id"`
+		Name     string     `json:"name"`
+		Criteria []string   `json:"criteria"`
+		MaxScore int        `json:"maxScore"`
+		OpenedAt time.Time  `json:"openedAt"`
+		ClosesAt *time.Time `json:"closesAt,omitempty"`
+		ClosedAt *time.Time `json:"closedAt,omitempty"`
+		Open     bool       `json:"open"`
+	}
+	peerReviewResp struct {
+		RevieweeID  int       `json:"revieweeId"`
+		Scores      []int64   `json:"scores"`
+		Comment     string    `json:"comment"`
+		SubmittedAt time.Time `json:"submittedAt"`
+	}
+	peerAssignmentResp struct {
+		Round     peerRoundResp     `json:"round"`
+		Teammates []ParticipantResp `json:"teammates"`
+		Reviews   []peerReviewResp  `json:"reviews"`
+	}
+	peerCompletionResp struct {
+		Participant ParticipantResp `json:"participant"`
+		Done        int             `json:"done"`
+		Expected    int             `json:"expected"`
+	}
+	peerResultResp struct {
+		Participant ParticipantResp `json:"participant"`
+		Reviews     int             `json:"reviews"`
+		Outliers    int             `json:"outliers"`
+		Means       []float64       `json:"means"`
+		Mean        float64         `json:"mean"`
+		RawMean     float64         `json:"rawMean"`
+		Comments    []string        `json:"comments"`
+	}
+	peerRoundResultsResp struct {
+		Round      peerRoundResp        `json:"round"`
+		Completion []peerCompletionResp `json:"completion"`
+		Results    []peerResultResp     `json:"results"`
+	}
+)
+
+func (s *Server) openPeerRound(c *gin.Context) {
+	roundReq := &PeerRoundReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(roundReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	roundReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	roundReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	round, err := s.svc.OpenPeerRound(c.Request.Context(), roundReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makePeerRoundResponse(*round))
+}
+
+func (s *Server) getPeerRounds(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	rounds, err := s.svc.GetPeerRounds(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]peerRoundResp, 0, len(rounds))
+	for _, round := range rounds {
+		res = append(res, makePeerRoundResponse(round))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) closePeerRound(c *gin.Context) {
+	roundID, err := strconv.Atoi(c.Param("roundId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	round, err := s.svc.ClosePeerRound(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int), roundID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makePeerRoundResponse(*round))
+}
+
+// getPeerAssignment returns the teammates to rate and the user's own reviews in the round
+func (s *Server) getPeerAssignment(c *gin.Context) {
+	projectID, roundID, err := parsePeerRoundParams(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	assignment, err := s.svc.GetPeerAssignment(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, roundID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makePeerAssignmentResponse(assignment))
+}
+
+func (s *Server) submitPeerReviews(c *gin.Context) {
+	reviewsReq := &SubmitPeerReviewsReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(reviewsReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if reviewsReq.ProjectID, reviewsReq.RoundID, err = parsePeerRoundParams(c); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	reviewsReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	assignment, err := s.svc.SubmitPeerReviews(c.Request.Context(), reviewsReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makePeerAssignmentResponse(assignment))
+}
+
+// getPeerRoundResults shows the PM the completion of the round and the anonymous ratings
+func (s *Server) getPeerRoundResults(c *gin.Context) {
+	roundID, err := strconv.Atoi(c.Param("roundId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	results, err := s.svc.GetPeerRoundResults(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int), roundID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := peerRoundResultsResp{
+		Round:      makePeerRoundResponse(results.Round),
+		Completion: make([]peerCompletionResp, 0, len(results.Completion)),
+		Results:    make([]peerResultResp, 0, len(results.Results)),
+	}
+	for _, completion := range results.Completion {
+		resp.Completion = append(resp.Completion, peerCompletionResp{
+			Participant: makePeerParticipantResponse(completion.Participant),
+			Done:        completion.Done,
+			Expected:    completion.Expected,
+		})
+	}
+	for _, result := range results.Results {
+		resp.Results = append(resp.Results, peerResultResp{
+			Participant: makePeerParticipantResponse(result.Participant),
+			Reviews:     result.Reviews,
+			Outliers:    result.Outliers,
+			Means:       result.Means,
+			Mean:        result.Mean,
+			RawMean:     result.RawMean,
+			Comments:    result.Comments,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func parsePeerRoundParams(c *gin.Context) (int, int, error) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	roundID, err := strconv.Atoi(c.Param("roundId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return projectID, roundID, nil
+}
+
+func makePeerRoundResponse(round model.PeerRound) peerRoundResp {
+	resp := peerRoundResp{
+		ID:       round.ID,
+		Name:     round.Name,
+		Criteria: round.Criteria,
+		MaxScore: round.MaxScore,
+		OpenedAt: round.OpenedAt,
+		Open:     round.IsOpen(time.Now()),
+	}
+	if round.ClosesAt.Valid {
+		resp.ClosesAt = &round.ClosesAt.Time
+	}
+	if round.ClosedAt.Valid {
+		resp.ClosedAt = &round.ClosedAt.Time
+	}
+	return resp
+}
+
+func makePeerAssignmentResponse(assignment *model.PeerAssignment) peerAssignmentResp {
+	resp := peerAssignmentResp{
+		Round:     makePeerRoundResponse(assignment.Round),
+		Teammates: make([]ParticipantResp, 0, len(assignment.Teammates)),
+		Reviews:   make([]peerReviewResp, 0, len(assignment.Reviews)),
+	}
+	for _, participant := range assignment.Teammates {
+		resp.Teammates = append(resp.Teammates, makePeerParticipantResponse(participant))
+	}
+	for _, review := range assignment.Reviews {
+		resp.Reviews = append(resp.Reviews, peerReviewResp{
+			RevieweeID:  review.RevieweeID,
+			Scores:      review.Scores,
+			Comment:     review.Comment.String,
+			SubmittedAt: review.SubmittedAt,
+		})
+	}
+	return resp
+}
+
+func makePeerParticipantResponse(participant model.Participant) ParticipantResp {
+	return ParticipantResp{
+		ID:        participant.ID,
+		Role:      string(participant.Role),
+		ProjectID: participant.ProjectID,
+		User:      participant.ShortUser,
+	}
+}
@@ -16,7 +16,17 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-const List1 = "Sheet1"
+const (
+	List1 = "Sheet1"
+	// List2 breaks the commits of the users down by the repositories of the project
+	List2 = "Репозитории"
+	// List3 is the code linked to the tasks of the project
+	List3 = "Задачи"
+	// List4 is the pull requests and the reviews of the users
+	List4 = "Pull request"
+	// List5 is the weekly activity of the users
+	List5 = "Активность"
+)
 
 type (
 	CreateProjectReq struct {
@@ -24,6 +34,7 @@ type (
 		Description string    `json:"description"`
 		ActiveTo    time.Time `json:"dueDate"`
 		PhotoURL    string    `json:"avatar"`
+		Course      string    `json:"course"`
 	}
 
 	CreateProjectResp struct {
@@ -33,9 +44,11 @@ type (
 
 	ProjectResp struct {
 		ShortProjectResp
-		ReportURL  string `json:"reportUrl"`
-		ReportName string `json:"reportName"`
-		RepoURL    string `json:"repo"`
+		ReportURL           string `json:"reportUrl"`
+		ReportName          string `json:"reportName"`
+		RepoURL             string `json:"repo"`
+		Course              string `json:"course"`
+		AutoTransitionTasks bool   `json:"autoTransitionTasks"`
 	}
 
 	ShortProjectResp struct {
@@ -44,6 +57,8 @@ type (
 		Description string    `json:"description"`
 		PhotoURL    string    `json:"avatar"`
 		ActiveTo    time.Time `json:"dueDate"`
+		// ChecklistProgress is the percent of the checked items, it is filled in the project lists only
+		ChecklistProgress int `json:"checklistProgress"`
 	}
 
 	projectWithParticipantsResp struct {
@@ -68,22 +83,34 @@ type (
 		Name        *string   `json:"name"`
 		Description *string   `json:"description"`
 		PhotoURL    *string   `json:"avatar"`
-		ReportURL   *string   `json:"reportUrl"`
-		ReportName  *string   `json:"reportName"`
 		RepoURL     *string   `json:"repo"`
+		Course      *string   `json:"course"`
 		ActiveTo    time.Time `json:"dueDate"`
+		// AutoTransitionTasks moves the tasks linked to the pull requests by their state
+		AutoTransitionTasks *bool `json:"autoTransitionTasks"`
 	}
 
 	projectInfoResp struct {
 		ProjectResp
-		Participants []model.Participant `json:"participants"`
-		Tasks        []ShortTaskResp     `json:"tasks"`
-		Checklist    []model.Checklist   `json:"checklist"`
+		Participants []model.Participant     `json:"participants"`
+		Tasks        []ShortTaskResp         `json:"tasks"`
+		Checklists   []checklistResp         `json:"checklists"`
+		Repositories []projectRepositoryResp `json:"repositories"`
 	}
 
 	commitsInfoResp struct {
-		User    model.ShortUser `json:"user"`
-		Metrics Metrics         `json:"metrics"`
+		User         model.ShortUser         `json:"user"`
+		Metrics      Metrics                 `json:"metrics"`
+		Repositories []repositoryCommitsResp `json:"repositories"`
+	}
+
+	repositoryCommitsResp struct {
+		RepositoryID      int    `json:"repositoryId"`
+		URL               string `json:"url"`
+		Role              string `json:"role"`
+		Count             int    `json:"count"`
+		NumberOfAdditions int    `json:"numberOfAdditions"`
+		NumberOfDeletions int    `json:"numberOfDeletions"`
 	}
 
 	Metrics struct {
@@ -92,6 +119,7 @@ type (
 		NumberOfDeletions  int `json:"numberOfDeletions"`
 		TasksDoneCount     int `json:"tasksDoneCount"`
 		TasksEstimateCount int `json:"tasksEstimateCount"`
+		TasksActualMinutes int `json:"tasksActualMinutes"`
 	}
 )
 
@@ -144,6 +172,16 @@ func (s *Server) getProjects(c *gin.Context) {
 		return
 	}
 
+	projectIDs := make([]int, 0, len(projects))
+	for _, v := range projects {
+		projectIDs = append(projectIDs, v.ID)
+	}
+	progress, err := s.svc.GetChecklistProgress(c.Request.Context(), projectIDs)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
 	projectsResp := make([]projectWithParticipantsResp, 0)
 	for _, v := range projects {
 		participants, err := s.svc.GetParticipants(c.Request.Context(), v.ID)
@@ -151,8 +189,10 @@ func (s *Server) getProjects(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 			return
 		}
+		projectResp := castProject(v)
+		projectResp.ChecklistProgress = progress[v.ID].Percent()
 		projectsResp = append(projectsResp, projectWithParticipantsResp{
-			ProjectResp:  castProject(v),
+			ProjectResp:  projectResp,
 			Participants: castParticipants(participants),
 		})
 	}
@@ -235,11 +275,23 @@ func (s *Server) getProjectCommits(c *gin.Context) {
 
 	commitsInfo, err := s.svc.GetProjectCommits(c.Request.Context(), projectID)
 	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 
 	resp := make([]commitsInfoResp, 0, len(commitsInfo))
 	for _, info := range commitsInfo {
+		repositories := make([]repositoryCommitsResp, 0, len(info.Repositories))
+		for _, repository := range info.Repositories {
+			repositories = append(repositories, repositoryCommitsResp{
+				RepositoryID:      repository.RepositoryID,
+				URL:               repository.URL,
+				Role:              repository.Role,
+				Count:             repository.Commits,
+				NumberOfAdditions: repository.Additions,
+				NumberOfDeletions: repository.Deletions,
+			})
+		}
 		resp = append(resp,
 			commitsInfoResp{
 				User: info.ShortUser,
@@ -249,7 +301,9 @@ func (s *Server) getProjectCommits(c *gin.Context) {
 					NumberOfDeletions:  info.NumberOfDeletions,
 					TasksDoneCount:     info.TotalTasksDone,
 					TasksEstimateCount: info.TotalTasksEstimate,
+					TasksActualMinutes: info.TotalTasksActual,
 				},
+				Repositories: repositories,
 			},
 		)
 	}
@@ -305,15 +359,35 @@ func (s *Server) getProjectReport(c *gin.Context) {
 			gin.H{errField: err.Error()})
 		return
 	}
-	if err = xlsx.SetCellValue(List1, "I1", "Время выполнения задач (ч.)"); err != nil {
+	if err = xlsx.SetCellValue(List1, "I1", "Оценка времени выполнения задач (ч.)"); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{errField: err.Error()})
+		return
+	}
+	if err = xlsx.SetCellValue(List1, "J1", "Фактическое время работы (ч.)"); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError,
+			gin.H{errField: err.Error()})
+		return
+	}
+	if err = xlsx.SetCellValue(List1, "K1", "Посещаемость встреч"); err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError,
 			gin.H{errField: err.Error()})
 		return
 	}
 	commitsInfo, err := s.svc.GetProjectCommits(c.Request.Context(), projectID)
 	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	attendance, err := s.svc.GetMeetingAttendance(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
+	attendanceByUser := make(map[uuid.UUID]model.Attendance, len(attendance))
+	for _, a := range attendance {
+		attendanceByUser[a.ShortUser.ID] = a
+	}
 	//nolint:errcheck
 	for i, commitInfo := range commitsInfo {
 		xlsx.SetCellValue(List1, fmt.Sprintf("A%v", i+2), commitInfo.FirstName)
@@ -324,7 +398,111 @@ func (s *Server) getProjectReport(c *gin.Context) {
 		xlsx.SetCellValue(List1, fmt.Sprintf("F%v", i+2), commitInfo.NumberOfAdditions)
 		xlsx.SetCellValue(List1, fmt.Sprintf("G%v", i+2), commitInfo.NumberOfDeletions)
 		xlsx.SetCellValue(List1, fmt.Sprintf("H%v", i+2), commitInfo.TotalTasksDone)
-		xlsx.SetCellValue(List1, fmt.Sprintf("H%v", i+2), commitInfo.TotalTasksEstimate)
+		xlsx.SetCellValue(List1, fmt.Sprintf("I%v", i+2), commitInfo.TotalTasksEstimate)
+		xlsx.SetCellValue(List1, fmt.Sprintf("J%v", i+2), minutesToHours(commitInfo.TotalTasksActual))
+		if a, ok := attendanceByUser[commitInfo.ID]; ok {
+			xlsx.SetCellValue(List1, fmt.Sprintf("K%v", i+2), fmt.Sprintf("%v/%v", a.Attended, a.Meetings))
+		}
+	}
+
+	if _, err = xlsx.NewSheet(List2); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	header := []interface{}{"Имя", "Фамилия", "Имя Github", "Репозиторий", "Роль репозитория",
+		"Кол-во коммитов", "Количество добавленных строк", "Количество удаленных строк"}
+	if err = xlsx.SetSheetRow(List2, "A1", &header); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	row := 2
+	for _, commitInfo := range commitsInfo {
+		for _, repository := range commitInfo.Repositories {
+			values := []interface{}{commitInfo.FirstName, commitInfo.LastName, commitInfo.GithubUsername,
+				repository.URL, repository.Role,
+				repository.Commits, repository.Additions, repository.Deletions}
+			if err = xlsx.SetSheetRow(List2, fmt.Sprintf("A%v", row), &values); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+				return
+			}
+			row++
+		}
+	}
+
+	churn, err := s.svc.GetTaskChurn(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if _, err = xlsx.NewSheet(List3); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	header = []interface{}{"Задача", "Название", "Статус", "Кол-во коммитов", "Кол-во pull request",
+		"Количество добавленных строк", "Количество удаленных строк"}
+	if err = xlsx.SetSheetRow(List3, "A1", &header); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	for i, task := range churn {
+		values := []interface{}{task.TaskID, task.Name, string(task.Status), task.Commits, task.PullRequests,
+			task.Additions, task.Deletions}
+		if err = xlsx.SetSheetRow(List3, fmt.Sprintf("A%v", i+2), &values); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+			return
+		}
+	}
+
+	prMetrics, err := s.svc.GetPullRequestMetrics(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if _, err = xlsx.NewSheet(List4); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	header = []interface{}{"Имя", "Фамилия", "Имя Github", "Открыто pull request", "Влито pull request",
+		"Медианное время до влития (ч.)", "Кол-во ревью", "Комментарии в ревью", "Одобрения"}
+	if err = xlsx.SetSheetRow(List4, "A1", &header); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	for i, m := range prMetrics {
+		values := []interface{}{m.FirstName, m.LastName, m.GithubUsername, m.Opened, m.Merged,
+			durationToHours(m.MedianTimeToMerge), m.ReviewsGiven, m.ReviewComments, m.Approvals}
+		if err = xlsx.SetSheetRow(List4, fmt.Sprintf("A%v", i+2), &values); err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+			return
+		}
+	}
+
+	timeline, err := s.svc.GetActivityTimeline(c.Request.Context(), projectID, model.ActivityWeek, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if _, err = xlsx.NewSheet(List5); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	header = []interface{}{"Имя", "Фамилия", "Имя Github", "Неделя", "Кол-во коммитов",
+		"Количество добавленных строк", "Количество удаленных строк", "Количество выполненных заданий"}
+	if err = xlsx.SetSheetRow(List5, "A1", &header); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+		return
+	}
+	row = 2
+	for _, series := range timeline {
+		for _, point := range series.Points {
+			values := []interface{}{series.FirstName, series.LastName, series.GithubUsername,
+				point.Start.Format("2006-01-02"), point.Commits, point.Additions, point.Deletions, point.TasksDone}
+			if err = xlsx.SetSheetRow(List5, fmt.Sprintf("A%v", row), &values); err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{errField: err.Error()})
+				return
+			}
+			row++
+		}
 	}
 
 	buffer, err := xlsx.WriteToBuffer()
@@ -362,13 +540,16 @@ func (s *Server) sendProjectInfoResponse(c *gin.Context, projectID int) {
 				PhotoURL:    projectInfo.PhotoURL.String,
 				ActiveTo:    projectInfo.ActiveTo,
 			},
-			ReportURL:  projectInfo.ReportURL.String,
-			ReportName: projectInfo.ReportName.String,
-			RepoURL:    projectInfo.RepoURL.String,
+			ReportURL:           projectInfo.ReportURL.String,
+			ReportName:          projectInfo.ReportName.String,
+			RepoURL:             projectInfo.RepoURL.String,
+			Course:              projectInfo.Course.String,
+			AutoTransitionTasks: projectInfo.AutoTransitionTasks,
 		},
 		Participants: projectInfo.Participants,
 		Tasks:        shortTasksResponse,
-		Checklist:    projectInfo.Checklist,
+		Checklists:   makeChecklistResponses(projectInfo.Checklists),
+		Repositories: makeProjectRepositoryResponses(projectInfo.Repositories),
 	})
 }
 
@@ -381,9 +562,11 @@ func castProject(project model.Project) ProjectResp {
 			PhotoURL:    project.PhotoURL.String,
 			ActiveTo:    project.ActiveTo,
 		},
-		ReportURL:  project.ReportURL.String,
-		ReportName: project.ReportName.String,
-		RepoURL:    project.RepoURL.String,
+		ReportURL:           project.ReportURL.String,
+		ReportName:          project.ReportName.String,
+		RepoURL:             project.RepoURL.String,
+		Course:              project.Course.String,
+		AutoTransitionTasks: project.AutoTransitionTasks,
 	}
 }
 
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+)
+
+type (
+	// ProjectRepositoryReq adds or changes the repository of the project, Role is a free label like backend
+	ProjectRepositoryReq struct {
+		ProjectID     int    `json:"-"`
+		RepositoryID  int    `json:"-"`
+		URL           string `json:"url"`
+		DefaultBranch string `json:"defaultBranch"`
+		Role          string `json:"role"`
+	}
+
+	projectRepositoryResp struct {
+		ID            int       `json:"id"`
+		URL           string    `json:"url"`
+		Provider      string    `json:"provider"`
+		DefaultBranch string    `json:"defaultBranch"`
+		Role          string    `json:"role"`
+		CreatedAt     time.Time `json:"createdAt"`
+	}
+)
+
+func (s *Server) getProjectRepositories(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	repositories, err := s.svc.GetProjectRepositories(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeProjectRepositoryResponses(repositories))
+}
+
+func (s *Server) addProjectRepository(c *gin.Context) {
+	repositoryReq := &ProjectRepositoryReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(repositoryReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	repositoryReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+
+	repository, err := s.svc.AddProjectRepository(c.Request.Context(), repositoryReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeProjectRepositoryResponse(*repository))
+}
+
+func (s *Server) updateProjectRepository(c *gin.Context) {
+	repositoryID, err := strconv.Atoi(c.Param("repositoryId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	repositoryReq := &ProjectRepositoryReq{}
+	if err = json.NewDecoder(c.Request.Body).Decode(repositoryReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	repositoryReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	repositoryReq.RepositoryID = repositoryID
+
+	repository, err := s.svc.UpdateProjectRepository(c.Request.Context(), repositoryReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeProjectRepositoryResponse(*repository))
+}
+
+func (s *Server) deleteProjectRepository(c *gin.Context) {
+	repositoryID, err := strconv.Atoi(c.Param("repositoryId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err = s.svc.DeleteProjectRepository(c.Request.Context(),
+		c.MustGet(string(domain.ProjectIDCtx)).(int), repositoryID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func makeProjectRepositoryResponse(repository model.ProjectRepository) projectRepositoryResp {
+	return projectRepositoryResp{
+		ID:            repository.ID,
+		URL:           repository.URL,
+		Provider:      repository.Provider,
+		DefaultBranch: repository.DefaultBranch,
+		Role:          repository.Role,
+		CreatedAt:     repository.CreatedAt,
+	}
+}
+
+func makeProjectRepositoryResponses(repositories []model.ProjectRepository) []projectRepositoryResp {
+	resp := make([]projectRepositoryResp, 0, len(repositories))
+	for _, repository := range repositories {
+		resp = append(resp, makeProjectRepositoryResponse(repository))
+	}
+	return resp
+}
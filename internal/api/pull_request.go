@@ -0,0 +1,55 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pullRequestMetricsResp struct {
+	User                   model.ShortUser `json:"user"`
+	Opened                 int             `json:"opened"`
+	Merged                 int             `json:"merged"`
+	MedianTimeToMergeHours float64         `json:"medianTimeToMergeHours"`
+	ReviewsGiven           int             `json:"reviewsGiven"`
+	ReviewComments         int             `json:"reviewComments"`
+	Approvals              int             `json:"approvals"`
+}
+
+func (s *Server) getPullRequestMetrics(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	metrics, err := s.svc.GetPullRequestMetrics(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := make([]pullRequestMetricsResp, 0, len(metrics))
+	for _, m := range metrics {
+		resp = append(resp, pullRequestMetricsResp{
+			User:                   m.ShortUser,
+			Opened:                 m.Opened,
+			Merged:                 m.Merged,
+			MedianTimeToMergeHours: durationToHours(m.MedianTimeToMerge),
+			ReviewsGiven:           m.ReviewsGiven,
+			ReviewComments:         m.ReviewComments,
+			Approvals:              m.Approvals,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// durationToHours rounds the duration to hundredths of an hour
+func durationToHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// githubWebhookMaxBody is the largest payload github sends
+const githubWebhookMaxBody = 25 << 20
+
+type (
+	GetRepositoryEventsReq struct {
+		ProjectID int
+		Kind      model.RepositoryEventKind
+		UserID    uuid.UUID
+		Offset    int
+		Limit     int
+	}
+
+	repositoryEventResp struct {
+		ID             int                       `json:"id"`
+		RepositoryID   int                       `json:"repositoryId"`
+		Kind           model.RepositoryEventKind `json:"kind"`
+		Action         string                    `json:"action"`
+		GithubUsername string                    `json:"githubUsername"`
+		UserID         *uuid.UUID                `json:"userId,omitempty"`
+		Number         *int64                    `json:"number,omitempty"`
+		SHA            string                    `json:"sha,omitempty"`
+		Ref            string                    `json:"ref,omitempty"`
+		Title          string                    `json:"title"`
+		URL            string                    `json:"url"`
+		State          string                    `json:"state,omitempty"`
+		OccurredAt     time.Time                 `json:"occurredAt"`
+	}
+)
+
+// receiveGithubWebhook saves the push, pull_request, pull_request_review and issues events of the repositories
+// of the projects. The payload is signed by github with the secret of the webhook in X-Hub-Signature-256.
+func (s *Server) receiveGithubWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, githubWebhookMaxBody))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if !validGithubSignature(c.GetHeader("X-Hub-Signature-256"), payload, s.githubWebhookSecret) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{errField: ierr.ErrWebhookSignatureIsInvalid.Error()})
+		return
+	}
+
+	if err = s.svc.ReceiveGithubWebhook(c.Request.Context(), &model.WebhookDelivery{
+		ID:         c.GetHeader("X-GitHub-Delivery"),
+		Event:      c.GetHeader("X-GitHub-Event"),
+		ReceivedAt: time.Now(),
+	}, payload); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// getProjectActivity returns the activity in the repositories of the project received from the webhooks
+func (s *Server) getProjectActivity(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	eventsReq := &GetRepositoryEventsReq{
+		ProjectID: projectID,
+		Kind:      model.RepositoryEventKind(strings.ToUpper(c.Query("kind"))),
+	}
+	if userID := c.Query("userId"); userID != "" {
+		if eventsReq.UserID, err = uuid.Parse(userID); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+			return
+		}
+	}
+	eventsReq.Offset, _ = strconv.Atoi(c.Query("offset"))
+	eventsReq.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	events, err := s.svc.GetRepositoryEvents(c.Request.Context(), eventsReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := make([]repositoryEventResp, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, makeRepositoryEventResponse(event))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func makeRepositoryEventResponse(event model.RepositoryEvent) repositoryEventResp {
+	resp := repositoryEventResp{
+		ID:             event.ID,
+		RepositoryID:   event.RepositoryID,
+		Kind:           event.Kind,
+		Action:         event.Action,
+		GithubUsername: event.GithubUsername,
+		SHA:            event.SHA.String,
+		Ref:            event.Ref,
+		Title:          event.Title,
+		URL:            event.URL,
+		State:          event.State,
+		OccurredAt:     event.OccurredAt,
+	}
+	if event.UserID.Valid {
+		resp.UserID = &event.UserID.UUID
+	}
+	if event.Number.Valid {
+		resp.Number = &event.Number.Int64
+	}
+	return resp
+}
+
+// validGithubSignature checks the signature like sha256=<hex hmac of the payload>
+func validGithubSignature(signature string, payload, secret []byte) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal([]byte(strings.TrimPrefix(signature, "sha256=")), []byte(hex.EncodeToString(mac.Sum(nil))))
+}
@@ -21,6 +21,10 @@ type (
 		*http.Server
 		logger *zap.SugaredLogger
 		svc    Service
+		// storageHandler serves the signed urls of the local file storage
+		storageHandler http.Handler
+		// githubWebhookSecret signs the payloads of the github webhooks, the webhook is off without it
+		githubWebhookSecret []byte
 
 		shutdownTimeout int
 	}
@@ -28,8 +32,25 @@ type (
 	Service interface {
 		userService
 		projectService
+		checklistService
+		checklistTemplateService
 		participantService
 		taskService
+		worklogService
+		boardService
+		notificationService
+		fileService
+		submissionService
+		gradeService
+		peerService
+		statusReportService
+		meetingService
+		githubSyncService
+		projectRepositoryService
+		repositoryEventService
+		issueSyncService
+		pullRequestService
+		activityService
 		tokenService
 	}
 	userService interface {
@@ -41,6 +62,8 @@ type (
 		UpdateUser(ctx context.Context, userReq *UpdateUserReq) (*model.User, error)
 		DeleteUser(ctx context.Context, id uuid.UUID) error
 		GetUserProfile(ctx context.Context, id uuid.UUID) (*model.Profile, error)
+		GetVCSAccounts(ctx context.Context, userID uuid.UUID) ([]model.VCSAccount, error)
+		UpdateVCSAccounts(ctx context.Context, userID uuid.UUID, accounts []model.VCSAccount) ([]model.VCSAccount, error)
 	}
 
 	tokenService interface {
@@ -55,10 +78,21 @@ type (
 		GetProjects(ctx context.Context, projectReq *GetProjectsReq) ([]model.Project, int, error)
 		GetProjectInfo(ctx context.Context, id int) (*model.ProjectInfo, error)
 		GetProjectCommits(ctx context.Context, id int) ([]model.CommitsInfo, error)
-		GetProjectChecklist(ctx context.Context, id int) ([]model.Checklist, error)
-		AddProjectChecklist(ctx context.Context, id int, checklist []model.Checklist) ([]model.Checklist, error)
-		UpdateProjectChecklist(ctx context.Context, id int, checklist *model.Checklist) ([]model.Checklist, error)
-		DeleteProjectChecklist(ctx context.Context, id int, checklistID int) ([]model.Checklist, error)
+	}
+
+	checklistService interface {
+		GetProjectChecklist(ctx context.Context, id int) ([]model.ChecklistItem, error)
+		AddProjectChecklist(ctx context.Context, userID uuid.UUID, id int, checklist []ChecklistItemReq) ([]model.ChecklistItem, error)
+		UpdateProjectChecklist(ctx context.Context, checklist *ChecklistItemReq) ([]model.ChecklistItem, error)
+		DeleteProjectChecklist(ctx context.Context, userID uuid.UUID, id int, checklistID int) ([]model.ChecklistItem, error)
+		GetChecklists(ctx context.Context, projectID int) ([]model.Checklist, error)
+		GetChecklistProgress(ctx context.Context, projectIDs []int) (map[int]model.ChecklistProgress, error)
+		CreateChecklist(ctx context.Context, userID uuid.UUID, checklistReq *ChecklistReq) (*model.Checklist, error)
+		UpdateChecklist(ctx context.Context, checklistReq *ChecklistReq) (*model.Checklist, error)
+		DeleteChecklist(ctx context.Context, userID uuid.UUID, projectID, checklistID int) error
+		AddChecklistItem(ctx context.Context, itemReq *ChecklistItemReq) (*model.Checklist, error)
+		UpdateChecklistItem(ctx context.Context, itemReq *ChecklistItemReq) (*model.Checklist, error)
+		DeleteChecklistItem(ctx context.Context, userID uuid.UUID, projectID, checklistID, itemID int) (*model.Checklist, error)
 	}
 
 	participantService interface {
@@ -77,8 +111,138 @@ type (
 		CreateTask(ctx context.Context, creatorUserID uuid.UUID, task *CreateTaskReq) (*model.Task, error)
 		UpdateTask(ctx context.Context, taskReq *UpdateTaskReq) (*model.Task, error)
 		DeleteTask(ctx context.Context, id int) error
+		BulkUpdateTasks(ctx context.Context, bulkReq *BulkTaskReq) ([]model.BulkTaskResult, error)
+		ExportTasks(ctx context.Context, projectID int, format model.BacklogFormat) ([]byte, error)
+		ImportTasks(ctx context.Context, userID uuid.UUID, projectID int, format model.BacklogFormat, data []byte) (*model.BacklogImport, error)
 		GetTasks(ctx context.Context, taskReq *GetTasksReq) ([]model.Task, int, error)
 		GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo, error)
+		GetTaskChurn(ctx context.Context, projectID int) ([]model.TaskChurn, error)
+	}
+
+	notificationService interface {
+		GetNotifications(ctx context.Context, notificationReq *GetNotificationsReq) ([]model.Notification, int, error)
+		GetUnreadNotificationCount(ctx context.Context, userID uuid.UUID) (int, error)
+		MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []int) error
+		GetNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error)
+		UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences []model.NotificationPreference) ([]model.NotificationPreference, error)
+		GetUserChannels(ctx context.Context, userID uuid.UUID) ([]model.UserChannel, error)
+		UpdateUserChannels(ctx context.Context, userID uuid.UUID, channels []model.UserChannel) ([]model.UserChannel, error)
+		WatchTask(ctx context.Context, userID uuid.UUID, projectID, taskID int) error
+		UnwatchTask(ctx context.Context, userID uuid.UUID, projectID, taskID int) error
+		GetTaskWatchers(ctx context.Context, projectID, taskID int) ([]model.ShortUser, error)
+	}
+
+	boardService interface {
+		GetBoard(ctx context.Context, projectID int) (*model.Board, error)
+		MoveTask(ctx context.Context, moveReq *MoveTaskReq) (*model.Task, error)
+		UpdateBoardColumn(ctx context.Context, columnReq *UpdateBoardColumnReq) (*model.Board, error)
+	}
+
+	fileService interface {
+		UploadProjectAvatar(ctx context.Context, fileReq *UploadFileReq) (*model.File, error)
+		UploadTaskAttachment(ctx context.Context, fileReq *UploadFileReq) (*model.File, error)
+		GetTaskAttachments(ctx context.Context, projectID, taskID int) ([]model.File, error)
+		DeleteTaskAttachment(ctx context.Context, projectID, taskID, fileID int) error
+		GetFileURL(ctx context.Context, userID uuid.UUID, fileID int) (string, error)
+	}
+
+	submissionService interface {
+		SubmitProjectReport(ctx context.Context, submitReq *SubmitReportReq) (*model.ReportSubmission, error)
+		GetProjectSubmissions(ctx context.Context, projectID int) ([]model.ReportSubmission, error)
+		ReviewSubmission(ctx context.Context, reviewReq *ReviewSubmissionReq) (*model.ReportSubmission, error)
+		GetCourseSubmissions(ctx context.Context, userID uuid.UUID, course string) ([]model.ProjectSubmissions, error)
+	}
+
+	gradeService interface {
+		GetRubrics(ctx context.Context, userID uuid.UUID) ([]model.Rubric, error)
+		GetRubric(ctx context.Context, userID uuid.UUID, rubricID int) (*model.Rubric, error)
+		CreateRubric(ctx context.Context, rubricReq *RubricReq) (*model.Rubric, error)
+		UpdateRubric(ctx context.Context, rubricReq *RubricReq) (*model.Rubric, error)
+		DeleteRubric(ctx context.Context, userID uuid.UUID, rubricID int) error
+		GetProjectGrades(ctx context.Context, userID uuid.UUID, rubricID, projectID int) ([]model.Grade, error)
+		UpdateProjectGrades(ctx context.Context, gradesReq *UpdateGradesReq) ([]model.Grade, error)
+		AutofillGrades(ctx context.Context, userID uuid.UUID, rubricID, projectID int) ([]model.Grade, error)
+		GetGradeBook(ctx context.Context, bookReq *GradeBookReq) (*model.GradeBook, error)
+		ExportGradeBook(ctx context.Context, bookReq *GradeBookReq) ([]byte, error)
+	}
+
+	peerService interface {
+		OpenPeerRound(ctx context.Context, roundReq *PeerRoundReq) (*model.PeerRound, error)
+		GetPeerRounds(ctx context.Context, projectID int) ([]model.PeerRound, error)
+		ClosePeerRound(ctx context.Context, projectID, roundID int) (*model.PeerRound, error)
+		GetPeerAssignment(ctx context.Context, userID uuid.UUID, projectID, roundID int) (*model.PeerAssignment, error)
+		SubmitPeerReviews(ctx context.Context, reviewsReq *SubmitPeerReviewsReq) (*model.PeerAssignment, error)
+		GetPeerRoundResults(ctx context.Context, projectID, roundID int) (*model.PeerRoundResults, error)
+	}
+
+	statusReportService interface {
+		GetStatusReportDraft(ctx context.Context, projectID int, week time.Time) (*model.StatusReportDraft, error)
+		SubmitStatusReport(ctx context.Context, reportReq *StatusReportReq) (*model.StatusReport, error)
+		GetStatusReports(ctx context.Context, projectID int) ([]model.StatusReport, error)
+		CommentStatusReport(ctx context.Context, commentReq *StatusReportCommentReq) (*model.StatusReport, error)
+		GetWeekStatusReports(ctx context.Context, ownerID uuid.UUID, week time.Time) ([]model.ProjectStatusReport, error)
+		ExportStatusReports(ctx context.Context, projectID int, format model.StatusReportsFormat) ([]byte, error)
+	}
+
+	githubSyncService interface {
+		GetGithubSyncs(ctx context.Context, projectID int) ([]model.GithubSync, error)
+		ResyncGithubStats(ctx context.Context, projectID int) ([]model.GithubSync, error)
+		GetAllGithubSyncs(ctx context.Context) ([]model.GithubSync, error)
+	}
+
+	repositoryEventService interface {
+		ReceiveGithubWebhook(ctx context.Context, delivery *model.WebhookDelivery, payload []byte) error
+		GetRepositoryEvents(ctx context.Context, eventsReq *GetRepositoryEventsReq) ([]model.RepositoryEvent, error)
+	}
+
+	pullRequestService interface {
+		GetPullRequestMetrics(ctx context.Context, projectID int) ([]model.PullRequestMetrics, error)
+	}
+
+	activityService interface {
+		GetActivityTimeline(ctx context.Context, projectID int, granularity model.ActivityGranularity, now time.Time) ([]model.ActivitySeries, error)
+		GetActivityFlags(ctx context.Context, ownerID uuid.UUID, now time.Time, inactiveDays, lastDays int) ([]model.ActivityFlag, error)
+	}
+
+	issueSyncService interface {
+		GetIssueSync(ctx context.Context, projectID int) (*model.IssueSync, error)
+		SetIssueSync(ctx context.Context, syncReq *IssueSyncReq) (*model.IssueSync, error)
+		SyncIssues(ctx context.Context, projectID int, dryRun bool) ([]model.IssueSyncEntry, error)
+		GetIssueSyncLog(ctx context.Context, logReq *GetIssueSyncLogReq) ([]model.IssueSyncEntry, error)
+	}
+
+	projectRepositoryService interface {
+		GetProjectRepositories(ctx context.Context, projectID int) ([]model.ProjectRepository, error)
+		AddProjectRepository(ctx context.Context, repositoryReq *ProjectRepositoryReq) (*model.ProjectRepository, error)
+		UpdateProjectRepository(ctx context.Context, repositoryReq *ProjectRepositoryReq) (*model.ProjectRepository, error)
+		DeleteProjectRepository(ctx context.Context, projectID, repositoryID int) error
+	}
+
+	checklistTemplateService interface {
+		GetChecklistTemplates(ctx context.Context, userID uuid.UUID) ([]model.ChecklistTemplate, error)
+		GetChecklistTemplate(ctx context.Context, userID uuid.UUID, templateID int) (*model.ChecklistTemplate, error)
+		CreateChecklistTemplate(ctx context.Context, templateReq *ChecklistTemplateReq) (*model.ChecklistTemplate, error)
+		UpdateChecklistTemplate(ctx context.Context, templateReq *ChecklistTemplateReq) (*model.ChecklistTemplate, error)
+		DeleteChecklistTemplate(ctx context.Context, userID uuid.UUID, templateID int) error
+		ApplyChecklistTemplate(ctx context.Context, applyReq *ApplyChecklistTemplateReq) (*model.TemplateApplication, error)
+	}
+
+	meetingService interface {
+		GetMeetings(ctx context.Context, projectID int) ([]model.Meeting, error)
+		GetMeeting(ctx context.Context, projectID, meetingID int) (*model.Meeting, error)
+		CreateMeeting(ctx context.Context, meetingReq *MeetingReq) (*model.Meeting, error)
+		UpdateMeeting(ctx context.Context, meetingReq *MeetingReq) (*model.Meeting, error)
+		DeleteMeeting(ctx context.Context, projectID, meetingID int) error
+		GetMeetingAttendance(ctx context.Context, projectID int) ([]model.Attendance, error)
+	}
+
+	worklogService interface {
+		AddWorklog(ctx context.Context, userID uuid.UUID, worklogReq *CreateWorklogReq) (*model.Worklog, error)
+		GetTaskWorklogs(ctx context.Context, projectID, taskID int) (*model.TaskWorklogs, error)
+		DeleteWorklog(ctx context.Context, userID uuid.UUID, projectID, taskID, id int) error
+		StartTimer(ctx context.Context, userID uuid.UUID, projectID, taskID int) (*model.Worklog, error)
+		StopTimer(ctx context.Context, userID uuid.UUID, projectID, taskID int, note string) (*model.Worklog, error)
+		GetWeeklyWorklogs(ctx context.Context, projectID int) ([]model.WeeklyWorklog, error)
 	}
 
 	OptionFunc func(s *Server)
@@ -117,6 +281,8 @@ func New(opts ...OptionFunc) *Server {
 	usersRtr := apiRtr.Group("/user")
 	usersRtr.GET("/search", s.getPartialUsers)
 	usersRtr.GET("/", s.authMiddleware(model.Admin, model.ProjectManager, model.Student), s.getUserProfileFromToken)
+	usersRtr.GET("/vcs-accounts", s.authMiddleware(model.Admin, model.ProjectManager, model.Student), s.getVCSAccounts)
+	usersRtr.PUT("/vcs-accounts", s.authMiddleware(model.Admin, model.ProjectManager, model.Student), s.updateVCSAccounts)
 	usersRtr.GET("/:id", s.getUserProfile)
 	usersRtr.PATCH("/", s.parseBodyToUpdatedUser, s.updateMiddleware(), s.updateUser)
 	//usersRtr.DELETE("/:id", s.deleteUser)
@@ -124,6 +290,28 @@ func New(opts ...OptionFunc) *Server {
 	// /api/pm
 	pmRtr := apiRtr.Group("/pm", s.authMiddleware(model.ProjectManager))
 	pmRtr.POST("/", s.createProject)
+	pmRtr.GET("/submissions", s.getCourseSubmissions)
+	pmRtr.GET("/status-reports", s.getWeekStatusReports)
+	pmRtr.GET("/activity-flags", s.getActivityFlags)
+	// /api/pm/rubrics
+	rubricRtr := pmRtr.Group("/rubrics")
+	rubricRtr.GET("/", s.getRubrics)
+	rubricRtr.POST("/", s.createRubric)
+	rubricRtr.GET("/:rubricId", s.getRubric)
+	rubricRtr.PUT("/:rubricId", s.updateRubric)
+	rubricRtr.DELETE("/:rubricId", s.deleteRubric)
+	rubricRtr.GET("/:rubricId/gradebook", s.getGradeBook)
+	rubricRtr.GET("/:rubricId/projects/:projectId/grades", s.getProjectGrades)
+	rubricRtr.PUT("/:rubricId/projects/:projectId/grades", s.updateProjectGrades)
+	rubricRtr.POST("/:rubricId/projects/:projectId/grades/autofill", s.autofillGrades)
+	// /api/pm/checklist-templates
+	templateRtr := pmRtr.Group("/checklist-templates")
+	templateRtr.GET("/", s.getChecklistTemplates)
+	templateRtr.POST("/", s.createChecklistTemplate)
+	templateRtr.GET("/:templateId", s.getChecklistTemplate)
+	templateRtr.PUT("/:templateId", s.updateChecklistTemplate)
+	templateRtr.DELETE("/:templateId", s.deleteChecklistTemplate)
+	templateRtr.POST("/:templateId/apply", s.applyChecklistTemplate)
 
 	// /api/project
 	projectRtr := apiRtr.Group("/project", s.authMiddleware(model.Admin, model.ProjectManager, model.Student))
@@ -133,10 +321,16 @@ func New(opts ...OptionFunc) *Server {
 	projectRtr.GET("/:projectId", s.getProjectInfo)
 	projectRtr.GET("/:projectId/commits", s.getProjectCommits)
 	projectRtr.GET("/:projectId/report", s.getProjectReport)
-	projectRtr.GET("/:projectId/checklist", s.getProjectChecklist)
-	projectRtr.POST("/:projectId/checklist", s.addProjectChecklist)
-	projectRtr.PUT("/:projectId/checklist", s.updateProjectChecklist)
-	projectRtr.DELETE("/:projectId/checklist", s.removeProjectChecklist)
+	projectRtr.GET("/:projectId/activity", s.verifyParticipantMiddleware(), s.getProjectActivity)
+	projectRtr.GET("/:projectId/pull-requests", s.verifyParticipantMiddleware(), s.getPullRequestMetrics)
+	projectRtr.GET("/:projectId/timeline", s.verifyParticipantMiddleware(), s.getActivityTimeline)
+	projectRtr.GET("/:projectId/github-sync", s.parseProjectIDParam, s.verifyParticipantMiddleware(), s.getGithubSyncs)
+	projectRtr.POST("/:projectId/github-sync", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.resyncGithubStats)
+	projectRtr.GET("/:projectId/checklist", s.verifyParticipantMiddleware(), s.getProjectChecklist)
+	projectRtr.POST("/:projectId/checklist", s.verifyParticipantMiddleware(), s.addProjectChecklist)
+	projectRtr.PUT("/:projectId/checklist", s.verifyParticipantMiddleware(), s.updateProjectChecklist)
+	projectRtr.DELETE("/:projectId/checklist", s.verifyParticipantMiddleware(), s.removeProjectChecklist)
 	projectRtr.DELETE("/remove", s.parseBodyToDeletedProject,
 		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.deleteProject)
 	projectRtr.POST("/add-participant", s.parseBodyToAddedParticipant,
@@ -152,6 +346,135 @@ func New(opts ...OptionFunc) *Server {
 	taskRtr.PATCH("/", s.updateTask)
 	taskRtr.GET("/:taskId", s.getTaskInfo)
 	taskRtr.DELETE("/", s.deleteTask)
+	taskRtr.POST("/bulk", s.bulkUpdateTasks)
+	taskRtr.GET("/export", s.exportTasks)
+	taskRtr.POST("/import", s.importTasks)
+	taskRtr.GET("/:taskId/worklog", s.getTaskWorklogs)
+	taskRtr.POST("/:taskId/worklog", s.createWorklog)
+	taskRtr.DELETE("/:taskId/worklog", s.deleteWorklog)
+	taskRtr.POST("/:taskId/timer/start", s.startTimer)
+	taskRtr.POST("/:taskId/timer/stop", s.stopTimer)
+	taskRtr.GET("/:taskId/watchers", s.getTaskWatchers)
+	taskRtr.POST("/:taskId/watchers", s.watchTask)
+	taskRtr.DELETE("/:taskId/watchers", s.unwatchTask)
+	taskRtr.GET("/:taskId/attachments", s.getTaskAttachments)
+	taskRtr.POST("/:taskId/attachments", s.uploadTaskAttachment)
+	taskRtr.DELETE("/:taskId/attachments/:fileId", s.deleteTaskAttachment)
+
+	// /api/project/:projectId/worklog
+	projectRtr.GET("/:projectId/worklog/weekly", s.verifyParticipantMiddleware(), s.getWeeklyWorklogs)
+
+	// /api/project/:projectId/board
+	boardRtr := projectRtr.Group("/:projectId/board", s.verifyParticipantMiddleware())
+	boardRtr.GET("/", s.getBoard)
+	boardRtr.POST("/move", s.moveTask)
+	boardRtr.PUT("/column", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.updateBoardColumn)
+
+	// /api/project/:projectId/files
+	projectFilesRtr := projectRtr.Group("/:projectId/files", s.verifyParticipantMiddleware())
+	projectFilesRtr.POST("/avatar", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.uploadProjectAvatar)
+
+	// /api/project/:projectId/submissions
+	submissionRtr := projectRtr.Group("/:projectId/submissions", s.verifyParticipantMiddleware())
+	submissionRtr.GET("/", s.getProjectSubmissions)
+	submissionRtr.POST("/", s.submitProjectReport)
+	submissionRtr.PUT("/:submissionId/review", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.reviewSubmission)
+
+	// /api/project/:projectId/peer-rounds
+	peerRtr := projectRtr.Group("/:projectId/peer-rounds", s.verifyParticipantMiddleware())
+	peerRtr.GET("/", s.getPeerRounds)
+	peerRtr.POST("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.openPeerRound)
+	peerRtr.POST("/:roundId/close", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.closePeerRound)
+	peerRtr.GET("/:roundId/results", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.getPeerRoundResults)
+	peerRtr.GET("/:roundId/reviews", s.getPeerAssignment)
+	peerRtr.PUT("/:roundId/reviews", s.submitPeerReviews)
+
+	// /api/project/:projectId/status-reports
+	statusReportRtr := projectRtr.Group("/:projectId/status-reports", s.verifyParticipantMiddleware())
+	statusReportRtr.GET("/", s.getStatusReports)
+	statusReportRtr.PUT("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleTeamlead), s.submitStatusReport)
+	statusReportRtr.GET("/draft", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.getStatusReportDraft)
+	statusReportRtr.GET("/export", s.exportStatusReports)
+	statusReportRtr.PUT("/:reportId/comment", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner), s.commentStatusReport)
+
+	// /api/project/:projectId/checklists
+	checklistRtr := projectRtr.Group("/:projectId/checklists", s.verifyParticipantMiddleware())
+	checklistRtr.GET("/", s.getChecklists)
+	checklistRtr.POST("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.createChecklist)
+	checklistRtr.PUT("/:checklistId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.updateChecklist)
+	checklistRtr.DELETE("/:checklistId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.deleteChecklist)
+	checklistRtr.POST("/:checklistId/items", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.addChecklistItem)
+	checklistRtr.PUT("/:checklistId/items/:itemId", s.updateChecklistItem)
+	checklistRtr.DELETE("/:checklistId/items/:itemId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.deleteChecklistItem)
+
+	// /api/project/:projectId/repositories
+	repositoryRtr := projectRtr.Group("/:projectId/repositories", s.verifyParticipantMiddleware())
+	repositoryRtr.GET("/", s.getProjectRepositories)
+	repositoryRtr.POST("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.addProjectRepository)
+	repositoryRtr.PUT("/:repositoryId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.updateProjectRepository)
+	repositoryRtr.DELETE("/:repositoryId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.deleteProjectRepository)
+
+	// /api/project/:projectId/issue-sync
+	issueSyncRtr := projectRtr.Group("/:projectId/issue-sync", s.verifyParticipantMiddleware())
+	issueSyncRtr.GET("/", s.getIssueSync)
+	issueSyncRtr.GET("/log", s.getIssueSyncLog)
+	issueSyncRtr.PUT("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.setIssueSync)
+	issueSyncRtr.POST("/run", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.runIssueSync)
+
+	// /api/project/:projectId/meetings
+	meetingRtr := projectRtr.Group("/:projectId/meetings", s.verifyParticipantMiddleware())
+	meetingRtr.GET("/", s.getMeetings)
+	meetingRtr.GET("/attendance", s.getMeetingAttendance)
+	meetingRtr.GET("/:meetingId", s.getMeeting)
+	meetingRtr.POST("/", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.createMeeting)
+	meetingRtr.PUT("/:meetingId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.updateMeeting)
+	meetingRtr.DELETE("/:meetingId", s.parseProjectIDParam,
+		s.verifyParticipantRoleMiddleware(model.RoleOwner, model.RoleTeamlead), s.deleteMeeting)
+
+	// /api/files
+	filesRtr := apiRtr.Group("/files", s.authMiddleware(model.Admin, model.ProjectManager, model.Student))
+	filesRtr.GET("/:fileId", s.downloadFile)
+	filesRtr.GET("/:fileId/url", s.getFileURL)
+	// /api/storage is served only by the local storage, the signature is checked by the storage
+	if s.storageHandler != nil {
+		apiRtr.GET("/storage/*key", gin.WrapH(http.StripPrefix("/api/storage", s.storageHandler)))
+	}
+
+	// /api/webhooks/github is signed by github, the signature is checked by the handler
+	if len(s.githubWebhookSecret) > 0 {
+		apiRtr.POST("/webhooks/github", s.receiveGithubWebhook)
+	}
+
+	// /api/notifications
+	notificationRtr := apiRtr.Group("/notifications", s.authMiddleware(model.Admin, model.ProjectManager, model.Student))
+	notificationRtr.GET("/", s.getNotifications)
+	notificationRtr.GET("/unread-count", s.getUnreadNotificationCount)
+	notificationRtr.POST("/read", s.markNotificationsRead)
+	notificationRtr.GET("/preferences", s.getNotificationPreferences)
+	notificationRtr.PUT("/preferences", s.updateNotificationPreferences)
+	notificationRtr.GET("/channels", s.getUserChannels)
+	notificationRtr.PUT("/channels", s.updateUserChannels)
 
 	// /api/admin
 	adminRtr := apiRtr.Group("/admin", s.authMiddleware(model.Admin))
@@ -161,6 +484,9 @@ func New(opts ...OptionFunc) *Server {
 	adminRtr.POST("/users", s.parseBodyToUpdatedUser, s.updateUser)
 	// /api/admin/projects
 	adminRtr.GET("/projects", s.getProjects)
+	adminRtr.GET("/status-reports", s.getAllWeekStatusReports)
+	adminRtr.GET("/activity-flags", s.getAllActivityFlags)
+	adminRtr.GET("/github-syncs", s.getAllGithubSyncs)
 
 	s.Handler = rtr
 	return s
@@ -199,6 +525,18 @@ func WithService(svc Service) OptionFunc {
 	}
 }
 
+func WithStorageHandler(handler http.Handler) OptionFunc {
+	return func(s *Server) {
+		s.storageHandler = handler
+	}
+}
+
+func WithGithubWebhookSecret(secret string) OptionFunc {
+	return func(s *Server) {
+		s.githubWebhookSecret = []byte(secret)
+	}
+}
+
 func WithShutdownTimeout(timeout int) OptionFunc {
 	return func(s *Server) {
 		s.shutdownTimeout = timeout
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const weekLayout = "2006-01-02"
+
+type (
+	// StatusReportReq is the report of the week which Week belongs to, the current week by default
+	StatusReportReq struct {
+		UserID    uuid.UUID `json:"-"`
+		ProjectID int       `json:"-"`
+		Week      time.Time `json:"week"`
+		Done      string    `json:"done"`
+		Planned   string    `json:"planned"`
+		Blockers  string    `json:"blockers"`
+	}
+	StatusReportCommentReq struct {
+		UserID    uuid.UUID `json:"-"`
+		ProjectID int       `json:"-"`
+		ReportID  int       `json:"-"`
+		Comment   string    `json:"comment"`
+	}
+
+	statusReportResp struct {
+		ID          int        `json:"id"`
+		ProjectID   int        `json:"projectId"`
+		WeekStart   string     `json:"week"`
+		Done        string     `json:"done"`
+		Planned     string     `json:"planned"`
+		Blockers    string     `json:"blockers"`
+		TasksDone   int        `json:"tasksDone"`
+		Commits     int        `json:"commits"`
+		SubmittedBy *uuid.UUID `json:"submittedBy,omitempty"`
+		SubmittedAt time.Time  `json:"submittedAt"`
+		UpdatedAt   time.Time  `json:"updatedAt"`
+		PMComment   string     `json:"pmComment"`
+		CommentedAt *time.Time `json:"commentedAt,omitempty"`
+	}
+	userCommitsResp struct {
+		User    model.ShortUser `json:"user"`
+		Commits int             `json:"commits"`
+	}
+	statusReportDraftResp struct {
+		WeekStart string            `json:"week"`
+		DoneTasks []ShortTaskResp   `json:"doneTasks"`
+		Commits   []userCommitsResp `json:"commits"`
+		Report    *statusReportResp `json:"report"`
+	}
+	weekStatusReportResp struct {
+		ProjectID   int               `json:"projectId"`
+		ProjectName string            `json:"projectName"`
+		Course      string            `json:"course"`
+		Submitted   bool              `json:"submitted"`
+		Report      *statusReportResp `json:"report"`
+	}
+)
+
+// getStatusReportDraft returns the tasks done and the commits of the week from the "week" query
+// parameter (yyyy-mm-dd, any day of the week), and the report if it has already been submitted
+func (s *Server) getStatusReportDraft(c *gin.Context) {
+	week, err := parseWeekQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	draft, err := s.svc.GetStatusReportDraft(c.Request.Context(), c.MustGet(string(domain.ProjectIDCtx)).(int), week)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := statusReportDraftResp{
+		WeekStart: draft.WeekStart.Format(weekLayout),
+		DoneTasks: make([]ShortTaskResp, 0, len(draft.DoneTasks)),
+		Commits:   make([]userCommitsResp, 0, len(draft.Commits)),
+	}
+	for _, task := range draft.DoneTasks {
+		resp.DoneTasks = append(resp.DoneTasks, castShortTaskResponse(task.ShortTask))
+	}
+	for _, commits := range draft.Commits {
+		resp.Commits = append(resp.Commits, userCommitsResp{User: commits.ShortUser, Commits: commits.Commits})
+	}
+	if draft.Report != nil {
+		report := makeStatusReportResponse(*draft.Report)
+		resp.Report = &report
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) submitStatusReport(c *gin.Context) {
+	reportReq := &StatusReportReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(reportReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	reportReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	reportReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	report, err := s.svc.SubmitStatusReport(c.Request.Context(), reportReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeStatusReportResponse(*report))
+}
+
+func (s *Server) getStatusReports(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	reports, err := s.svc.GetStatusReports(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]statusReportResp, 0, len(reports))
+	for _, report := range reports {
+		res = append(res, makeStatusReportResponse(report))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) commentStatusReport(c *gin.Context) {
+	commentReq := &StatusReportCommentReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(commentReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if commentReq.ReportID, err = strconv.Atoi(c.Param("reportId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	commentReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	commentReq.UserID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	report, err := s.svc.CommentStatusReport(c.Request.Context(), commentReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeStatusReportResponse(*report))
+}
+
+func (s *Server) exportStatusReports(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	format := model.StatusReportsFormat(c.DefaultQuery("format", string(model.StatusReportsXLSX)))
+	data, err := s.svc.ExportStatusReports(c.Request.Context(), projectID, format)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		Data string `json:"data"`
+		Name string `json:"name"`
+	}{
+		Data: base64.StdEncoding.EncodeToString(data),
+		Name: fmt.Sprintf("status-reports-%v.%s", projectID, format),
+	})
+}
+
+// getWeekStatusReports shows which of the PM's projects have not submitted the report of the week
+func (s *Server) getWeekStatusReports(c *gin.Context) {
+	s.sendWeekStatusReports(c, c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+}
+
+// getAllWeekStatusReports is the same overview for all the projects
+func (s *Server) getAllWeekStatusReports(c *gin.Context) {
+	s.sendWeekStatusReports(c, uuid.Nil)
+}
+
+func (s *Server) sendWeekStatusReports(c *gin.Context, ownerID uuid.UUID) {
+	week, err := parseWeekQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	projects, err := s.svc.GetWeekStatusReports(c.Request.Context(), ownerID, week)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]weekStatusReportResp, 0, len(projects))
+	for _, project := range projects {
+		resp := weekStatusReportResp{
+			ProjectID:   project.Project.ID,
+			ProjectName: project.Project.Name,
+			Course:      project.Project.Course.String,
+			Submitted:   project.Report != nil,
+		}
+		if project.Report != nil {
+			report := makeStatusReportResponse(*project.Report)
+			resp.Report = &report
+		}
+		res = append(res, resp)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// parseWeekQuery returns the day from the "week" query parameter, today by default
+func parseWeekQuery(c *gin.Context) (time.Time, error) {
+	week := c.Query("week")
+	if week == "" {
+		return time.Now(), nil
+	}
+	return time.Parse(weekLayout, week)
+}
+
+func makeStatusReportResponse(report model.StatusReport) statusReportResp {
+	resp := statusReportResp{
+		ID:          report.ID,
+		ProjectID:   report.ProjectID,
+		WeekStart:   report.WeekStart.Format(weekLayout),
+		Done:        report.Done,
+		Planned:     report.Planned,
+		Blockers:    report.Blockers,
+		TasksDone:   report.TasksDone,
+		Commits:     report.Commits,
+		SubmittedAt: report.SubmittedAt,
+		UpdatedAt:   report.UpdatedAt,
+		PMComment:   report.PMComment.String,
+	}
+	if report.SubmittedBy.Valid {
+		resp.SubmittedBy = &report.SubmittedBy.UUID
+	}
+	if report.CommentedAt.Valid {
+		resp.CommentedAt = &report.CommentedAt.Time
+	}
+	return resp
+}
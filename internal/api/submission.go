@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	// SubmitReportReq has either an uploaded file or a link to the report
+	SubmitReportReq struct {
+		ProjectID int
+		File      *UploadFileReq
+		URL       string
+		Name      string
+		Comment   string
+	}
+	ReviewSubmissionReq struct {
+		ProjectID    int       `json:"-"`
+		SubmissionID int       `json:"-"`
+		ReviewerID   uuid.UUID `json:"-"`
+		Status       string    `json:"status"`
+		Comment      string    `json:"comment"`
+	}
+	submissionResp struct {
+		ID            int        `json:"id"`
+		Version       int        `json:"version"`
+		FileID        int        `json:"fileId,omitempty"`
+		URL           string     `json:"url"`
+		Name          string     `json:"name"`
+		Comment       string     `json:"comment"`
+		SubmittedBy   *uuid.UUID `json:"submittedBy,omitempty"`
+		SubmittedAt   time.Time  `json:"submittedAt"`
+		Late          bool       `json:"late"`
+		Status        string     `json:"status"`
+		ReviewerID    *uuid.UUID `json:"reviewerId,omitempty"`
+		ReviewComment string     `json:"reviewComment"`
+		ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+	}
+	courseSubmissionResp struct {
+		ProjectID   int             `json:"projectId"`
+		ProjectName string          `json:"projectName"`
+		Course      string          `json:"course"`
+		DueDate     time.Time       `json:"dueDate"`
+		Submitted   bool            `json:"submitted"`
+		Versions    int             `json:"versions"`
+		Last        *submissionResp `json:"last"`
+	}
+)
+
+// submitProjectReport takes a multipart form with the report "file" or the "url" field,
+// and the optional "name" and "comment" fields
+func (s *Server) submitProjectReport(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	submitReq := &SubmitReportReq{ProjectID: projectID}
+	fileReq, closeFile, err := s.parseFormFile(c)
+	switch {
+	case errors.Is(err, http.ErrMissingFile):
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	default:
+		defer closeFile()
+		submitReq.File = fileReq
+	}
+	submitReq.URL = c.PostForm("url")
+	submitReq.Name = c.PostForm("name")
+	submitReq.Comment = c.PostForm("comment")
+
+	submission, err := s.svc.SubmitProjectReport(c.Request.Context(), submitReq)
+	if err != nil {
+		abortWithUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeSubmissionResponse(*submission))
+}
+
+func (s *Server) getProjectSubmissions(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	submissions, err := s.svc.GetProjectSubmissions(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]submissionResp, 0, len(submissions))
+	for _, submission := range submissions {
+		res = append(res, makeSubmissionResponse(submission))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) reviewSubmission(c *gin.Context) {
+	reviewReq := &ReviewSubmissionReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(reviewReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if reviewReq.SubmissionID, err = strconv.Atoi(c.Param("submissionId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	reviewReq.ProjectID = c.MustGet(string(domain.ProjectIDCtx)).(int)
+	reviewReq.ReviewerID = c.MustGet(string(domain.UserIDCtx)).(uuid.UUID)
+
+	submission, err := s.svc.ReviewSubmission(c.Request.Context(), reviewReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeSubmissionResponse(*submission))
+}
+
+// getCourseSubmissions shows which teams of the PM's projects have submitted the report,
+// the course is taken from the "course" query parameter
+func (s *Server) getCourseSubmissions(c *gin.Context) {
+	projects, err := s.svc.GetCourseSubmissions(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), c.Query("course"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res := make([]courseSubmissionResp, 0, len(projects))
+	for _, project := range projects {
+		resp := courseSubmissionResp{
+			ProjectID:   project.Project.ID,
+			ProjectName: project.Project.Name,
+			Course:      project.Project.Course.String,
+			DueDate:     project.Project.ActiveTo,
+			Submitted:   project.Last != nil,
+			Versions:    project.Versions,
+		}
+		if project.Last != nil {
+			last := makeSubmissionResponse(*project.Last)
+			resp.Last = &last
+		}
+		res = append(res, resp)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func makeSubmissionResponse(submission model.ReportSubmission) submissionResp {
+	resp := submissionResp{
+		ID:            submission.ID,
+		Version:       submission.Version,
+		FileID:        int(submission.FileID.Int64),
+		URL:           submission.URL.String,
+		Name:          submission.Name,
+		Comment:       submission.Comment.String,
+		SubmittedAt:   submission.SubmittedAt,
+		Late:          submission.Late,
+		Status:        string(submission.Status),
+		ReviewComment: submission.ReviewComment.String,
+	}
+	if submission.SubmittedBy.Valid {
+		resp.SubmittedBy = &submission.SubmittedBy.UUID
+	}
+	if submission.ReviewerID.Valid {
+		resp.ReviewerID = &submission.ReviewerID.UUID
+	}
+	if submission.ReviewedAt.Valid {
+		resp.ReviewedAt = &submission.ReviewedAt.Time
+	}
+	return resp
+}
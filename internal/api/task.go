@@ -4,7 +4,10 @@ import (
 	"be-project-monitoring/internal/domain"
 	"be-project-monitoring/internal/domain/model"
 	ierr "be-project-monitoring/internal/errors"
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -15,24 +18,31 @@ import (
 
 type (
 	CreateTaskReq struct {
-		Name              string `json:"title"`
-		Description       string `json:"description"`
-		SuggestedEstimate int    `json:"estimatedTime"`
-		ParticipantID     *int   `json:"asignee"`
-		Status            string `json:"status"`
-		ProjectID         int    `json:"projectId"`
+		Name              string     `json:"title"`
+		Description       string     `json:"description"`
+		SuggestedEstimate int        `json:"estimatedTime"`
+		ParticipantID     *int       `json:"asignee"`
+		Status            string     `json:"status"`
+		Labels            []string   `json:"labels"`
+		DueDate           *time.Time `json:"dueDate"`
+		ProjectID         int        `json:"projectId"`
 	}
 	ShortTaskResp struct {
-		ID            int       `json:"id"`
-		Name          string    `json:"title"`
-		Status        string    `json:"status"`
-		Description   string    `json:"description"`
-		Estimate      int       `json:"estimatedTime"`
-		CreatedAt     time.Time `json:"createdAt"`
-		UpdatedAt     time.Time `json:"updatedAt"`
-		Approved      bool      `json:"approved"`
-		ParticipantID int       `json:"asignee,omitempty"`
-		CreatorID     int       `json:"creatorId,omitempty"`
+		ID            int        `json:"id"`
+		Name          string     `json:"title"`
+		Status        string     `json:"status"`
+		Description   string     `json:"description"`
+		Estimate      int        `json:"estimatedTime"`
+		CreatedAt     time.Time  `json:"createdAt"`
+		UpdatedAt     time.Time  `json:"updatedAt"`
+		Approved      bool       `json:"approved"`
+		Labels        []string   `json:"labels"`
+		DueDate       *time.Time `json:"dueDate,omitempty"`
+		DoneAt        *time.Time `json:"doneAt,omitempty"`
+		Rank          string     `json:"rank"`
+		Version       int        `json:"version"`
+		ParticipantID int        `json:"asignee,omitempty"`
+		CreatorID     int        `json:"creatorId,omitempty"`
 	}
 	TaskResp struct {
 		ShortTaskResp
@@ -43,6 +53,22 @@ type (
 		TaskResp
 		// Creator     model.ShortUser `json:"creator"`
 		// Participant model.ShortUser `json:"asignee"`
+		Links []taskLinkResp `json:"links"`
+	}
+	// taskLinkResp is the commit (sha) or the pull request (number) referencing the task
+	taskLinkResp struct {
+		ID             int                       `json:"id"`
+		RepositoryID   int                       `json:"repositoryId"`
+		Kind           model.RepositoryEventKind `json:"kind"`
+		SHA            string                    `json:"sha,omitempty"`
+		Number         int                       `json:"number,omitempty"`
+		Title          string                    `json:"title"`
+		URL            string                    `json:"url"`
+		GithubUsername string                    `json:"githubUsername"`
+		State          string                    `json:"state,omitempty"`
+		Additions      int                       `json:"additions"`
+		Deletions      int                       `json:"deletions"`
+		LinkedAt       time.Time                 `json:"linkedAt"`
 	}
 	GetTasksReq struct {
 		ProjectID     int
@@ -53,16 +79,50 @@ type (
 		Limit         int
 	}
 	UpdateTaskReq struct {
-		ID                int     `json:"id"`
-		Name              *string `json:"title"`
-		Description       *string `json:"description"`
-		SuggestedEstimate *int    `json:"estimatedTime"`
-		Status            *string `json:"status"`
-		ParticipantID     *int    `json:"asignee"`
-		ProjectID         int     `json:"projectId"`
-		Approved          *bool   `json:"approved"`
+		ID                int       `json:"id"`
+		Name              *string   `json:"title"`
+		Description       *string   `json:"description"`
+		SuggestedEstimate *int      `json:"estimatedTime"`
+		Status            *string   `json:"status"`
+		ParticipantID     *int      `json:"asignee"`
+		ProjectID         int       `json:"projectId"`
+		Approved          *bool     `json:"approved"`
+		Labels            *[]string `json:"labels"`
+		// DueDate is removed if it is set to zero time
+		DueDate *time.Time `json:"dueDate"`
 		//ChangeParticipant *bool   `json:"change_participant"`
 	}
+	BulkTaskReq struct {
+		TaskIDs       []int    `json:"taskIds"`
+		Action        string   `json:"action"`
+		ParticipantID *int     `json:"asignee"`
+		Status        *string  `json:"status"`
+		Labels        []string `json:"labels"`
+		Approved      *bool    `json:"approved"`
+		ProjectID     int      `json:"-"`
+	}
+	bulkTaskResp struct {
+		Error   string               `json:"error,omitempty"`
+		Applied bool                 `json:"applied"`
+		Results []bulkTaskResultResp `json:"results"`
+	}
+	bulkTaskResultResp struct {
+		ID    int    `json:"id"`
+		Error string `json:"error,omitempty"`
+	}
+	importTasksReq struct {
+		Format string `json:"format"`
+		Data   []byte `json:"data"`
+	}
+	importTasksResp struct {
+		Imported []TaskResp          `json:"imported"`
+		Rows     []importTaskRowResp `json:"rows"`
+	}
+	importTaskRowResp struct {
+		Line    int    `json:"line"`
+		Title   string `json:"title,omitempty"`
+		Warning string `json:"warning"`
+	}
 )
 
 func (s *Server) getTasks(c *gin.Context) {
@@ -82,7 +142,7 @@ func (s *Server) getTasks(c *gin.Context) {
 	taskReq.Offset, _ = strconv.Atoi(c.Query("offset"))
 	taskReq.Limit, _ = strconv.Atoi(c.Query("limit"))
 
-	tasks, count, err := s.svc.GetTasks(c, taskReq)
+	tasks, count, err := s.svc.GetTasks(c.Request.Context(), taskReq)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
@@ -113,7 +173,7 @@ func (s *Server) createTask(c *gin.Context) {
 	}
 	taskReq.ProjectID = projectID
 
-	task, err := s.svc.CreateTask(c, c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), taskReq)
+	task, err := s.svc.CreateTask(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), taskReq)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
@@ -133,7 +193,7 @@ func (s *Server) updateTask(c *gin.Context) {
 	}
 	taskReq.ProjectID = projectID
 
-	task, err := s.svc.UpdateTask(c, taskReq)
+	task, err := s.svc.UpdateTask(c.Request.Context(), taskReq)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
@@ -150,13 +210,105 @@ func (s *Server) deleteTask(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
-	if err := s.svc.DeleteTask(c, deletedTask.ID); err != nil {
+	if err := s.svc.DeleteTask(c.Request.Context(), deletedTask.ID); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, nil)
 }
 
+func (s *Server) bulkUpdateTasks(c *gin.Context) {
+	bulkReq := &BulkTaskReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(bulkReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	bulkReq.ProjectID = projectID
+
+	results, err := s.svc.BulkUpdateTasks(c.Request.Context(), bulkReq)
+
+	resp := bulkTaskResp{
+		Applied: err == nil,
+		Results: make([]bulkTaskResultResp, 0, len(results)),
+	}
+	for _, result := range results {
+		resultResp := bulkTaskResultResp{ID: result.TaskID}
+		if result.Err != nil {
+			resultResp.Error = result.Err.Error()
+		}
+		resp.Results = append(resp.Results, resultResp)
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+		c.AbortWithStatusJSON(http.StatusBadRequest, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) exportTasks(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	format := model.BacklogFormat(c.DefaultQuery("format", string(model.FormatCSV)))
+	data, err := s.svc.ExportTasks(c.Request.Context(), projectID, format)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, struct {
+		Data string `json:"data"`
+		Name string `json:"name"`
+	}{
+		Data: base64.StdEncoding.EncodeToString(data),
+		Name: fmt.Sprintf("backlog-%v.%s", projectID, format),
+	})
+}
+
+func (s *Server) importTasks(c *gin.Context) {
+	importReq := &importTasksReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(importReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	backlogImport, err := s.svc.ImportTasks(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		projectID, model.BacklogFormat(importReq.Format), importReq.Data)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	rows := make([]importTaskRowResp, 0, len(backlogImport.Rows))
+	for _, row := range backlogImport.Rows {
+		rows = append(rows, importTaskRowResp{
+			Line:    row.Line,
+			Title:   row.Title,
+			Warning: row.Warning,
+		})
+	}
+
+	c.JSON(http.StatusCreated, importTasksResp{
+		Imported: makeTasksResponses(backlogImport.Tasks),
+		Rows:     rows,
+	})
+}
+
 func (s *Server) getTaskInfo(c *gin.Context) {
 	taskID, err := strconv.Atoi(c.Param("taskId"))
 	if err != nil {
@@ -170,10 +322,29 @@ func (s *Server) getTaskInfo(c *gin.Context) {
 		return
 	}
 
+	links := make([]taskLinkResp, 0, len(taskInfo.Links))
+	for _, link := range taskInfo.Links {
+		links = append(links, taskLinkResp{
+			ID:             link.ID,
+			RepositoryID:   link.RepositoryID,
+			Kind:           link.Kind,
+			SHA:            link.SHA,
+			Number:         link.Number,
+			Title:          link.Title,
+			URL:            link.URL,
+			GithubUsername: link.GithubUsername,
+			State:          link.State,
+			Additions:      link.Additions,
+			Deletions:      link.Deletions,
+			LinkedAt:       link.LinkedAt,
+		})
+	}
+
 	c.JSON(http.StatusOK, taskInfoResp{
 		TaskResp: makeTaskResponse(taskInfo.Task),
 		// Creator:     taskInfo.Creator,
 		// Participant: taskInfo.Participant,
+		Links: links,
 	})
 }
 
@@ -188,6 +359,11 @@ func makeTaskResponse(task model.Task) TaskResp {
 			CreatorID:     int(task.CreatorID.Int64),
 			Status:        string(task.Status),
 			Approved:      task.Approved.Bool,
+			Labels:        task.Labels,
+			DueDate:       dueDateResp(task.DueDate),
+			DoneAt:        dueDateResp(task.DoneAt),
+			Rank:          task.Rank,
+			Version:       task.Version,
 			CreatedAt:     task.CreatedAt,
 			UpdatedAt:     task.UpdatedAt,
 		},
@@ -204,6 +380,11 @@ func castShortTaskResponse(task model.ShortTask) ShortTaskResp {
 		CreatorID:     int(task.CreatorID.Int64),
 		Status:        string(task.Status),
 		Approved:      task.Approved.Bool,
+		Labels:        task.Labels,
+		DueDate:       dueDateResp(task.DueDate),
+		DoneAt:        dueDateResp(task.DoneAt),
+		Rank:          task.Rank,
+		Version:       task.Version,
 		CreatedAt:     task.CreatedAt,
 		UpdatedAt:     task.UpdatedAt,
 	}
@@ -224,3 +405,10 @@ func makeShortTasksResponses(tasks []model.ShortTask) []ShortTaskResp {
 	}
 	return taskResponses
 }
+
+func dueDateResp(dueDate sql.NullTime) *time.Time {
+	if !dueDate.Valid {
+		return nil
+	}
+	return &dueDate.Time
+}
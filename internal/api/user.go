@@ -2,6 +2,7 @@ package api
 
 import (
 	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -157,6 +158,16 @@ func (s *Server) getUserProjects(c *gin.Context) {
 		return
 	}
 
+	projectIDs := make([]int, 0, len(userProfile.UserProjects))
+	for _, project := range userProfile.UserProjects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	progress, err := s.svc.GetChecklistProgress(ctx, projectIDs)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
 	projectResponses := make([]projectWithShortParticipantsResp, 0)
 	for _, project := range userProfile.UserProjects {
 		participants, err := s.svc.GetParticipants(ctx, project.ID)
@@ -172,8 +183,38 @@ func (s *Server) getUserProjects(c *gin.Context) {
 				Description: project.Description.String,
 				PhotoURL:    project.PhotoURL.String,
 				ActiveTo:    project.ActiveTo,
+
+				ChecklistProgress: progress[project.ID].Percent(),
 			},
 		})
 	}
 	c.JSON(http.StatusOK, projectResponses)
 }
+
+// getVCSAccounts returns the user's usernames at the self-hosted gitlab and gitea, which the commits are matched by
+func (s *Server) getVCSAccounts(c *gin.Context) {
+	accounts, err := s.svc.GetVCSAccounts(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (s *Server) updateVCSAccounts(c *gin.Context) {
+	var accounts []model.VCSAccount
+	if err := json.NewDecoder(c.Request.Body).Decode(&accounts); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	res, err := s.svc.UpdateVCSAccounts(c.Request.Context(),
+		c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), accounts)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	CreateWorklogReq struct {
+		Duration  int       `json:"duration"`
+		Date      time.Time `json:"date"`
+		Note      string    `json:"note"`
+		TaskID    int       `json:"-"`
+		ProjectID int       `json:"-"`
+	}
+
+	worklogResp struct {
+		ID            int        `json:"id"`
+		TaskID        int        `json:"taskId"`
+		ParticipantID int        `json:"participantId"`
+		Duration      int        `json:"duration"`
+		Date          time.Time  `json:"date"`
+		Note          string     `json:"note"`
+		StartedAt     *time.Time `json:"startedAt,omitempty"`
+		Running       bool       `json:"running"`
+	}
+
+	taskWorklogsResp struct {
+		TaskID        int           `json:"taskId"`
+		Estimate      int           `json:"estimatedTime"`
+		ActualMinutes int           `json:"actualMinutes"`
+		ActualHours   float64       `json:"actualHours"`
+		Worklogs      []worklogResp `json:"worklogs"`
+	}
+
+	weeklyWorklogResp struct {
+		ParticipantID int             `json:"participantId"`
+		User          model.ShortUser `json:"user"`
+		WeekStart     time.Time       `json:"weekStart"`
+		TotalMinutes  int             `json:"totalMinutes"`
+	}
+)
+
+func (s *Server) createWorklog(c *gin.Context) {
+	worklogReq := &CreateWorklogReq{}
+	if err := json.NewDecoder(c.Request.Body).Decode(worklogReq); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	var err error
+	if worklogReq.ProjectID, err = strconv.Atoi(c.Param("projectId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	if worklogReq.TaskID, err = strconv.Atoi(c.Param("taskId")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	worklog, err := s.svc.AddWorklog(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), worklogReq)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeWorklogResponse(*worklog))
+}
+
+func (s *Server) getTaskWorklogs(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	taskWorklogs, err := s.svc.GetTaskWorklogs(c.Request.Context(), projectID, taskID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	worklogs := make([]worklogResp, 0, len(taskWorklogs.Worklogs))
+	for _, worklog := range taskWorklogs.Worklogs {
+		worklogs = append(worklogs, makeWorklogResponse(worklog))
+	}
+
+	c.JSON(http.StatusOK, taskWorklogsResp{
+		TaskID:        taskWorklogs.TaskID,
+		Estimate:      int(taskWorklogs.Estimate.Int64),
+		ActualMinutes: taskWorklogs.Actual,
+		ActualHours:   minutesToHours(taskWorklogs.Actual),
+		Worklogs:      worklogs,
+	})
+}
+
+func (s *Server) deleteWorklog(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	deletedWorklog := &struct {
+		ID int `json:"id"`
+	}{}
+	if err := json.NewDecoder(c.Request.Body).Decode(deletedWorklog); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	if err := s.svc.DeleteWorklog(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		projectID, taskID, deletedWorklog.ID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, nil)
+}
+
+func (s *Server) startTimer(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	worklog, err := s.svc.StartTimer(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID), projectID, taskID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, makeWorklogResponse(*worklog))
+}
+
+func (s *Server) stopTimer(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	// Body is optional, the note can be left when the timer is stopped
+	stopReq := &struct {
+		Note string `json:"note"`
+	}{}
+	if c.Request.ContentLength > 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(stopReq); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+			return
+		}
+	}
+
+	worklog, err := s.svc.StopTimer(c.Request.Context(), c.MustGet(string(domain.UserIDCtx)).(uuid.UUID),
+		projectID, taskID, stopReq.Note)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, makeWorklogResponse(*worklog))
+}
+
+func (s *Server) getWeeklyWorklogs(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("projectId"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	weeklyWorklogs, err := s.svc.GetWeeklyWorklogs(c.Request.Context(), projectID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{errField: err.Error()})
+		return
+	}
+
+	resp := make([]weeklyWorklogResp, 0, len(weeklyWorklogs))
+	for _, weekly := range weeklyWorklogs {
+		resp = append(resp, weeklyWorklogResp{
+			ParticipantID: weekly.ParticipantID,
+			User:          weekly.ShortUser,
+			WeekStart:     weekly.WeekStart,
+			TotalMinutes:  weekly.TotalMinutes,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func makeWorklogResponse(worklog model.Worklog) worklogResp {
+	resp := worklogResp{
+		ID:            worklog.ID,
+		TaskID:        worklog.TaskID,
+		ParticipantID: worklog.ParticipantID,
+		Duration:      worklog.Duration,
+		Date:          worklog.Date,
+		Note:          worklog.Note.String,
+		Running:       worklog.Running,
+	}
+	if worklog.StartedAt.Valid {
+		resp.StartedAt = &worklog.StartedAt.Time
+	}
+	return resp
+}
+
+func minutesToHours(minutes int) float64 {
+	return float64(minutes*100/60) / 100
+}
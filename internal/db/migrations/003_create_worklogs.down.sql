@@ -0,0 +1,4 @@
+BEGIN;
+
+DROP TABLE worklogs;
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE worklogs
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    task_id        BIGINT REFERENCES tasks (id) ON DELETE CASCADE,
+    participant_id BIGINT REFERENCES participants (id) ON DELETE CASCADE,
+    duration       INT       NOT NULL DEFAULT 0,
+    date           DATE      NOT NULL DEFAULT CURRENT_DATE,
+    note           TEXT,
+    started_at     TIMESTAMP,
+    running        BOOLEAN   NOT NULL DEFAULT false,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX worklogs_running_idx ON worklogs (task_id, participant_id) WHERE running;
+
+COMMIT;
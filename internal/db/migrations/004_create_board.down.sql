@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE board_columns;
+ALTER TABLE tasks
+    DROP COLUMN rank,
+    DROP COLUMN version;
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE tasks
+    ADD COLUMN rank    VARCHAR COLLATE "C" NOT NULL DEFAULT '',
+    ADD COLUMN version INT                 NOT NULL DEFAULT 0;
+
+UPDATE tasks t
+SET rank = LPAD(r.num::text, 10, '0')
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id, status ORDER BY created_at, id) num
+      FROM tasks) r
+WHERE r.id = t.id;
+
+CREATE TABLE board_columns
+(
+    project_id BIGINT REFERENCES projects (id) ON DELETE CASCADE,
+    status     status NOT NULL,
+    wip_limit  INT,
+    PRIMARY KEY (project_id, status)
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE tasks
+    DROP COLUMN labels;
+COMMIT;
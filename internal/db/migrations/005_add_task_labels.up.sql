@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE tasks
+    ADD COLUMN labels VARCHAR[] NOT NULL DEFAULT '{}';
+
+COMMIT;
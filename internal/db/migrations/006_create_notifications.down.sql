@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE notification_preferences;
+DROP TABLE task_watchers;
+DROP TABLE notifications;
+COMMIT;
@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TABLE notifications
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    user_id    uuid REFERENCES users (id) ON DELETE CASCADE,
+    project_id BIGINT REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    BIGINT REFERENCES tasks (id) ON DELETE SET NULL,
+    event      VARCHAR   NOT NULL,
+    message    TEXT      NOT NULL,
+    read       BOOLEAN   NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX notifications_user_idx ON notifications (user_id, read);
+
+CREATE TABLE task_watchers
+(
+    task_id BIGINT REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE TABLE notification_preferences
+(
+    user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+    event   VARCHAR NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    PRIMARY KEY (user_id, event)
+);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE notification_deliveries;
+DROP TABLE user_channels;
+COMMIT;
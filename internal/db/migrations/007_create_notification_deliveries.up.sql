@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE user_channels
+(
+    user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+    channel VARCHAR NOT NULL,
+    address VARCHAR NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    digest  BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (user_id, channel)
+);
+
+CREATE TABLE notification_deliveries
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    notification_id BIGINT REFERENCES notifications (id) ON DELETE CASCADE,
+    user_id         uuid REFERENCES users (id) ON DELETE CASCADE,
+    channel         VARCHAR   NOT NULL,
+    digest          BOOLEAN   NOT NULL DEFAULT false,
+    status          VARCHAR   NOT NULL DEFAULT 'PENDING',
+    attempts        INT       NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMP
+);
+
+CREATE INDEX notification_deliveries_pending_idx ON notification_deliveries (next_attempt_at) WHERE status = 'PENDING';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS deadline_reminders;
+
+ALTER TABLE tasks
+    DROP COLUMN due_date;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE tasks
+    ADD COLUMN due_date DATE;
+
+CREATE TABLE deadline_reminders
+(
+    project_id BIGINT REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    BIGINT REFERENCES tasks (id) ON DELETE CASCADE,
+    kind       VARCHAR   NOT NULL,
+    due_date   DATE      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX deadline_reminders_uniq ON deadline_reminders (project_id, (COALESCE(task_id, 0)), kind, due_date);
+
+COMMIT;
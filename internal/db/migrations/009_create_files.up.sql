@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE files
+(
+    id           BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    key          VARCHAR   NOT NULL UNIQUE,
+    name         VARCHAR   NOT NULL,
+    content_type VARCHAR   NOT NULL,
+    size         BIGINT    NOT NULL,
+    kind         VARCHAR   NOT NULL,
+    project_id   BIGINT REFERENCES projects (id) ON DELETE SET NULL,
+    task_id      BIGINT REFERENCES tasks (id) ON DELETE SET NULL,
+    uploaded_by  uuid REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP
+);
+
+CREATE INDEX files_project_id_idx ON files (project_id);
+CREATE INDEX files_task_id_idx ON files (task_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS report_submissions;
+
+ALTER TABLE projects
+    DROP COLUMN course;
+
+COMMIT;
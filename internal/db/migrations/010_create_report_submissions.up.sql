@@ -0,0 +1,25 @@
+BEGIN;
+
+ALTER TABLE projects
+    ADD COLUMN course VARCHAR;
+
+CREATE TABLE report_submissions
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id     BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    version        INT       NOT NULL,
+    file_id        BIGINT REFERENCES files (id) ON DELETE SET NULL,
+    url            VARCHAR,
+    name           VARCHAR   NOT NULL,
+    comment        TEXT,
+    submitted_by   uuid REFERENCES users (id) ON DELETE SET NULL,
+    submitted_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    late           BOOLEAN   NOT NULL DEFAULT false,
+    status         VARCHAR   NOT NULL DEFAULT 'SUBMITTED',
+    reviewer_id    uuid REFERENCES users (id) ON DELETE SET NULL,
+    review_comment TEXT,
+    reviewed_at    TIMESTAMP,
+    UNIQUE (project_id, version)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS grades;
+DROP TABLE IF EXISTS rubric_criteria;
+DROP TABLE IF EXISTS rubrics;
+
+COMMIT;
@@ -0,0 +1,40 @@
+BEGIN;
+
+CREATE TABLE rubrics
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    owner_id   uuid      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR   NOT NULL,
+    course     VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE rubric_criteria
+(
+    id            BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    rubric_id     BIGINT           NOT NULL REFERENCES rubrics (id) ON DELETE CASCADE,
+    name          VARCHAR          NOT NULL,
+    weight        DOUBLE PRECISION NOT NULL DEFAULT 1,
+    max_points    DOUBLE PRECISION NOT NULL,
+    scope         VARCHAR          NOT NULL,
+    metric        VARCHAR          NOT NULL DEFAULT '',
+    metric_target DOUBLE PRECISION NOT NULL DEFAULT 0,
+    position      INT              NOT NULL DEFAULT 0
+);
+
+CREATE TABLE grades
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    criterion_id   BIGINT           NOT NULL REFERENCES rubric_criteria (id) ON DELETE CASCADE,
+    project_id     BIGINT           NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    participant_id BIGINT REFERENCES participants (id) ON DELETE CASCADE,
+    points         DOUBLE PRECISION NOT NULL,
+    comment        TEXT,
+    auto           BOOLEAN          NOT NULL DEFAULT false,
+    graded_by      uuid REFERENCES users (id) ON DELETE SET NULL,
+    updated_at     TIMESTAMP        NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX grades_uniq ON grades (criterion_id, project_id, (COALESCE(participant_id, 0)));
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS peer_reviews;
+DROP TABLE IF EXISTS peer_rounds;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE peer_rounds
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR   NOT NULL,
+    criteria   VARCHAR[] NOT NULL,
+    max_score  INT       NOT NULL,
+    opened_by  uuid REFERENCES users (id) ON DELETE SET NULL,
+    opened_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closes_at  TIMESTAMP,
+    closed_at  TIMESTAMP
+);
+
+CREATE INDEX peer_rounds_project_id_idx ON peer_rounds (project_id);
+
+CREATE TABLE peer_reviews
+(
+    id           BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    round_id     BIGINT    NOT NULL REFERENCES peer_rounds (id) ON DELETE CASCADE,
+    reviewer_id  BIGINT    NOT NULL REFERENCES participants (id) ON DELETE CASCADE,
+    reviewee_id  BIGINT    NOT NULL REFERENCES participants (id) ON DELETE CASCADE,
+    scores       INT[]     NOT NULL,
+    comment      TEXT,
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (round_id, reviewer_id, reviewee_id)
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS status_reports;
+
+ALTER TABLE tasks
+    DROP COLUMN done_at;
+
+COMMIT;
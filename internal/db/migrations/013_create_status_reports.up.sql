@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE tasks
+    ADD COLUMN done_at TIMESTAMP;
+
+UPDATE tasks
+SET done_at = updated_at
+WHERE status = 'DONE';
+
+CREATE TABLE status_reports
+(
+    id           BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id   BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    week_start   DATE      NOT NULL,
+    done         TEXT      NOT NULL DEFAULT '',
+    planned      TEXT      NOT NULL DEFAULT '',
+    blockers     TEXT      NOT NULL DEFAULT '',
+    tasks_done   INT       NOT NULL DEFAULT 0,
+    commits      INT       NOT NULL DEFAULT 0,
+    submitted_by uuid REFERENCES users (id) ON DELETE SET NULL,
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    pm_comment   TEXT,
+    commented_by uuid REFERENCES users (id) ON DELETE SET NULL,
+    commented_at TIMESTAMP,
+    UNIQUE (project_id, week_start)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS meeting_action_items;
+DROP TABLE IF EXISTS meeting_attendees;
+DROP TABLE IF EXISTS meetings;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+CREATE TABLE meetings
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    kind       VARCHAR   NOT NULL,
+    title      VARCHAR   NOT NULL,
+    held_at    TIMESTAMP NOT NULL,
+    notes      TEXT      NOT NULL DEFAULT '',
+    decisions  TEXT      NOT NULL DEFAULT '',
+    created_by uuid REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX meetings_project_id_idx ON meetings (project_id, held_at);
+
+CREATE TABLE meeting_attendees
+(
+    meeting_id     BIGINT  NOT NULL REFERENCES meetings (id) ON DELETE CASCADE,
+    participant_id BIGINT  NOT NULL REFERENCES participants (id) ON DELETE CASCADE,
+    present        BOOLEAN NOT NULL,
+    PRIMARY KEY (meeting_id, participant_id)
+);
+
+CREATE TABLE meeting_action_items
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    meeting_id     BIGINT  NOT NULL REFERENCES meetings (id) ON DELETE CASCADE,
+    text           VARCHAR NOT NULL,
+    participant_id BIGINT REFERENCES participants (id) ON DELETE SET NULL,
+    due_date       TIMESTAMP,
+    task_id        BIGINT REFERENCES tasks (id) ON DELETE SET NULL,
+    position       INT     NOT NULL DEFAULT 0
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE checklist
+    ALTER COLUMN project_id DROP NOT NULL,
+    DROP COLUMN checked_at,
+    DROP COLUMN checked_by,
+    DROP COLUMN locked,
+    DROP COLUMN due_date,
+    DROP COLUMN participant_id,
+    DROP COLUMN position,
+    DROP COLUMN checklist_id;
+
+DROP TABLE IF EXISTS checklists;
+
+COMMIT;
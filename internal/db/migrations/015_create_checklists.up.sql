@@ -0,0 +1,42 @@
+BEGIN;
+
+CREATE TABLE checklists
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name       VARCHAR   NOT NULL,
+    position   INT       NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE checklist
+    ADD COLUMN checklist_id   BIGINT REFERENCES checklists (id) ON DELETE CASCADE,
+    ADD COLUMN position       INT     NOT NULL DEFAULT 0,
+    ADD COLUMN participant_id BIGINT REFERENCES participants (id) ON DELETE SET NULL,
+    ADD COLUMN due_date       TIMESTAMP,
+    ADD COLUMN locked         BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN checked_by     uuid REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN checked_at     TIMESTAMP;
+
+DELETE
+FROM checklist
+WHERE project_id IS NULL;
+
+INSERT INTO checklists (project_id, name)
+SELECT DISTINCT project_id, 'Чек-лист'
+FROM checklist;
+
+UPDATE checklist c
+SET checklist_id = l.id,
+    position     = o.position
+FROM checklists l,
+     (SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY id) - 1 AS position
+      FROM checklist) o
+WHERE l.project_id = c.project_id
+  AND o.id = c.id;
+
+ALTER TABLE checklist
+    ALTER COLUMN checklist_id SET NOT NULL,
+    ALTER COLUMN project_id SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE checklist
+    DROP COLUMN template_item_id;
+
+ALTER TABLE checklists
+    DROP COLUMN template_id;
+
+DROP TABLE IF EXISTS checklist_template_items;
+DROP TABLE IF EXISTS checklist_templates;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE checklist_templates
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    owner_id   uuid      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR   NOT NULL,
+    course     VARCHAR,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE checklist_template_items
+(
+    id          BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    template_id BIGINT  NOT NULL REFERENCES checklist_templates (id) ON DELETE CASCADE,
+    name        VARCHAR NOT NULL,
+    position    INT     NOT NULL DEFAULT 0,
+    locked      BOOLEAN NOT NULL DEFAULT false,
+    due_date    TIMESTAMP
+);
+
+ALTER TABLE checklists
+    ADD COLUMN template_id BIGINT REFERENCES checklist_templates (id) ON DELETE SET NULL;
+
+ALTER TABLE checklist
+    ADD COLUMN template_item_id BIGINT REFERENCES checklist_template_items (id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS contributor_stats;
+DROP TABLE IF EXISTS github_syncs;
+
+COMMIT;
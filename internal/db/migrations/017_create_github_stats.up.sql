@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE github_syncs
+(
+    project_id    BIGINT PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    status        VARCHAR   NOT NULL DEFAULT 'PENDING',
+    attempts      INT       NOT NULL DEFAULT 0,
+    next_sync_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claimed_until TIMESTAMP,
+    attempted_at  TIMESTAMP,
+    synced_at     TIMESTAMP,
+    error         TEXT
+);
+
+CREATE TABLE contributor_stats
+(
+    project_id      BIGINT  NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    github_username VARCHAR NOT NULL,
+    week_start      DATE    NOT NULL,
+    commits         INT     NOT NULL DEFAULT 0,
+    additions       INT     NOT NULL DEFAULT 0,
+    deletions       INT     NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, github_username, week_start)
+);
+
+COMMIT;
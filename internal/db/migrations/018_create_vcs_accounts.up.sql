@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE vcs_accounts
+(
+    user_id  uuid REFERENCES users (id) ON DELETE CASCADE,
+    host     VARCHAR NOT NULL,
+    username VARCHAR NOT NULL,
+    PRIMARY KEY (user_id, host)
+);
+
+CREATE UNIQUE INDEX vcs_accounts_host_username_idx ON vcs_accounts (host, LOWER(username));
+
+COMMIT;
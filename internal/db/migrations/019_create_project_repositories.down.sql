@@ -0,0 +1,21 @@
+BEGIN;
+
+DELETE
+FROM contributor_stats cs
+WHERE cs.repository_id <> (SELECT MIN(r.id) FROM project_repositories r WHERE r.project_id = cs.project_id);
+ALTER TABLE contributor_stats
+    DROP CONSTRAINT contributor_stats_pkey,
+    DROP COLUMN repository_id,
+    ADD PRIMARY KEY (project_id, github_username, week_start);
+
+DELETE
+FROM github_syncs s
+WHERE s.repository_id <> (SELECT MIN(r.id) FROM project_repositories r WHERE r.project_id = s.project_id);
+ALTER TABLE github_syncs
+    DROP CONSTRAINT github_syncs_pkey,
+    DROP COLUMN repository_id,
+    ADD PRIMARY KEY (project_id);
+
+DROP TABLE IF EXISTS project_repositories;
+
+COMMIT;
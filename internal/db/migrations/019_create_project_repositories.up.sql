@@ -0,0 +1,50 @@
+BEGIN;
+
+CREATE TABLE project_repositories
+(
+    id             BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id     BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    url            VARCHAR   NOT NULL,
+    provider       VARCHAR   NOT NULL,
+    default_branch VARCHAR   NOT NULL DEFAULT '',
+    role           VARCHAR   NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, url)
+);
+
+INSERT INTO project_repositories (project_id, url, provider)
+SELECT id,
+       TRIM(repo_url),
+       LOWER(SPLIT_PART(REGEXP_REPLACE(TRIM(repo_url), '^[a-zA-Z]+://(www\.)?', ''), '/', 1))
+FROM projects
+WHERE COALESCE(TRIM(repo_url), '') <> '';
+
+ALTER TABLE github_syncs
+    ADD COLUMN repository_id BIGINT REFERENCES project_repositories (id) ON DELETE CASCADE;
+UPDATE github_syncs s
+SET repository_id = r.id
+FROM project_repositories r
+WHERE r.project_id = s.project_id;
+DELETE
+FROM github_syncs
+WHERE repository_id IS NULL;
+ALTER TABLE github_syncs
+    DROP CONSTRAINT github_syncs_pkey,
+    ALTER COLUMN repository_id SET NOT NULL,
+    ADD PRIMARY KEY (repository_id);
+
+ALTER TABLE contributor_stats
+    ADD COLUMN repository_id BIGINT REFERENCES project_repositories (id) ON DELETE CASCADE;
+UPDATE contributor_stats cs
+SET repository_id = r.id
+FROM project_repositories r
+WHERE r.project_id = cs.project_id;
+DELETE
+FROM contributor_stats
+WHERE repository_id IS NULL;
+ALTER TABLE contributor_stats
+    DROP CONSTRAINT contributor_stats_pkey,
+    ALTER COLUMN repository_id SET NOT NULL,
+    ADD PRIMARY KEY (repository_id, github_username, week_start);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS repository_events;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+COMMIT;
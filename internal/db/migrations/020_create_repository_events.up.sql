@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE webhook_deliveries
+(
+    id          VARCHAR PRIMARY KEY,
+    event       VARCHAR   NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE repository_events
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    delivery_id     VARCHAR   NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    repository_id   BIGINT    NOT NULL REFERENCES project_repositories (id) ON DELETE CASCADE,
+    project_id      BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    kind            VARCHAR   NOT NULL,
+    action          VARCHAR   NOT NULL DEFAULT '',
+    github_username VARCHAR   NOT NULL DEFAULT '',
+    user_id         uuid REFERENCES users (id) ON DELETE SET NULL,
+    number          INT,
+    sha             VARCHAR,
+    ref             VARCHAR   NOT NULL DEFAULT '',
+    title           VARCHAR   NOT NULL DEFAULT '',
+    url             VARCHAR   NOT NULL DEFAULT '',
+    state           VARCHAR   NOT NULL DEFAULT '',
+    occurred_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX repository_events_project_idx ON repository_events (project_id, occurred_at);
+-- a commit pushed to several branches is saved once
+CREATE UNIQUE INDEX repository_events_commit_uniq ON repository_events (repository_id, sha) WHERE kind = 'COMMIT';
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS task_links;
+
+ALTER TABLE repository_events
+    DROP COLUMN IF EXISTS additions,
+    DROP COLUMN IF EXISTS deletions;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS auto_transition_tasks;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+ALTER TABLE projects
+    ADD COLUMN auto_transition_tasks BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE repository_events
+    ADD COLUMN additions INT NOT NULL DEFAULT 0,
+    ADD COLUMN deletions INT NOT NULL DEFAULT 0;
+
+CREATE TABLE task_links
+(
+    id              BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    task_id         BIGINT    NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    repository_id   BIGINT    NOT NULL REFERENCES project_repositories (id) ON DELETE CASCADE,
+    kind            VARCHAR   NOT NULL,
+    sha             VARCHAR   NOT NULL DEFAULT '',
+    number          INT       NOT NULL DEFAULT 0,
+    title           VARCHAR   NOT NULL DEFAULT '',
+    url             VARCHAR   NOT NULL DEFAULT '',
+    github_username VARCHAR   NOT NULL DEFAULT '',
+    user_id         uuid REFERENCES users (id) ON DELETE SET NULL,
+    state           VARCHAR   NOT NULL DEFAULT '',
+    additions       INT       NOT NULL DEFAULT 0,
+    deletions       INT       NOT NULL DEFAULT 0,
+    linked_at       TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    UNIQUE (task_id, repository_id, kind, sha, number)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS issue_sync_log;
+DROP TABLE IF EXISTS task_issues;
+DROP TABLE IF EXISTS issue_syncs;
+
+COMMIT;
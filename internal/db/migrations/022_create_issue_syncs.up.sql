@@ -0,0 +1,39 @@
+BEGIN;
+
+CREATE TABLE issue_syncs
+(
+    project_id    BIGINT PRIMARY KEY REFERENCES projects (id) ON DELETE CASCADE,
+    repository_id BIGINT  NOT NULL REFERENCES project_repositories (id) ON DELETE CASCADE,
+    enabled       BOOLEAN NOT NULL DEFAULT TRUE,
+    synced_at     TIMESTAMP NULL
+);
+
+-- task_id is cleared when the task is deleted, so its issue is not imported back
+CREATE TABLE task_issues
+(
+    repository_id    BIGINT    NOT NULL REFERENCES project_repositories (id) ON DELETE CASCADE,
+    number           INT       NOT NULL,
+    task_id          BIGINT UNIQUE REFERENCES tasks (id) ON DELETE SET NULL,
+    url              VARCHAR   NOT NULL DEFAULT '',
+    task_updated_at  TIMESTAMP NOT NULL,
+    issue_updated_at TIMESTAMP NOT NULL,
+    synced_at        TIMESTAMP NOT NULL,
+    PRIMARY KEY (repository_id, number)
+);
+
+CREATE TABLE issue_sync_log
+(
+    id         BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    project_id BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    task_id    BIGINT REFERENCES tasks (id) ON DELETE SET NULL,
+    number     INT       NOT NULL DEFAULT 0,
+    direction  VARCHAR   NOT NULL,
+    action     VARCHAR   NOT NULL,
+    conflict   BOOLEAN   NOT NULL DEFAULT FALSE,
+    message    VARCHAR   NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX issue_sync_log_project_id_idx ON issue_sync_log (project_id, created_at);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS pull_request_reviews;
+DROP TABLE IF EXISTS pull_requests;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE pull_requests
+(
+    repository_id BIGINT    NOT NULL REFERENCES project_repositories (id) ON DELETE CASCADE,
+    number        INT       NOT NULL,
+    project_id    BIGINT    NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    author_login  VARCHAR   NOT NULL DEFAULT '',
+    title         VARCHAR   NOT NULL DEFAULT '',
+    url           VARCHAR   NOT NULL DEFAULT '',
+    state         VARCHAR   NOT NULL,
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL,
+    merged_at     TIMESTAMP NULL,
+    closed_at     TIMESTAMP NULL,
+    PRIMARY KEY (repository_id, number)
+);
+
+CREATE INDEX pull_requests_project_id_idx ON pull_requests (project_id);
+
+CREATE TABLE pull_request_reviews
+(
+    id            BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    repository_id BIGINT    NOT NULL,
+    number        INT       NOT NULL,
+    author_login  VARCHAR   NOT NULL DEFAULT '',
+    state         VARCHAR   NOT NULL,
+    comments      INT       NOT NULL DEFAULT 0,
+    submitted_at  TIMESTAMP NOT NULL,
+    FOREIGN KEY (repository_id, number) REFERENCES pull_requests (repository_id, number) ON DELETE CASCADE
+);
+
+CREATE INDEX pull_request_reviews_pull_request_idx ON pull_request_reviews (repository_id, number);
+
+COMMIT;
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"be-project-monitoring/internal/domain/model"
 	"be-project-monitoring/internal/repository"
@@ -15,6 +16,25 @@ type (
 		projectRepo
 		participantRepo
 		taskRepo
+		worklogRepo
+		boardRepo
+		notificationRepo
+		deadlineRepo
+		fileRepo
+		submissionRepo
+		gradeRepo
+		peerRepo
+		statusReportRepo
+		meetingRepo
+		checklistRepo
+		checklistTemplateRepo
+		githubSyncRepo
+		vcsAccountRepo
+		projectRepositoryRepo
+		repositoryEventRepo
+		taskLinkRepo
+		issueSyncRepo
+		activityRepo
 	}
 
 	userRepo interface {
@@ -36,10 +56,6 @@ type (
 		GetProjectCountByFilter(ctx context.Context, filter *repository.ProjectFilter) (int, error)
 		// GetProjectInfo(ctx context.Context, id int, isTasks bool) (*model.ProjectInfo, error)
 		GetProjectInfo(ctx context.Context, id int) (*model.ProjectInfo, error)
-		GetProjectChecklist(ctx context.Context, id int) ([]model.Checklist, error)
-		AddProjectChecklist(ctx context.Context, id int, checklist []model.Checklist) ([]model.Checklist, error)
-		UpdateProjectChecklist(ctx context.Context, id int, checklist *model.Checklist) ([]model.Checklist, error)
-		DeleteProjectChecklist(ctx context.Context, id int, itemID int) ([]model.Checklist, error)
 
 		InsertProject(ctx context.Context, project *model.Project) error
 		UpdateProject(ctx context.Context, project *model.Project) error
@@ -57,14 +73,185 @@ type (
 	taskRepo interface {
 		GetTask(ctx context.Context, filter *repository.TaskFilter) (*model.Task, error)
 		GetTasks(ctx context.Context, filter *repository.TaskFilter) ([]model.Task, error)
-		GetCompletedTasksCountByGHUsername(ctx context.Context, projectID int) ([]model.TaskCount, error)
+		GetCompletedTasksCountByUser(ctx context.Context, projectID int) ([]model.TaskCount, error)
 		GetTaskCountByFilter(ctx context.Context, filter *repository.TaskFilter) (int, error)
 		GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo, error)
 
 		InsertTask(ctx context.Context, task *model.Task) error
+		InsertTasks(ctx context.Context, tasks []model.Task) error
 		UpdateTask(ctx context.Context, task *model.Task) error
+		UpdateTasks(ctx context.Context, tasks []model.Task) error
 		DeleteTask(ctx context.Context, id int) error
+		DeleteTasks(ctx context.Context, ids []int) error
 
 		DeleteParticipantsFromTask(ctx context.Context, participantID int) error
 	}
+
+	worklogRepo interface {
+		GetWorklog(ctx context.Context, filter *repository.WorklogFilter) (*model.Worklog, error)
+		GetWorklogs(ctx context.Context, filter *repository.WorklogFilter) ([]model.Worklog, error)
+		GetTaskWorklogTotal(ctx context.Context, taskID int) (int, error)
+		GetWeeklyWorklogs(ctx context.Context, projectID int) ([]model.WeeklyWorklog, error)
+		GetWorklogTotalsByUser(ctx context.Context, projectID int) ([]model.WorklogCount, error)
+
+		InsertWorklog(ctx context.Context, worklog *model.Worklog) error
+		UpdateWorklog(ctx context.Context, worklog *model.Worklog) error
+		DeleteWorklog(ctx context.Context, id int) error
+	}
+
+	boardRepo interface {
+		GetBoardColumns(ctx context.Context, projectID int) ([]model.BoardColumn, error)
+		UpsertBoardColumn(ctx context.Context, projectID int, column *model.BoardColumn) error
+		MoveTask(ctx context.Context, move *model.TaskMove) error
+	}
+
+	notificationRepo interface {
+		GetNotifications(ctx context.Context, filter *repository.NotificationFilter) ([]model.Notification, error)
+		GetNotificationCountByFilter(ctx context.Context, filter *repository.NotificationFilter) (int, error)
+		InsertNotifications(ctx context.Context, notifications []model.Notification) error
+		MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []int) error
+
+		GetNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error)
+		UpsertNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences []model.NotificationPreference) error
+		GetMutedUserIDs(ctx context.Context, event model.NotificationEvent, userIDs []uuid.UUID) ([]uuid.UUID, error)
+
+		AddTaskWatcher(ctx context.Context, taskID int, userID uuid.UUID) error
+		DeleteTaskWatcher(ctx context.Context, taskID int, userID uuid.UUID) error
+		GetTaskWatchers(ctx context.Context, taskID int) ([]model.ShortUser, error)
+
+		GetUserChannels(ctx context.Context, userID uuid.UUID) ([]model.UserChannel, error)
+		UpsertUserChannels(ctx context.Context, userID uuid.UUID, channels []model.UserChannel) error
+	}
+
+	deadlineRepo interface {
+		ClaimDeadlineReminders(ctx context.Context, today time.Time, daysBefore, overdueDays int) ([]model.DeadlineReminder, error)
+	}
+
+	fileRepo interface {
+		GetFile(ctx context.Context, filter *repository.FileFilter) (*model.File, error)
+		GetFiles(ctx context.Context, filter *repository.FileFilter) ([]model.File, error)
+		InsertFile(ctx context.Context, file *model.File) error
+		MarkFilesDeleted(ctx context.Context, filter *repository.FileFilter, exceptID int) error
+		GetOrphanedFiles(ctx context.Context, limit uint64) ([]model.File, error)
+		DeleteFiles(ctx context.Context, ids []int) error
+	}
+
+	submissionRepo interface {
+		GetSubmission(ctx context.Context, filter *repository.SubmissionFilter) (*model.ReportSubmission, error)
+		GetSubmissions(ctx context.Context, filter *repository.SubmissionFilter) ([]model.ReportSubmission, error)
+		InsertSubmission(ctx context.Context, submission *model.ReportSubmission) error
+		UpdateSubmissionReview(ctx context.Context, submission *model.ReportSubmission) error
+		GetCourseSubmissions(ctx context.Context, ownerID uuid.UUID, course string) ([]model.ProjectSubmissions, error)
+	}
+
+	gradeRepo interface {
+		GetRubric(ctx context.Context, filter *repository.RubricFilter) (*model.Rubric, error)
+		GetRubrics(ctx context.Context, filter *repository.RubricFilter) ([]model.Rubric, error)
+		InsertRubric(ctx context.Context, rubric *model.Rubric) error
+		UpdateRubric(ctx context.Context, rubric *model.Rubric) error
+		DeleteRubric(ctx context.Context, id int) error
+		GetGrades(ctx context.Context, rubricID int, projectIDs []int) ([]model.Grade, error)
+		UpsertGrades(ctx context.Context, grades []model.Grade, onlyAuto bool) error
+	}
+
+	peerRepo interface {
+		GetPeerRound(ctx context.Context, filter *repository.PeerRoundFilter) (*model.PeerRound, error)
+		GetPeerRounds(ctx context.Context, filter *repository.PeerRoundFilter) ([]model.PeerRound, error)
+		InsertPeerRound(ctx context.Context, round *model.PeerRound) error
+		ClosePeerRound(ctx context.Context, round *model.PeerRound) error
+		GetPeerReviews(ctx context.Context, filter *repository.PeerReviewFilter) ([]model.PeerReview, error)
+		UpsertPeerReviews(ctx context.Context, reviews []model.PeerReview) error
+	}
+
+	statusReportRepo interface {
+		GetStatusReport(ctx context.Context, filter *repository.StatusReportFilter) (*model.StatusReport, error)
+		GetStatusReports(ctx context.Context, filter *repository.StatusReportFilter) ([]model.StatusReport, error)
+		UpsertStatusReport(ctx context.Context, report *model.StatusReport) error
+		UpdateStatusReportComment(ctx context.Context, report *model.StatusReport) error
+	}
+
+	meetingRepo interface {
+		GetMeeting(ctx context.Context, filter *repository.MeetingFilter) (*model.Meeting, error)
+		GetMeetings(ctx context.Context, filter *repository.MeetingFilter) ([]model.Meeting, error)
+		InsertMeeting(ctx context.Context, meeting *model.Meeting) error
+		UpdateMeeting(ctx context.Context, meeting *model.Meeting) error
+		SetActionItemTask(ctx context.Context, itemID, taskID int) error
+		DeleteMeeting(ctx context.Context, id int) error
+		GetAttendance(ctx context.Context, projectID int) ([]model.Attendance, error)
+	}
+
+	checklistRepo interface {
+		GetChecklist(ctx context.Context, filter *repository.ChecklistFilter) (*model.Checklist, error)
+		GetChecklists(ctx context.Context, filter *repository.ChecklistFilter) ([]model.Checklist, error)
+		GetChecklistItem(ctx context.Context, filter *repository.ChecklistItemFilter) (*model.ChecklistItem, error)
+		GetChecklistItems(ctx context.Context, filter *repository.ChecklistItemFilter) ([]model.ChecklistItem, error)
+		GetChecklistProgress(ctx context.Context, projectIDs []int) (map[int]model.ChecklistProgress, error)
+		InsertChecklist(ctx context.Context, checklist *model.Checklist) error
+		UpdateChecklist(ctx context.Context, checklist *model.Checklist) error
+		DeleteChecklist(ctx context.Context, id int) error
+		InsertChecklistItems(ctx context.Context, items []model.ChecklistItem) error
+		UpdateChecklistItem(ctx context.Context, item *model.ChecklistItem) error
+		DeleteChecklistItem(ctx context.Context, id int) error
+	}
+
+	checklistTemplateRepo interface {
+		GetChecklistTemplate(ctx context.Context, filter *repository.ChecklistTemplateFilter) (*model.ChecklistTemplate, error)
+		GetChecklistTemplates(ctx context.Context, filter *repository.ChecklistTemplateFilter) ([]model.ChecklistTemplate, error)
+		InsertChecklistTemplate(ctx context.Context, template *model.ChecklistTemplate) error
+		UpdateChecklistTemplate(ctx context.Context, template *model.ChecklistTemplate, propagate bool) error
+		DeleteChecklistTemplate(ctx context.Context, id int) error
+	}
+
+	githubSyncRepo interface {
+		ClaimGithubSyncs(ctx context.Context, now, claimUntil, syncedBefore time.Time, limit int) ([]model.GithubSync, error)
+		SaveGithubSync(ctx context.Context, sync *model.GithubSync) error
+		ReplaceContributorStats(ctx context.Context, weeks []model.ContributorWeek, sync *model.GithubSync) error
+		ResetGithubSync(ctx context.Context, repository *model.ProjectRepository, now time.Time) error
+		GetGithubSyncs(ctx context.Context, projectID int) ([]model.GithubSync, error)
+		GetContributorStats(ctx context.Context, projectID int) ([]model.ContributorStats, error)
+		SavePullRequests(ctx context.Context, sync *model.GithubSync, prs []model.PullRequest, reviews []model.Review) error
+		GetPullRequests(ctx context.Context, projectID int) ([]model.PullRequest, error)
+		GetPullRequestReviews(ctx context.Context, projectID int) ([]model.Review, error)
+	}
+
+	vcsAccountRepo interface {
+		GetVCSAccounts(ctx context.Context, userID uuid.UUID) ([]model.VCSAccount, error)
+		UpsertVCSAccounts(ctx context.Context, userID uuid.UUID, accounts []model.VCSAccount) error
+		GetVCSAccountUserID(ctx context.Context, host, username string) (uuid.UUID, error)
+		GetVCSUsernames(ctx context.Context, host string, userIDs []uuid.UUID) (map[uuid.UUID]string, error)
+	}
+
+	projectRepositoryRepo interface {
+		GetProjectRepository(ctx context.Context, filter *repository.ProjectRepositoryFilter) (*model.ProjectRepository, error)
+		GetProjectRepositories(ctx context.Context, filter *repository.ProjectRepositoryFilter) ([]model.ProjectRepository, error)
+		InsertProjectRepository(ctx context.Context, repository *model.ProjectRepository) error
+		UpdateProjectRepository(ctx context.Context, repository *model.ProjectRepository) error
+		DeleteProjectRepository(ctx context.Context, repository *model.ProjectRepository) error
+	}
+
+	repositoryEventRepo interface {
+		InsertRepositoryEvents(ctx context.Context, delivery *model.WebhookDelivery, events []model.RepositoryEvent) error
+		GetRepositoryEvents(ctx context.Context, filter *repository.RepositoryEventFilter) ([]model.RepositoryEvent, error)
+	}
+
+	taskLinkRepo interface {
+		UpsertTaskLinks(ctx context.Context, links []model.TaskLink) error
+		GetTaskLinks(ctx context.Context, taskID int) ([]model.TaskLink, error)
+		GetTaskChurn(ctx context.Context, projectID int) ([]model.TaskChurn, error)
+	}
+
+	issueSyncRepo interface {
+		GetIssueSync(ctx context.Context, projectID int) (*model.IssueSync, error)
+		GetEnabledIssueSyncs(ctx context.Context) ([]model.IssueSync, error)
+		SaveIssueSync(ctx context.Context, sync *model.IssueSync) error
+		GetTaskIssues(ctx context.Context, repositoryID int) ([]model.TaskIssue, error)
+		SaveIssueSyncRun(ctx context.Context, sync *model.IssueSync, links []model.TaskIssue, entries []model.IssueSyncEntry) error
+		GetIssueSyncLog(ctx context.Context, filter *repository.IssueSyncLogFilter) ([]model.IssueSyncEntry, error)
+	}
+
+	activityRepo interface {
+		GetCommitWeeks(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error)
+		GetCommitDays(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error)
+		GetTaskDoneDays(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error)
+	}
 )
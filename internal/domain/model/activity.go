@@ -0,0 +1,82 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActivityWeek ActivityGranularity = "WEEK"
+	ActivityDay  ActivityGranularity = "DAY"
+)
+
+const (
+	// FlagInactive means the student has done nothing for the inactivity days
+	FlagInactive ActivityFlagKind = "INACTIVE"
+	// FlagLastMinute means the most of the activity of the student is in the last days before the due date
+	FlagLastMinute ActivityFlagKind = "LAST_MINUTE"
+)
+
+const (
+	DefaultInactiveDays   = 7
+	DefaultLastMinuteDays = 7
+	// LastMinuteShare is the share of the activity in the last days which flags the student
+	LastMinuteShare = 0.5
+	// LastMinuteKeepDays is how long after the due date the last minute flags are shown
+	LastMinuteKeepDays = 14
+)
+
+type (
+	ActivityGranularity string
+	ActivityFlagKind    string
+
+	// ActivityCount is the activity of the user in the project in the day or the week starting at Start.
+	// The commits are counted by the login of the author in the repository, UserID is nil for them,
+	// the tasks by the id of the assignee, RepositoryID is zero and Login is empty for them.
+	ActivityCount struct {
+		ProjectID    int
+		RepositoryID int
+		Login        string
+		UserID       uuid.UUID
+		Start        time.Time
+		Commits      int
+		Additions    int
+		Deletions    int
+		TasksDone    int
+	}
+
+	ActivityPoint struct {
+		Start     time.Time
+		Commits   int
+		Additions int
+		Deletions int
+		TasksDone int
+	}
+
+	// ActivitySeries is the activity of the participant in every period of the project without gaps.
+	// The weeks start on Sunday like the synced statistics of the repositories. The lines are synced by weeks,
+	// so the days have only the commits received by the webhooks and the tasks.
+	ActivitySeries struct {
+		ShortUser
+		Points []ActivityPoint
+	}
+
+	// ActivityFlag marks the student of the project who needs the attention of the PM.
+	// LastActivityAt is not valid if the student has done nothing yet,
+	// LastDaysShare is the share of the commits and the tasks done in the last days before the due date.
+	ActivityFlag struct {
+		Project        ShortProject
+		User           ShortUser
+		Kind           ActivityFlagKind
+		LastActivityAt sql.NullTime
+		InactiveDays   int
+		LastDaysShare  float64
+	}
+)
+
+var ActivityGranularities = map[ActivityGranularity]struct{}{
+	ActivityWeek: {},
+	ActivityDay:  {},
+}
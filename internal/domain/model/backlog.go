@@ -0,0 +1,42 @@
+package model
+
+const (
+	FormatCSV      BacklogFormat = "csv"
+	FormatJSON     BacklogFormat = "json"
+	FormatMarkdown BacklogFormat = "md"
+)
+
+type (
+	BacklogFormat string
+
+	// BacklogTask is a task in the form it is exported and imported,
+	// with the assignee referred to by the github username
+	BacklogTask struct {
+		ID          int      `json:"id,omitempty"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Status      string   `json:"status"`
+		Assignee    string   `json:"assignee"`
+		Estimate    int      `json:"estimatedTime"`
+		Approved    bool     `json:"approved"`
+		Labels      []string `json:"labels"`
+		CreatedAt   string   `json:"createdAt,omitempty"`
+		UpdatedAt   string   `json:"updatedAt,omitempty"`
+	}
+
+	BacklogImport struct {
+		Tasks []Task
+		Rows  []BacklogImportRow
+	}
+	BacklogImportRow struct {
+		Line    int
+		Title   string
+		Warning string
+	}
+)
+
+var BacklogFormats = map[BacklogFormat]struct{}{
+	FormatCSV:      {},
+	FormatJSON:     {},
+	FormatMarkdown: {},
+}
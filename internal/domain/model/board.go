@@ -0,0 +1,71 @@
+package model
+
+import "database/sql"
+
+// rankAlphabet is ordered the same way as the "C" collation of tasks.rank
+const rankAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+type (
+	Board struct {
+		ProjectID int
+		Columns   []BoardColumn
+	}
+	BoardColumn struct {
+		Status   TaskStatus
+		WIPLimit sql.NullInt64
+		Tasks    []Task
+	}
+	// TaskMove puts the task to the column at Position among the other tasks of the column,
+	// the task goes to the end of the column if the position is out of range
+	TaskMove struct {
+		TaskID    int
+		ProjectID int
+		Status    TaskStatus
+		Position  int
+		Version   int
+	}
+)
+
+// BoardStatuses are the board columns in the order they are shown
+var BoardStatuses = []TaskStatus{TODO, InProgress, InReview, Done}
+
+// RankBetween returns a rank which is sorted strictly between prev and next.
+// Empty prev means the beginning of the column, empty next - the end of it.
+// Generated ranks never end with the lowest symbol, so there is always room
+// to put another rank before them.
+func RankBetween(prev, next string) string {
+	var (
+		base = len(rankAlphabet)
+		res  = make([]byte, 0, len(prev)+1)
+	)
+	for i := 0; ; i++ {
+		p := 0
+		if i < len(prev) {
+			p = rankIndex(prev[i])
+		}
+		n := base
+		if next != "" && i < len(next) {
+			n = rankIndex(next[i])
+		}
+
+		switch mid := (p + n) / 2; {
+		case p == n:
+			res = append(res, rankAlphabet[p])
+		case mid > p:
+			return string(append(res, rankAlphabet[mid]))
+		default:
+			// Symbols are adjacent, so everything after prev's symbol is free
+			res = append(res, rankAlphabet[p])
+			next = ""
+		}
+	}
+}
+
+func rankIndex(b byte) int {
+	for i := 0; i < len(rankAlphabet); i++ {
+		if rankAlphabet[i] == b {
+			return i
+		}
+	}
+	return 0
+}
@@ -1,8 +1,92 @@
 package model
 
-type Checklist struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	ProjectID int    `json:"projectId"`
-	Checked   bool   `json:"checked"`
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultChecklistName is the name of the checklist the items are added to when none is given
+const DefaultChecklistName = "Чек-лист"
+
+type (
+	// Checklist is a named list of the project items, the lists and their items are kept in order
+	Checklist struct {
+		ID        int
+		ProjectID int
+		Name      string
+		Position  int
+		CreatedAt time.Time
+		// TemplateID is set for the checklists made of a template
+		TemplateID sql.NullInt64
+		Items      []ChecklistItem
+	}
+
+	// ChecklistItem is a point of the checklist, a locked item can be checked by a PM only
+	ChecklistItem struct {
+		ID            int
+		ChecklistID   int
+		ProjectID     int
+		Name          string
+		Position      int
+		Checked       bool
+		ParticipantID sql.NullInt64
+		DueDate       sql.NullTime
+		Locked        bool
+		CheckedBy     uuid.NullUUID
+		CheckedAt     sql.NullTime
+		// TemplateItemID is set for the items made of a template item, they follow its changes
+		TemplateItemID sql.NullInt64
+	}
+
+	// ChecklistTemplate is a checklist of a PM which is copied to many projects at once
+	ChecklistTemplate struct {
+		ID        int
+		OwnerID   uuid.UUID
+		Name      string
+		Course    sql.NullString
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		Items     []ChecklistTemplateItem
+	}
+
+	ChecklistTemplateItem struct {
+		ID         int
+		TemplateID int
+		Name       string
+		Position   int
+		Locked     bool
+		DueDate    sql.NullTime
+	}
+
+	// TemplateApplication lists the projects which got the template and which already had it
+	TemplateApplication struct {
+		Applied []int
+		Skipped []int
+	}
+
+	ChecklistProgress struct {
+		Total   int
+		Checked int
+	}
+)
+
+// Percent is the share of the checked items rounded down, an empty checklist is 0% complete
+func (p ChecklistProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return p.Checked * 100 / p.Total
+}
+
+// Progress counts the checked items of the checklist
+func (c Checklist) Progress() ChecklistProgress {
+	progress := ChecklistProgress{Total: len(c.Items)}
+	for _, item := range c.Items {
+		if item.Checked {
+			progress.Checked++
+		}
+	}
+	return progress
 }
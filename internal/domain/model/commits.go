@@ -1,10 +1,36 @@
 package model
 
+import "time"
+
 type CommitsInfo struct {
 	ShortUser
 	TotalCommits       int
 	TotalTasksDone     int
 	TotalTasksEstimate int
+	TotalTasksActual   int
 	NumberOfAdditions  int
 	NumberOfDeletions  int
+	// Repositories break the commits down by the repositories of the project
+	Repositories []RepositoryCommits
+}
+
+type RepositoryCommits struct {
+	RepositoryID int
+	URL          string
+	Role         string
+	Commits      int
+	Additions    int
+	Deletions    int
+}
+
+// PullRequestMetrics are the pull requests of the user and the reviews they gave to the pull requests
+// of the others in all the repositories of the project. MedianTimeToMerge is zero if nothing was merged.
+type PullRequestMetrics struct {
+	ShortUser
+	Opened            int
+	Merged            int
+	MedianTimeToMerge time.Duration
+	ReviewsGiven      int
+	ReviewComments    int
+	Approvals         int
 }
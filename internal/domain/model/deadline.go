@@ -0,0 +1,24 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	DeadlineUpcoming DeadlineKind = "UPCOMING"
+	DeadlineOverdue  DeadlineKind = "OVERDUE"
+)
+
+type (
+	DeadlineKind string
+
+	// DeadlineReminder is a reminder about the project's ActiveTo
+	// or the task's DueDate if TaskID is set
+	DeadlineReminder struct {
+		ProjectID int
+		TaskID    sql.NullInt64
+		Kind      DeadlineKind
+		DueDate   time.Time
+	}
+)
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ChannelEmail    DeliveryChannel = "EMAIL"
+	ChannelTelegram DeliveryChannel = "TELEGRAM"
+	ChannelWebhook  DeliveryChannel = "WEBHOOK"
+)
+
+const (
+	DeliveryPending DeliveryStatus = "PENDING"
+	DeliverySent    DeliveryStatus = "SENT"
+	DeliveryFailed  DeliveryStatus = "FAILED"
+)
+
+type (
+	DeliveryChannel string
+	DeliveryStatus  string
+
+	// UserChannel is the user's setting of a delivery channel. Address is an email,
+	// a telegram chat id or a webhook url, empty email address means the user's email.
+	UserChannel struct {
+		Channel DeliveryChannel `json:"channel"`
+		Address string          `json:"address"`
+		Enabled bool            `json:"enabled"`
+		Digest  bool            `json:"digest"`
+	}
+
+	// Delivery is a notification which is queued to be sent through a channel
+	Delivery struct {
+		ID             int
+		NotificationID int
+		UserID         uuid.UUID
+		Channel        DeliveryChannel
+		Address        string
+		Digest         bool
+		Attempts       int
+		Event          NotificationEvent
+		Message        string
+		CreatedAt      time.Time
+	}
+)
+
+// DeliveryChannelOrder is the order the delivery channels are shown in
+var DeliveryChannelOrder = []DeliveryChannel{ChannelEmail, ChannelTelegram, ChannelWebhook}
+
+var DeliveryChannels = map[DeliveryChannel]struct{}{
+	ChannelEmail:    {},
+	ChannelTelegram: {},
+	ChannelWebhook:  {},
+}
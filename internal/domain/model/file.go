@@ -0,0 +1,71 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FileReport     FileKind = "REPORT"
+	FileAttachment FileKind = "ATTACHMENT"
+	FileAvatar     FileKind = "AVATAR"
+)
+
+type (
+	FileKind string
+
+	File struct {
+		ID          int           `json:"id"`
+		Key         string        `json:"-"`
+		Name        string        `json:"name"`
+		ContentType string        `json:"contentType"`
+		Size        int64         `json:"size"`
+		Kind        FileKind      `json:"kind"`
+		ProjectID   sql.NullInt64 `json:"projectId"`
+		TaskID      sql.NullInt64 `json:"taskId"`
+		UploadedBy  uuid.NullUUID `json:"uploadedBy"`
+		CreatedAt   time.Time     `json:"createdAt"`
+		DeletedAt   sql.NullTime  `json:"-"`
+	}
+
+	// FileRule limits the size and the detected content types of the uploaded files
+	FileRule struct {
+		MaxSize      int64
+		ContentTypes map[string]struct{}
+	}
+)
+
+var FileRules = map[FileKind]FileRule{
+	FileReport: {
+		MaxSize: 50 << 20,
+		ContentTypes: map[string]struct{}{
+			"application/pdf": {},
+			"application/zip": {}, // docx, pptx, odt
+			"text/plain":      {},
+		},
+	},
+	FileAttachment: {
+		MaxSize: 20 << 20,
+		ContentTypes: map[string]struct{}{
+			"application/pdf":  {},
+			"application/zip":  {},
+			"application/json": {},
+			"text/plain":       {},
+			"image/png":        {},
+			"image/jpeg":       {},
+			"image/gif":        {},
+			"image/webp":       {},
+		},
+	},
+	FileAvatar: {
+		MaxSize: 2 << 20,
+		ContentTypes: map[string]struct{}{
+			"image/png":  {},
+			"image/jpeg": {},
+			"image/gif":  {},
+			"image/webp": {},
+		},
+	},
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+	ReviewDismissed        = "DISMISSED"
+)
+
+const (
+	SyncPending     SyncStatus = "PENDING"
+	SyncOK          SyncStatus = "OK"
+	SyncComputing   SyncStatus = "COMPUTING"
+	SyncRateLimited SyncStatus = "RATE_LIMITED"
+	SyncFailed      SyncStatus = "FAILED"
+)
+
+type (
+	SyncStatus string
+
+	// GithubSync is the state of the repository statistics sync. Status is the result of the last
+	// attempt, the statistics of the last successful one are kept until the next success.
+	// RepositoryURL and ProjectName are filled in the lists only.
+	GithubSync struct {
+		RepositoryID  int
+		RepositoryURL string
+		ProjectID     int
+		ProjectName   string
+		Status        SyncStatus
+		Attempts      int
+		NextSyncAt    time.Time
+		AttemptedAt   sql.NullTime
+		SyncedAt      sql.NullTime
+		Error         sql.NullString
+	}
+
+	// ContributorWeek is the activity of the contributor in the week starting on WeekStart (Sunday).
+	// GithubUsername is the login at the provider of the repository or the email of an author unknown to it.
+	ContributorWeek struct {
+		GithubUsername string
+		WeekStart      time.Time
+		Commits        int
+		Additions      int
+		Deletions      int
+	}
+
+	ContributorStats struct {
+		RepositoryID   int
+		GithubUsername string
+		Commits        int
+		Additions      int
+		Deletions      int
+	}
+)
+
+type (
+	// VCSAccount is the username of the user on the version control system at Host
+	VCSAccount struct {
+		Host     string `json:"host"`
+		Username string `json:"username"`
+	}
+
+	VCSUser struct {
+		Login     string
+		Name      string
+		AvatarURL string
+	}
+
+	// Commit is a commit of the repository. AuthorLogin is empty if the author is not a user of the provider,
+	// Additions and Deletions are filled by the providers which list commits with statistics.
+	Commit struct {
+		SHA         string
+		AuthorLogin string
+		AuthorName  string
+		AuthorEmail string
+		Message     string
+		URL         string
+		CommittedAt time.Time
+		Additions   int
+		Deletions   int
+	}
+
+	// PullRequest is the pull request of the repository, RepositoryID is set for the saved ones
+	PullRequest struct {
+		RepositoryID int
+		Number       int
+		Title        string
+		Body         string
+		State        string
+		AuthorLogin  string
+		HeadBranch   string
+		URL          string
+		CreatedAt    time.Time
+		UpdatedAt    time.Time
+		MergedAt     sql.NullTime
+		ClosedAt     sql.NullTime
+	}
+
+	// Review is the review of the pull request Number in the states of github, Comments are the comments
+	// on the code left with it. The providers without reviews report the approvals and the comments of every
+	// reviewer as reviews.
+	Review struct {
+		RepositoryID int
+		Number       int
+		AuthorLogin  string
+		State        string
+		Comments     int
+		SubmittedAt  time.Time
+	}
+
+	Issue struct {
+		Number      int
+		Title       string
+		Body        string
+		State       string
+		AuthorLogin string
+		Assignees   []string
+		Labels      []string
+		URL         string
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		ClosedAt    sql.NullTime
+	}
+)
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeProject     CriterionScope = "PROJECT"
+	ScopeParticipant CriterionScope = "PARTICIPANT"
+)
+
+const (
+	MetricNone        CriterionMetric = ""
+	MetricTasksDone   CriterionMetric = "TASKS_DONE"
+	MetricCommits     CriterionMetric = "COMMITS"
+	MetricActualHours CriterionMetric = "ACTUAL_HOURS"
+	MetricChecklist   CriterionMetric = "CHECKLIST"
+	// MetricPeerScore is the mean peer rating in percents from the latest finished round
+	MetricPeerScore CriterionMetric = "PEER_SCORE"
+)
+
+const (
+	GradeBookXLSX GradeBookFormat = "xlsx"
+	GradeBookCSV  GradeBookFormat = "csv"
+)
+
+type (
+	CriterionScope  string
+	CriterionMetric string
+	GradeBookFormat string
+
+	Rubric struct {
+		ID        int
+		OwnerID   uuid.UUID
+		Name      string
+		Course    sql.NullString
+		Criteria  []Criterion
+		CreatedAt time.Time
+	}
+
+	// Criterion is scored either once for the project or for every participant.
+	// If Metric is set the points can be filled automatically: MetricTarget
+	// of the metric gives MaxPoints, less gives the proportional part.
+	Criterion struct {
+		ID           int
+		RubricID     int
+		Name         string
+		Weight       float64
+		MaxPoints    float64
+		Scope        CriterionScope
+		Metric       CriterionMetric
+		MetricTarget float64
+		Position     int
+	}
+
+	// Grade is the points of the project, or of the participant if ParticipantID is set
+	Grade struct {
+		ID            int
+		CriterionID   int
+		ProjectID     int
+		ParticipantID sql.NullInt64
+		Points        float64
+		Comment       sql.NullString
+		Auto          bool
+		GradedBy      uuid.NullUUID
+		UpdatedAt     time.Time
+	}
+
+	// GradeBookRow is the participant's points by criterion ID and the total
+	// percentage weighted over all the criteria of the rubric
+	GradeBookRow struct {
+		Project     Project
+		Participant Participant
+		Points      map[int]float64
+		Total       float64
+	}
+
+	GradeBook struct {
+		Rubric Rubric
+		Rows   []GradeBookRow
+	}
+)
+
+var CriterionScopes = map[CriterionScope]struct{}{
+	ScopeProject:     {},
+	ScopeParticipant: {},
+}
+
+// CriterionMetrics lists the metrics available for each scope
+var CriterionMetrics = map[CriterionScope]map[CriterionMetric]struct{}{
+	ScopeProject: {
+		MetricNone:        {},
+		MetricTasksDone:   {},
+		MetricCommits:     {},
+		MetricActualHours: {},
+		MetricChecklist:   {},
+	},
+	ScopeParticipant: {
+		MetricNone:        {},
+		MetricTasksDone:   {},
+		MetricCommits:     {},
+		MetricActualHours: {},
+		MetricPeerScore:   {},
+	},
+}
+
+var GradeBookFormats = map[GradeBookFormat]struct{}{
+	GradeBookXLSX: {},
+	GradeBookCSV:  {},
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	IssueSyncToIssue IssueSyncDirection = "TO_ISSUE"
+	IssueSyncToTask  IssueSyncDirection = "TO_TASK"
+)
+
+const (
+	IssueSyncCreate IssueSyncAction = "CREATE"
+	IssueSyncUpdate IssueSyncAction = "UPDATE"
+	IssueSyncFailed IssueSyncAction = "FAILED"
+)
+
+type (
+	IssueSyncDirection string
+	IssueSyncAction    string
+
+	// IssueSync is the opt-in sync of the project tasks with the issues of one of its repositories.
+	// SyncedAt is the start of the last run, RepositoryURL is filled in the lists only.
+	IssueSync struct {
+		ProjectID     int
+		RepositoryID  int
+		RepositoryURL string
+		Enabled       bool
+		SyncedAt      sql.NullTime
+	}
+
+	// TaskIssue links the task to its issue. TaskUpdatedAt and IssueUpdatedAt are the versions
+	// of both synced last, the side changed after them wins. TaskID is null if the task was deleted.
+	TaskIssue struct {
+		RepositoryID   int
+		Number         int
+		TaskID         sql.NullInt64
+		URL            string
+		TaskUpdatedAt  time.Time
+		IssueUpdatedAt time.Time
+		SyncedAt       time.Time
+	}
+
+	// IssueSyncEntry is one change made by the sync run. Conflict is set if both the task and the issue
+	// were changed since the last run and the one modified last won. Message lists the changed fields
+	// or the error of the failed change.
+	IssueSyncEntry struct {
+		ID        int
+		ProjectID int
+		TaskID    sql.NullInt64
+		Number    int
+		Direction IssueSyncDirection
+		Action    IssueSyncAction
+		Conflict  bool
+		Message   string
+		CreatedAt time.Time
+	}
+)
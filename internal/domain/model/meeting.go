@@ -0,0 +1,62 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MeetingWeekly  MeetingKind = "MEETING"
+	MeetingStandup MeetingKind = "STANDUP"
+)
+
+type (
+	MeetingKind string
+
+	// Meeting is the record of a team meeting, Attendees has every participant
+	// of the project at the moment of the meeting
+	Meeting struct {
+		ID          int
+		ProjectID   int
+		Kind        MeetingKind
+		Title       string
+		HeldAt      time.Time
+		Notes       string
+		Decisions   string
+		CreatedBy   uuid.NullUUID
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		Attendees   []MeetingAttendee
+		ActionItems []ActionItem
+	}
+
+	MeetingAttendee struct {
+		ParticipantID int
+		Present       bool
+	}
+
+	// ActionItem is a follow-up of the meeting, it becomes a task of the project
+	ActionItem struct {
+		ID            int
+		MeetingID     int
+		Text          string
+		ParticipantID sql.NullInt64
+		DueDate       sql.NullTime
+		TaskID        sql.NullInt64
+		Position      int
+	}
+
+	// Attendance is the number of meetings the participant was expected at and attended
+	Attendance struct {
+		Participant
+		Meetings int
+		Attended int
+	}
+)
+
+var MeetingKinds = map[MeetingKind]struct{}{
+	MeetingWeekly:  {},
+	MeetingStandup: {},
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventTaskAssigned          NotificationEvent = "TASK_ASSIGNED"
+	EventTaskStatusChanged     NotificationEvent = "TASK_STATUS_CHANGED"
+	EventTaskInReview          NotificationEvent = "TASK_IN_REVIEW"
+	EventChecklistChecked      NotificationEvent = "CHECKLIST_CHECKED"
+	EventParticipantAdded      NotificationEvent = "PARTICIPANT_ADDED"
+	EventDeadlineUpcoming      NotificationEvent = "DEADLINE_UPCOMING"
+	EventDeadlineOverdue       NotificationEvent = "DEADLINE_OVERDUE"
+	EventReportSubmitted       NotificationEvent = "REPORT_SUBMITTED"
+	EventReportReviewed        NotificationEvent = "REPORT_REVIEWED"
+	EventPeerRoundOpened       NotificationEvent = "PEER_ROUND_OPENED"
+	EventStatusReportSubmitted NotificationEvent = "STATUS_REPORT_SUBMITTED"
+	EventStatusReportCommented NotificationEvent = "STATUS_REPORT_COMMENTED"
+)
+
+type (
+	NotificationEvent string
+
+	Notification struct {
+		ID        int               `json:"id"`
+		UserID    uuid.UUID         `json:"userId"`
+		ProjectID sql.NullInt64     `json:"projectId"`
+		TaskID    sql.NullInt64     `json:"taskId"`
+		Event     NotificationEvent `json:"event"`
+		Message   string            `json:"message"`
+		Read      bool              `json:"read"`
+		CreatedAt time.Time         `json:"createdAt"`
+	}
+
+	NotificationPreference struct {
+		Event   NotificationEvent `json:"event"`
+		Enabled bool              `json:"enabled"`
+	}
+)
+
+// NotificationEventOrder is the order the notification preferences are shown in
+var NotificationEventOrder = []NotificationEvent{
+	EventTaskAssigned,
+	EventTaskStatusChanged,
+	EventTaskInReview,
+	EventChecklistChecked,
+	EventParticipantAdded,
+	EventDeadlineUpcoming,
+	EventDeadlineOverdue,
+	EventReportSubmitted,
+	EventReportReviewed,
+	EventPeerRoundOpened,
+	EventStatusReportSubmitted,
+	EventStatusReportCommented,
+}
+
+var NotificationEvents = map[NotificationEvent]struct{}{
+	EventTaskAssigned:          {},
+	EventTaskStatusChanged:     {},
+	EventTaskInReview:          {},
+	EventChecklistChecked:      {},
+	EventParticipantAdded:      {},
+	EventDeadlineUpcoming:      {},
+	EventDeadlineOverdue:       {},
+	EventReportSubmitted:       {},
+	EventReportReviewed:        {},
+	EventPeerRoundOpened:       {},
+	EventStatusReportSubmitted: {},
+	EventStatusReportCommented: {},
+}
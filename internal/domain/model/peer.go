@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPeerMaxScore = 5
+	// PeerOutlierShare is the share of the score scale a review has to deviate from
+	// the median of the reviews of the same participant to be an outlier
+	PeerOutlierShare = 0.5
+	// PeerOutlierMinReviews is the number of reviews from which outliers are looked for
+	PeerOutlierMinReviews = 3
+)
+
+type (
+	// PeerRound is the period in which the team members rate each other,
+	// every criterion is scored from 1 to MaxScore
+	PeerRound struct {
+		ID        int
+		ProjectID int
+		Name      string
+		Criteria  []string
+		MaxScore  int
+		OpenedBy  uuid.NullUUID
+		OpenedAt  time.Time
+		ClosesAt  sql.NullTime
+		ClosedAt  sql.NullTime
+	}
+
+	// PeerReview is the rating of the reviewee by the reviewer, the scores go
+	// in the order of the round criteria
+	PeerReview struct {
+		ID          int
+		RoundID     int
+		ReviewerID  int
+		RevieweeID  int
+		Scores      []int64
+		Comment     sql.NullString
+		SubmittedAt time.Time
+	}
+
+	// PeerAssignment is what the participant has to fill in the round
+	PeerAssignment struct {
+		Round     PeerRound
+		Teammates []Participant
+		Reviews   []PeerReview
+	}
+
+	PeerCompletion struct {
+		Participant Participant
+		Done        int
+		Expected    int
+	}
+
+	// PeerResult is the aggregated rating of the participant. Means and Mean don't
+	// count the outlier reviews, RawMean counts all of them. Comments are anonymous.
+	PeerResult struct {
+		Participant Participant
+		Reviews     int
+		Outliers    int
+		Means       []float64
+		Mean        float64
+		RawMean     float64
+		Comments    []string
+	}
+
+	PeerRoundResults struct {
+		Round      PeerRound
+		Completion []PeerCompletion
+		Results    []PeerResult
+	}
+)
+
+func (r PeerRound) IsOpen(now time.Time) bool {
+	return !r.ClosedAt.Valid && (!r.ClosesAt.Valid || now.Before(r.ClosesAt.Time))
+}
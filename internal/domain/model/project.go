@@ -11,6 +11,10 @@ type (
 		ReportURL  sql.NullString `json:"reportUrl"`
 		ReportName sql.NullString `json:"reportName"`
 		RepoURL    sql.NullString `json:"repo"`
+		Course     sql.NullString `json:"course"`
+		// AutoTransitionTasks moves the tasks linked to a pull request to REVIEW
+		// when it opens and to DONE when it merges
+		AutoTransitionTasks bool `json:"autoTransitionTasks"`
 	}
 	ShortProject struct {
 		ID          int            `json:"id"`
@@ -23,6 +27,20 @@ type (
 		Project
 		Participants []Participant
 		Tasks        []Task
-		Checklist    []Checklist
+		Checklists   []Checklist
+		Repositories []ProjectRepository
+	}
+
+	// ProjectRepository is a repository of the project. Provider is the host of the url,
+	// Role is a free label like frontend or backend. The url of the first repository
+	// is kept as the RepoURL of the project.
+	ProjectRepository struct {
+		ID            int
+		ProjectID     int
+		URL           string
+		Provider      string
+		DefaultBranch string
+		Role          string
+		CreatedAt     time.Time
 	}
 )
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventCommit      RepositoryEventKind = "COMMIT"
+	EventPullRequest RepositoryEventKind = "PULL_REQUEST"
+	EventReview      RepositoryEventKind = "REVIEW"
+	EventIssue       RepositoryEventKind = "ISSUE"
+)
+
+type (
+	RepositoryEventKind string
+
+	// WebhookDelivery is a request of the webhook of the provider, ID is unique for every delivery
+	// and is kept to skip the redelivered ones
+	WebhookDelivery struct {
+		ID         string
+		Event      string
+		ReceivedAt time.Time
+	}
+
+	// RepositoryEvent is the activity in the repository of the project received from the webhook.
+	// GithubUsername is the login of the author of the commit or of the sender of the event,
+	// UserID is set when it is the GithubUsername of a user of the project.
+	// Number is the number of the pull request or of the issue, SHA is set for the commits only,
+	// Ref is the pushed branch or the head branch of the pull request. State is the state
+	// of the pull request, of the review or of the issue after the event. Additions and Deletions
+	// are the lines changed by the pull request, the push payload has no lines of the commits.
+	RepositoryEvent struct {
+		ID             int
+		DeliveryID     string
+		RepositoryID   int
+		ProjectID      int
+		Kind           RepositoryEventKind
+		Action         string
+		GithubUsername string
+		UserID         uuid.NullUUID
+		Number         sql.NullInt64
+		SHA            sql.NullString
+		Ref            string
+		Title          string
+		URL            string
+		State          string
+		Additions      int
+		Deletions      int
+		OccurredAt     time.Time
+	}
+
+	// TaskLink is the commit or the pull request referencing the task in the message, the title
+	// or the branch like PM-123 or #task-123. SHA is set for the commits and Number for the pull requests,
+	// the link of a pull request follows its state and lines.
+	TaskLink struct {
+		ID             int
+		TaskID         int
+		RepositoryID   int
+		Kind           RepositoryEventKind
+		SHA            string
+		Number         int
+		Title          string
+		URL            string
+		GithubUsername string
+		UserID         uuid.NullUUID
+		State          string
+		Additions      int
+		Deletions      int
+		LinkedAt       time.Time
+		UpdatedAt      time.Time
+	}
+
+	// TaskChurn is the code linked to the task, the lines are counted by the pull requests
+	TaskChurn struct {
+		TaskID       int
+		Name         string
+		Status       TaskStatus
+		Commits      int
+		PullRequests int
+		Additions    int
+		Deletions    int
+	}
+)
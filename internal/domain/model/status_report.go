@@ -0,0 +1,69 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusReportsXLSX StatusReportsFormat = "xlsx"
+	StatusReportsCSV  StatusReportsFormat = "csv"
+)
+
+type (
+	StatusReportsFormat string
+
+	// StatusReport is the weekly report of the team, WeekStart is the monday of the week.
+	// TasksDone and Commits are the numbers of the week at the moment of the submission.
+	StatusReport struct {
+		ID          int
+		ProjectID   int
+		WeekStart   time.Time
+		Done        string
+		Planned     string
+		Blockers    string
+		TasksDone   int
+		Commits     int
+		SubmittedBy uuid.NullUUID
+		SubmittedAt time.Time
+		UpdatedAt   time.Time
+		PMComment   sql.NullString
+		CommentedBy uuid.NullUUID
+		CommentedAt sql.NullTime
+	}
+
+	// StatusReportDraft is what the report of the week is pre-filled with,
+	// Report is set if it has already been submitted
+	StatusReportDraft struct {
+		WeekStart time.Time
+		DoneTasks []Task
+		Commits   []UserCommits
+		Report    *StatusReport
+	}
+
+	UserCommits struct {
+		ShortUser
+		Commits int
+	}
+
+	// ProjectStatusReport is the state of the project in the missing reports overview,
+	// Report is nil if the team has not submitted the report of the week
+	ProjectStatusReport struct {
+		Project Project
+		Report  *StatusReport
+	}
+)
+
+var StatusReportsFormats = map[StatusReportsFormat]struct{}{
+	StatusReportsXLSX: {},
+	StatusReportsCSV:  {},
+}
+
+// WeekStart returns the monday of the week of t
+func WeekStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SubmissionSubmitted        SubmissionStatus = "SUBMITTED"
+	SubmissionAccepted         SubmissionStatus = "ACCEPTED"
+	SubmissionChangesRequested SubmissionStatus = "CHANGES_REQUESTED"
+)
+
+type (
+	SubmissionStatus string
+
+	// ReportSubmission is a version of the project report, either an uploaded file or a link
+	ReportSubmission struct {
+		ID            int
+		ProjectID     int
+		Version       int
+		FileID        sql.NullInt64
+		URL           sql.NullString
+		Name          string
+		Comment       sql.NullString
+		SubmittedBy   uuid.NullUUID
+		SubmittedAt   time.Time
+		Late          bool
+		Status        SubmissionStatus
+		ReviewerID    uuid.NullUUID
+		ReviewComment sql.NullString
+		ReviewedAt    sql.NullTime
+	}
+
+	// ProjectSubmissions is the state of the project's report in a course overview,
+	// Last is nil if the team has not submitted anything
+	ProjectSubmissions struct {
+		Project  Project
+		Versions int
+		Last     *ReportSubmission
+	}
+)
+
+var ReviewStatuses = map[SubmissionStatus]struct{}{
+	SubmissionAccepted:         {},
+	SubmissionChangesRequested: {},
+}
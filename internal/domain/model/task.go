@@ -3,6 +3,8 @@ package model
 import (
 	"database/sql"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -12,9 +14,18 @@ const (
 	Done       TaskStatus = "DONE"
 )
 
+const (
+	BulkReassign BulkTaskAction = "REASSIGN"
+	BulkStatus   BulkTaskAction = "STATUS"
+	BulkRelabel  BulkTaskAction = "RELABEL"
+	BulkApprove  BulkTaskAction = "APPROVE"
+	BulkDelete   BulkTaskAction = "DELETE"
+)
+
 type (
-	TaskStatus string
-	Task       struct {
+	TaskStatus     string
+	BulkTaskAction string
+	Task           struct {
 		ShortTask
 		ProjectID int `json:"projectId"`
 	}
@@ -27,6 +38,11 @@ type (
 		Status        TaskStatus     `json:"status"`
 		Estimate      sql.NullInt64  `json:"estimatedTime"`
 		Approved      sql.NullBool   `json:"approved"`
+		Labels        []string       `json:"labels"`
+		DueDate       sql.NullTime   `json:"dueDate"`
+		DoneAt        sql.NullTime   `json:"doneAt"`
+		Rank          string         `json:"rank"`
+		Version       int            `json:"version"`
 		CreatedAt     time.Time      `json:"createdAt"`
 		UpdatedAt     time.Time      `json:"updatedAt"`
 	}
@@ -34,14 +50,27 @@ type (
 		Task
 		Creator     ShortUser
 		Participant ShortUser
+		Links       []TaskLink
+	}
+	BulkTaskResult struct {
+		TaskID int
+		Err    error
 	}
 	TaskCount struct {
-		GithubUsername string
-		TotalDone      int
-		TotalEstimate  int
+		UserID        uuid.UUID
+		TotalDone     int
+		TotalEstimate int
 	}
 )
 
+var BulkTaskActions = map[BulkTaskAction]struct{}{
+	BulkReassign: {},
+	BulkStatus:   {},
+	BulkRelabel:  {},
+	BulkApprove:  {},
+	BulkDelete:   {},
+}
+
 var TaskStatuses = map[string]struct{}{
 	"BACKLOG":     {},
 	"IN_PROGRESS": {},
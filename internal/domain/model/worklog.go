@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Worklog is a piece of time (in minutes) spent by a participant on a task.
+	// A running worklog is a started timer which has no duration yet.
+	Worklog struct {
+		ID            int            `json:"id"`
+		TaskID        int            `json:"taskId"`
+		ParticipantID int            `json:"participantId"`
+		Duration      int            `json:"duration"`
+		Date          time.Time      `json:"date"`
+		Note          sql.NullString `json:"note"`
+		StartedAt     sql.NullTime   `json:"startedAt"`
+		Running       bool           `json:"running"`
+		CreatedAt     time.Time      `json:"createdAt"`
+	}
+	TaskWorklogs struct {
+		TaskID   int
+		Estimate sql.NullInt64
+		Actual   int
+		Worklogs []Worklog
+	}
+	WeeklyWorklog struct {
+		ParticipantID int
+		ShortUser
+		WeekStart    time.Time
+		TotalMinutes int
+	}
+	WorklogCount struct {
+		UserID       uuid.UUID
+		TotalMinutes int
+	}
+)
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// activityPoints keeps the activity of the participants of a project by the ids of the users and the starts of the periods
+type activityPoints map[uuid.UUID]map[time.Time]model.ActivityPoint
+
+// GetActivityTimeline returns the activity of every participant of the project by weeks or by days
+// from the first active period to the due date, or to now if the project is still active.
+// The weeks are synced from the repositories in the background, see SyncGithubStats.
+func (s *service) GetActivityTimeline(ctx context.Context, projectID int, granularity model.ActivityGranularity,
+	now time.Time) ([]model.ActivitySeries, error) {
+	if _, ok := model.ActivityGranularities[granularity]; !ok {
+		return nil, ierr.ErrInvalidActivityGranularity
+	}
+	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	users, logins, err := s.activityLogins(ctx, []int{projectID})
+	if err != nil {
+		return nil, err
+	}
+	activity, err := s.activityPoints(ctx, []int{projectID}, logins, granularity)
+	if err != nil {
+		return nil, err
+	}
+	points := activity[projectID]
+
+	end := now
+	if !project.ActiveTo.IsZero() && project.ActiveTo.Before(now) {
+		end = project.ActiveTo
+	}
+	last := periodStart(end, granularity)
+	var first time.Time
+	for _, userPoints := range points {
+		for start := range userPoints {
+			if first.IsZero() || start.Before(first) {
+				first = start
+			}
+			if start.After(last) {
+				last = start
+			}
+		}
+	}
+
+	res := make([]model.ActivitySeries, 0, len(users[projectID]))
+	for _, user := range users[projectID] {
+		series := model.ActivitySeries{ShortUser: user, Points: make([]model.ActivityPoint, 0)}
+		for start := first; !first.IsZero() && !start.After(last); start = nextPeriod(start, granularity) {
+			point := points[user.ID][start]
+			point.Start = start
+			series.Points = append(series.Points, point)
+		}
+		res = append(res, series)
+	}
+	return res, nil
+}
+
+// GetActivityFlags finds the students of the projects of the owner, of all the projects if ownerID is nil,
+// who need the attention of the PM. The students of the projects before the due date are flagged if they
+// have done nothing for inactiveDays. Once the last lastDays before the due date begin, and for a while after it,
+// the students are flagged if the most of their commits and done tasks fall in these days. The commits are synced
+// by weeks, so the last days are rounded out to the whole weeks.
+func (s *service) GetActivityFlags(ctx context.Context, ownerID uuid.UUID, now time.Time,
+	inactiveDays, lastDays int) ([]model.ActivityFlag, error) {
+	if inactiveDays <= 0 {
+		inactiveDays = model.DefaultInactiveDays
+	}
+	if lastDays <= 0 {
+		lastDays = model.DefaultLastMinuteDays
+	}
+	today := periodStart(now, model.ActivityDay)
+
+	projects, err := s.repo.GetProjects(ctx, repository.NewProjectFilter().
+		ByOwnerID(ownerID).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+	checked := make([]model.Project, 0, len(projects))
+	projectIDs := make([]int, 0, len(projects))
+	for _, project := range projects {
+		if project.ActiveTo.IsZero() || !project.ActiveTo.Before(today.AddDate(0, 0, -model.LastMinuteKeepDays)) {
+			checked = append(checked, project)
+			projectIDs = append(projectIDs, project.ID)
+		}
+	}
+	if len(checked) == 0 {
+		return make([]model.ActivityFlag, 0), nil
+	}
+
+	users, logins, err := s.activityLogins(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	weeks, err := s.activityPoints(ctx, projectIDs, logins, model.ActivityWeek)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.activityPoints(ctx, projectIDs, logins, model.ActivityDay)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.ActivityFlag, 0)
+	for _, project := range checked {
+		dueDate := periodStart(project.ActiveTo, model.ActivityDay)
+		for _, user := range users[project.ID] {
+			if user.Role != model.Student {
+				continue
+			}
+			flag := model.ActivityFlag{Project: project.ShortProject, User: user}
+			lastActivity := lastActivityDay(weeks[project.ID][user.ID], days[project.ID][user.ID], today)
+
+			if project.ActiveTo.IsZero() || !dueDate.Before(today) {
+				inactive := lastActivity.IsZero()
+				if !inactive {
+					flag.LastActivityAt.Scan(lastActivity)
+					flag.InactiveDays = int(today.Sub(lastActivity).Hours() / 24)
+					inactive = flag.InactiveDays >= inactiveDays
+				}
+				if inactive {
+					flag.Kind = model.FlagInactive
+					res = append(res, flag)
+					continue
+				}
+			}
+
+			lastDaysStart := dueDate.AddDate(0, 0, 1-lastDays)
+			if project.ActiveTo.IsZero() || today.Before(lastDaysStart) {
+				continue
+			}
+			var total, last int
+			for start, point := range weeks[project.ID][user.ID] {
+				activity := point.Commits + point.TasksDone
+				total += activity
+				if !start.Before(periodStart(lastDaysStart, model.ActivityWeek)) && !start.After(dueDate) {
+					last += activity
+				}
+			}
+			if total == 0 {
+				continue
+			}
+			flag.LastDaysShare = float64(last) / float64(total)
+			if flag.LastDaysShare >= model.LastMinuteShare {
+				if !lastActivity.IsZero() {
+					flag.LastActivityAt.Scan(lastActivity)
+				}
+				flag.Kind = model.FlagLastMinute
+				res = append(res, flag)
+			}
+		}
+	}
+	return res, nil
+}
+
+// activityLogins returns the participants of every project and the logins of all the repositories of the projects
+func (s *service) activityLogins(ctx context.Context, projectIDs []int) (map[int][]model.ShortUser,
+	map[int]map[string]uuid.UUID, error) {
+	users := make(map[int][]model.ShortUser, len(projectIDs))
+	logins := make(map[int]map[string]uuid.UUID)
+	for _, projectID := range projectIDs {
+		projectUsers, err := s.repo.GetPartialUsers(ctx, repository.NewUserFilter().ByAtProject(projectID))
+		if err != nil {
+			return nil, nil, err
+		}
+		repositories, err := s.GetProjectRepositories(ctx, projectID)
+		if err != nil {
+			return nil, nil, err
+		}
+		repositoryLogins, err := s.repositoryUserIDs(ctx, repositories, projectUsers)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		users[projectID] = projectUsers
+		for repositoryID, repoLogins := range repositoryLogins {
+			logins[repositoryID] = repoLogins
+		}
+	}
+	return users, logins, nil
+}
+
+// activityPoints sums the commits of the participants and the tasks done by them in every period of the projects
+func (s *service) activityPoints(ctx context.Context, projectIDs []int, logins map[int]map[string]uuid.UUID,
+	granularity model.ActivityGranularity) (map[int]activityPoints, error) {
+	var (
+		commits []model.ActivityCount
+		err     error
+	)
+	if granularity == model.ActivityWeek {
+		commits, err = s.repo.GetCommitWeeks(ctx, projectIDs)
+	} else {
+		commits, err = s.repo.GetCommitDays(ctx, projectIDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.GetTaskDoneDays(ctx, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int]activityPoints, len(projectIDs))
+	for _, projectID := range projectIDs {
+		res[projectID] = make(activityPoints)
+	}
+	for _, count := range commits {
+		// the commits of an author may be counted by the login and by the email
+		userID, ok := logins[count.RepositoryID][strings.ToLower(count.Login)]
+		if !ok {
+			continue
+		}
+		res[count.ProjectID].add(userID, count, granularity)
+	}
+	for _, count := range tasks {
+		res[count.ProjectID].add(count.UserID, count, granularity)
+	}
+	return res, nil
+}
+
+func (p activityPoints) add(userID uuid.UUID, count model.ActivityCount, granularity model.ActivityGranularity) {
+	userPoints, ok := p[userID]
+	if !ok {
+		userPoints = make(map[time.Time]model.ActivityPoint)
+		p[userID] = userPoints
+	}
+	start := periodStart(count.Start, granularity)
+	point := userPoints[start]
+	point.Start = start
+	point.Commits += count.Commits
+	point.Additions += count.Additions
+	point.Deletions += count.Deletions
+	point.TasksDone += count.TasksDone
+	userPoints[start] = point
+}
+
+// lastActivityDay returns the last day with any activity, zero if there is none.
+// The day of the synced commits is not known, the end of their week is taken.
+func lastActivityDay(weeks, days map[time.Time]model.ActivityPoint, today time.Time) time.Time {
+	var last time.Time
+	for start, point := range weeks {
+		end := start.AddDate(0, 0, 6)
+		if end.After(today) {
+			end = today
+		}
+		if point.Commits > 0 && end.After(last) {
+			last = end
+		}
+	}
+	for start := range days {
+		if start.After(last) {
+			last = start
+		}
+	}
+	return last
+}
+
+// periodStart returns the start of the day or of the week in UTC,
+// the weeks start on Sunday like the synced statistics of the repositories
+func periodStart(t time.Time, granularity model.ActivityGranularity) time.Time {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if granularity == model.ActivityDay {
+		return day
+	}
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+func nextPeriod(start time.Time, granularity model.ActivityGranularity) time.Time {
+	if granularity == model.ActivityDay {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 0, 7)
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var backlogCSVHeader = []string{"id", "title", "description", "status", "assignee",
+	"estimatedTime", "approved", "labels", "createdAt", "updatedAt"}
+
+type backlogRow struct {
+	line int
+	task model.BacklogTask
+}
+
+func (s *service) ExportTasks(ctx context.Context, projectID int, format model.BacklogFormat) ([]byte, error) {
+	if _, ok := model.BacklogFormats[format]; !ok {
+		return nil, ierr.ErrInvalidBacklogFormat
+	}
+
+	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+	assignees := make(map[int64]string, len(participants))
+	for _, participant := range participants {
+		assignees[int64(participant.ID)] = participant.GithubUsername
+	}
+
+	tasks, err := s.projectTasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks are already sorted by rank, so only grouping by status is left
+	backlog := make([]model.BacklogTask, 0, len(tasks))
+	for _, status := range model.BoardStatuses {
+		for _, task := range tasks {
+			if task.Status != status {
+				continue
+			}
+			backlog = append(backlog, model.BacklogTask{
+				ID:          task.ID,
+				Title:       task.Name,
+				Description: task.Description.String,
+				Status:      string(task.Status),
+				Assignee:    assignees[task.ParticipantID.Int64],
+				Estimate:    int(task.Estimate.Int64),
+				Approved:    task.Approved.Bool,
+				Labels:      task.Labels,
+				CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+				UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+	}
+
+	switch format {
+	case model.FormatCSV:
+		return exportBacklogCSV(backlog)
+	case model.FormatJSON:
+		return json.MarshalIndent(backlog, "", "  ")
+	default:
+		return exportBacklogMarkdown(project.Name, backlog), nil
+	}
+}
+
+// ImportTasks creates tasks from the file. Assignees are matched with the project
+// participants by github username, rows which can't be fully mapped are still
+// imported (without an assignee) and reported back, rows without a title are skipped.
+func (s *service) ImportTasks(ctx context.Context, userID uuid.UUID, projectID int,
+	format model.BacklogFormat, data []byte) (*model.BacklogImport, error) {
+	var (
+		rows []backlogRow
+		err  error
+	)
+	switch format {
+	case model.FormatCSV:
+		rows, err = parseBacklogCSV(data)
+	case model.FormatJSON:
+		rows, err = parseBacklogJSON(data)
+	case model.FormatMarkdown:
+		rows, err = parseBacklogMarkdown(data)
+	default:
+		return nil, ierr.ErrInvalidBacklogFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ierr.ErrInvalidBacklogFile, err)
+	}
+
+	creator, err := s.VerifyParticipant(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+	participantIDs := make(map[string]int, len(participants))
+	for _, participant := range participants {
+		participantIDs[strings.ToLower(participant.GithubUsername)] = participant.ID
+	}
+
+	var (
+		res = &model.BacklogImport{
+			Tasks: make([]model.Task, 0, len(rows)),
+			Rows:  make([]model.BacklogImportRow, 0),
+		}
+		ranks = make(map[model.TaskStatus]string, len(model.BoardStatuses))
+		now   = time.Now()
+	)
+	for _, row := range rows {
+		title := strings.TrimSpace(row.task.Title)
+		if title == "" {
+			res.Rows = append(res.Rows, model.BacklogImportRow{
+				Line:    row.line,
+				Warning: "task title is empty, row is skipped",
+			})
+			continue
+		}
+
+		task := model.Task{
+			ShortTask: model.ShortTask{
+				Name:      title,
+				Status:    model.TaskStatus(strings.ToUpper(strings.TrimSpace(row.task.Status))),
+				Labels:    normalizeLabels(row.task.Labels),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			ProjectID: projectID,
+		}
+		task.CreatorID.Scan(int64(creator.ID))
+		if strings.TrimSpace(row.task.Description) != "" {
+			task.Description.Scan(row.task.Description)
+		}
+		if row.task.Estimate > 0 {
+			task.Estimate.Scan(int64(row.task.Estimate))
+		}
+		if row.task.Approved {
+			task.Approved.Scan(true)
+		}
+
+		var warnings []string
+		if task.Status == "" {
+			task.Status = model.TODO
+		} else if _, ok := model.TaskStatuses[string(task.Status)]; !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown status %q, task is put to %s", row.task.Status, model.TODO))
+			task.Status = model.TODO
+		}
+
+		if assignee := strings.TrimPrefix(strings.TrimSpace(row.task.Assignee), "@"); assignee != "" {
+			if participantID, ok := participantIDs[strings.ToLower(assignee)]; ok {
+				task.ParticipantID.Scan(int64(participantID))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%q is not a project participant, task is left unassigned", assignee))
+			}
+		}
+
+		if _, ok := ranks[task.Status]; !ok {
+			if ranks[task.Status], err = s.lastRankInColumn(ctx, projectID, task.Status); err != nil {
+				return nil, err
+			}
+		}
+		task.Rank = ranks[task.Status]
+		ranks[task.Status] = model.RankBetween(task.Rank, "")
+
+		if len(warnings) != 0 {
+			res.Rows = append(res.Rows, model.BacklogImportRow{
+				Line:    row.line,
+				Title:   title,
+				Warning: strings.Join(warnings, "; "),
+			})
+		}
+		res.Tasks = append(res.Tasks, task)
+	}
+
+	if len(res.Tasks) == 0 {
+		return res, nil
+	}
+	if err = s.repo.InsertTasks(ctx, res.Tasks); err != nil {
+		return nil, err
+	}
+
+	for i := range res.Tasks {
+		s.notifyTaskChanged(ctx, nil, &res.Tasks[i])
+	}
+	return res, nil
+}
+
+func exportBacklogCSV(backlog []model.BacklogTask) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write(backlogCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, task := range backlog {
+		if err := w.Write([]string{
+			strconv.Itoa(task.ID),
+			task.Title,
+			task.Description,
+			task.Status,
+			task.Assignee,
+			strconv.Itoa(task.Estimate),
+			strconv.FormatBool(task.Approved),
+			strings.Join(task.Labels, ";"),
+			task.CreatedAt,
+			task.UpdatedAt,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func exportBacklogMarkdown(projectName string, backlog []model.BacklogTask) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# %s\n", projectName)
+
+	var status string
+	for _, task := range backlog {
+		if task.Status != status {
+			status = task.Status
+			fmt.Fprintf(buf, "\n## %s\n\n", status)
+		}
+
+		mark := " "
+		if task.Status == string(model.Done) {
+			mark = "x"
+		}
+		fmt.Fprintf(buf, "- [%s] %s", mark, strings.ReplaceAll(task.Title, "\n", " "))
+		if task.Assignee != "" {
+			fmt.Fprintf(buf, " @%s", task.Assignee)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+func parseBacklogCSV(data []byte) ([]backlogRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("title column is missing")
+	}
+	field := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	rows := make([]backlogRow, 0)
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := backlogRow{
+			line: line,
+			task: model.BacklogTask{
+				Title:       field(record, "title"),
+				Description: field(record, "description"),
+				Status:      field(record, "status"),
+				Assignee:    field(record, "assignee", "asignee"),
+			},
+		}
+		row.task.Estimate, _ = strconv.Atoi(field(record, "estimatedtime", "estimate"))
+		row.task.Approved, _ = strconv.ParseBool(field(record, "approved"))
+		if labels := field(record, "labels"); labels != "" {
+			row.task.Labels = strings.Split(labels, ";")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseBacklogJSON(data []byte) ([]backlogRow, error) {
+	var backlog []model.BacklogTask
+	if err := json.Unmarshal(data, &backlog); err != nil {
+		return nil, err
+	}
+
+	rows := make([]backlogRow, 0, len(backlog))
+	for i, task := range backlog {
+		rows = append(rows, backlogRow{line: i + 1, task: task})
+	}
+	return rows, nil
+}
+
+// parseBacklogMarkdown reads github task list items ("- [ ] title @assignee").
+// A "## STATUS" heading sets the status for the items below it,
+// checked items are always DONE.
+func parseBacklogMarkdown(data []byte) ([]backlogRow, error) {
+	var (
+		rows    = make([]backlogRow, 0)
+		status  string
+		scanner = bufio.NewScanner(bytes.NewReader(data))
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(text, "#") {
+			heading := strings.ToUpper(strings.TrimSpace(strings.TrimLeft(text, "#")))
+			heading = strings.ReplaceAll(heading, " ", "_")
+			if _, ok := model.TaskStatuses[heading]; ok {
+				status = heading
+			} else {
+				status = ""
+			}
+			continue
+		}
+
+		if len(text) < 6 || !strings.ContainsRune("-*+", rune(text[0])) {
+			continue
+		}
+		item := strings.TrimSpace(text[1:])
+		var checked bool
+		switch {
+		case strings.HasPrefix(item, "[ ]"):
+		case strings.HasPrefix(item, "[x]"), strings.HasPrefix(item, "[X]"):
+			checked = true
+		default:
+			continue
+		}
+
+		task := model.BacklogTask{Status: status}
+		if checked {
+			task.Status = string(model.Done)
+		}
+		words := make([]string, 0)
+		for _, word := range strings.Fields(item[3:]) {
+			if strings.HasPrefix(word, "@") && len(word) > 1 && task.Assignee == "" {
+				task.Assignee = word[1:]
+				continue
+			}
+			words = append(words, word)
+		}
+		task.Title = strings.Join(words, " ")
+
+		rows = append(rows, backlogRow{line: line, task: task})
+	}
+	return rows, scanner.Err()
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+)
+
+func (s *service) GetBoard(ctx context.Context, projectID int) (*model.Board, error) {
+	if _, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(projectID)); err != nil {
+		return nil, err
+	}
+
+	columns, err := s.repo.GetBoardColumns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	wipLimits := make(map[model.TaskStatus]sql.NullInt64, len(columns))
+	for _, column := range columns {
+		wipLimits[column.Status] = column.WIPLimit
+	}
+
+	tasks, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().
+		ByProjectID(projectID).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+	tasksByStatus := make(map[model.TaskStatus][]model.Task, len(model.BoardStatuses))
+	for _, task := range tasks {
+		tasksByStatus[task.Status] = append(tasksByStatus[task.Status], task)
+	}
+
+	board := &model.Board{
+		ProjectID: projectID,
+		Columns:   make([]model.BoardColumn, 0, len(model.BoardStatuses)),
+	}
+	for _, status := range model.BoardStatuses {
+		board.Columns = append(board.Columns, model.BoardColumn{
+			Status:   status,
+			WIPLimit: wipLimits[status],
+			Tasks:    tasksByStatus[status],
+		})
+	}
+	return board, nil
+}
+
+func (s *service) MoveTask(ctx context.Context, moveReq *api.MoveTaskReq) (*model.Task, error) {
+	if _, ok := model.TaskStatuses[moveReq.Status]; !ok {
+		return nil, ierr.ErrInvalidStatus
+	}
+
+	oldTask, err := s.getProjectTask(ctx, moveReq.ProjectID, moveReq.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.MoveTask(ctx, &model.TaskMove{
+		TaskID:    moveReq.TaskID,
+		ProjectID: moveReq.ProjectID,
+		Status:    model.TaskStatus(moveReq.Status),
+		Position:  moveReq.Position,
+		Version:   moveReq.Version,
+	}); err != nil {
+		return nil, err
+	}
+
+	newTask, err := s.repo.GetTask(ctx, repository.NewTaskFilter().ByID(moveReq.TaskID))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyTaskChanged(ctx, oldTask, newTask)
+	return newTask, nil
+}
+
+func (s *service) UpdateBoardColumn(ctx context.Context, columnReq *api.UpdateBoardColumnReq) (*model.Board, error) {
+	if _, ok := model.TaskStatuses[columnReq.Status]; !ok {
+		return nil, ierr.ErrInvalidStatus
+	}
+
+	column := &model.BoardColumn{Status: model.TaskStatus(columnReq.Status)}
+	if columnReq.WIPLimit != nil {
+		if *columnReq.WIPLimit <= 0 {
+			return nil, ierr.ErrWIPLimitIsInvalid
+		}
+		column.WIPLimit.Scan(int64(*columnReq.WIPLimit))
+	}
+
+	if err := s.repo.UpsertBoardColumn(ctx, columnReq.ProjectID, column); err != nil {
+		return nil, err
+	}
+
+	return s.GetBoard(ctx, columnReq.ProjectID)
+}
+
+// lastRankInColumn returns a rank which puts a task to the end of the column
+func (s *service) lastRankInColumn(ctx context.Context, projectID int, status model.TaskStatus) (string, error) {
+	column, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().
+		ByProjectID(projectID).
+		ByStatus(status).
+		WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return "", err
+	}
+
+	var last string
+	if len(column) != 0 {
+		last = column[len(column)-1].Rank
+	}
+	return model.RankBetween(last, ""), nil
+}
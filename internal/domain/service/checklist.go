@@ -2,20 +2,375 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
 	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
 )
 
-func (s *service) GetProjectChecklist(ctx context.Context, id int) ([]model.Checklist, error) {
-	return s.repo.GetProjectChecklist(ctx, id)
+// GetProjectChecklist returns the items of all the checklists of the project in order
+func (s *service) GetProjectChecklist(ctx context.Context, id int) ([]model.ChecklistItem, error) {
+	return s.repo.GetChecklistItems(ctx, repository.NewChecklistItemFilter().ByProjectID(id))
+}
+
+// AddProjectChecklist appends the items to the first checklist of the project, it is created if missing
+func (s *service) AddProjectChecklist(ctx context.Context, userID uuid.UUID, id int, itemsReq []api.ChecklistItemReq) ([]model.ChecklistItem, error) {
+	checklists, err := s.GetChecklists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(checklists) == 0 {
+		if _, err = s.CreateChecklist(ctx, userID, &api.ChecklistReq{
+			ProjectID: id,
+			Name:      model.DefaultChecklistName,
+			Items:     itemsReq,
+		}); err != nil {
+			return nil, err
+		}
+		return s.GetProjectChecklist(ctx, id)
+	}
+
+	if _, err = s.addChecklistItems(ctx, userID, &checklists[0], itemsReq); err != nil {
+		return nil, err
+	}
+	return s.GetProjectChecklist(ctx, id)
+}
+
+// UpdateProjectChecklist checks or unchecks the item of the project
+func (s *service) UpdateProjectChecklist(ctx context.Context, itemReq *api.ChecklistItemReq) ([]model.ChecklistItem, error) {
+	item, err := s.repo.GetChecklistItem(ctx, repository.NewChecklistItemFilter().
+		ByID(itemReq.ID).ByProjectID(itemReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	isPM, err := s.isProjectManager(ctx, itemReq.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Locked && !isPM {
+		return nil, ierr.ErrChecklistItemIsLocked
+	}
+
+	if err = s.saveChecklistItem(ctx, item, itemReq.UserID, itemReq.Checked); err != nil {
+		return nil, err
+	}
+	return s.GetProjectChecklist(ctx, itemReq.ProjectID)
+}
+
+// DeleteProjectChecklist deletes the item of the project, only a PM can delete the locked one
+func (s *service) DeleteProjectChecklist(ctx context.Context, userID uuid.UUID, id int, itemID int) ([]model.ChecklistItem, error) {
+	item, err := s.repo.GetChecklistItem(ctx, repository.NewChecklistItemFilter().
+		ByID(itemID).ByProjectID(id))
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkChecklistItemsUnlocked(ctx, userID, *item); err != nil {
+		return nil, err
+	}
+	if err = s.repo.DeleteChecklistItem(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.GetProjectChecklist(ctx, id)
 }
 
-func (s *service) AddProjectChecklist(ctx context.Context, id int, checklist []model.Checklist) ([]model.Checklist, error) {
-	return s.repo.AddProjectChecklist(ctx, id, checklist)
+func (s *service) GetChecklists(ctx context.Context, projectID int) ([]model.Checklist, error) {
+	return s.repo.GetChecklists(ctx, repository.NewChecklistFilter().
+		ByProjectID(projectID).WithPaginator(db.MaxLimit, 0))
 }
-func (s *service) UpdateProjectChecklist(ctx context.Context, id int, checklist *model.Checklist) ([]model.Checklist, error) {
-	return s.repo.UpdateProjectChecklist(ctx, id, checklist)
+
+// GetChecklistProgress returns the checklist completion of every project, projects without items are missing
+func (s *service) GetChecklistProgress(ctx context.Context, projectIDs []int) (map[int]model.ChecklistProgress, error) {
+	return s.repo.GetChecklistProgress(ctx, projectIDs)
 }
-func (s *service) DeleteProjectChecklist(ctx context.Context, id int, itemID int) ([]model.Checklist, error) {
-	return s.repo.DeleteProjectChecklist(ctx, id, itemID)
+
+// CreateChecklist adds the checklist after the others unless the position is given
+func (s *service) CreateChecklist(ctx context.Context, userID uuid.UUID, checklistReq *api.ChecklistReq) (*model.Checklist, error) {
+	name := strings.TrimSpace(checklistReq.Name)
+	if name == "" {
+		return nil, ierr.ErrChecklistIsInvalid
+	}
+
+	checklists, err := s.GetChecklists(ctx, checklistReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	checklist := &model.Checklist{
+		ProjectID: checklistReq.ProjectID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		Items:     make([]model.ChecklistItem, 0, len(checklistReq.Items)),
+	}
+	if checklistReq.Position != nil {
+		checklist.Position = *checklistReq.Position
+	} else if len(checklists) != 0 {
+		checklist.Position = checklists[len(checklists)-1].Position + 1
+	}
+
+	items, err := s.makeChecklistItems(ctx, userID, checklist, checklistReq.Items)
+	if err != nil {
+		return nil, err
+	}
+	checklist.Items = items
+
+	if err = s.repo.InsertChecklist(ctx, checklist); err != nil {
+		return nil, err
+	}
+	return checklist, nil
+}
+
+// UpdateChecklist renames and moves the checklist, ItemIDs must list all of its items in the new order
+func (s *service) UpdateChecklist(ctx context.Context, checklistReq *api.ChecklistReq) (*model.Checklist, error) {
+	checklist, err := s.repo.GetChecklist(ctx, repository.NewChecklistFilter().
+		ByID(checklistReq.ID).ByProjectID(checklistReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	if checklist.Name = strings.TrimSpace(checklistReq.Name); checklist.Name == "" {
+		return nil, ierr.ErrChecklistIsInvalid
+	}
+	if checklistReq.Position != nil {
+		checklist.Position = *checklistReq.Position
+	}
+
+	if checklistReq.ItemIDs != nil {
+		if len(checklistReq.ItemIDs) != len(checklist.Items) {
+			return nil, ierr.ErrChecklistIsInvalid
+		}
+		positions := make(map[int]int, len(checklistReq.ItemIDs))
+		for i, id := range checklistReq.ItemIDs {
+			positions[id] = i
+		}
+		items := make([]model.ChecklistItem, len(checklist.Items))
+		for _, item := range checklist.Items {
+			position, ok := positions[item.ID]
+			if !ok {
+				return nil, ierr.ErrChecklistIsInvalid
+			}
+			item.Position = position
+			items[position] = item
+		}
+		checklist.Items = items
+	}
+
+	if err = s.repo.UpdateChecklist(ctx, checklist); err != nil {
+		return nil, err
+	}
+	return checklist, nil
+}
+
+// DeleteChecklist deletes the checklist with its items, only a PM can delete the checklist with the locked items
+func (s *service) DeleteChecklist(ctx context.Context, userID uuid.UUID, projectID, checklistID int) error {
+	checklist, err := s.repo.GetChecklist(ctx, repository.NewChecklistFilter().
+		ByID(checklistID).ByProjectID(projectID))
+	if err != nil {
+		return err
+	}
+	if err = s.checkChecklistItemsUnlocked(ctx, userID, checklist.Items...); err != nil {
+		return err
+	}
+	return s.repo.DeleteChecklist(ctx, checklistID)
+}
+
+// AddChecklistItem appends the item to the end of the checklist
+func (s *service) AddChecklistItem(ctx context.Context, itemReq *api.ChecklistItemReq) (*model.Checklist, error) {
+	checklist, err := s.repo.GetChecklist(ctx, repository.NewChecklistFilter().
+		ByID(itemReq.ChecklistID).ByProjectID(itemReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addChecklistItems(ctx, itemReq.UserID, checklist, []api.ChecklistItemReq{*itemReq})
+}
+
+// UpdateChecklistItem saves the item, only a PM can lock the item and change the locked one
+func (s *service) UpdateChecklistItem(ctx context.Context, itemReq *api.ChecklistItemReq) (*model.Checklist, error) {
+	checklist, err := s.repo.GetChecklist(ctx, repository.NewChecklistFilter().
+		ByID(itemReq.ChecklistID).ByProjectID(itemReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.repo.GetChecklistItem(ctx, repository.NewChecklistItemFilter().
+		ByID(itemReq.ID).ByChecklistID(checklist.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	isPM, err := s.isProjectManager(ctx, itemReq.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Locked && !isPM {
+		return nil, ierr.ErrChecklistItemIsLocked
+	}
+	if err = s.mergeChecklistItemFields(ctx, item, itemReq, isPM); err != nil {
+		return nil, err
+	}
+
+	if err = s.saveChecklistItem(ctx, item, itemReq.UserID, itemReq.Checked); err != nil {
+		return nil, err
+	}
+	return s.repo.GetChecklist(ctx, repository.NewChecklistFilter().ByID(checklist.ID))
+}
+
+// DeleteChecklistItem deletes the item of the checklist, only a PM can delete the locked one
+func (s *service) DeleteChecklistItem(ctx context.Context, userID uuid.UUID, projectID, checklistID, itemID int) (*model.Checklist, error) {
+	item, err := s.repo.GetChecklistItem(ctx, repository.NewChecklistItemFilter().
+		ByID(itemID).ByChecklistID(checklistID).ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+	if err = s.checkChecklistItemsUnlocked(ctx, userID, *item); err != nil {
+		return nil, err
+	}
+	if err = s.repo.DeleteChecklistItem(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetChecklist(ctx, repository.NewChecklistFilter().ByID(checklistID))
+}
+
+func (s *service) addChecklistItems(ctx context.Context, userID uuid.UUID, checklist *model.Checklist, itemsReq []api.ChecklistItemReq) (*model.Checklist, error) {
+	items, err := s.makeChecklistItems(ctx, userID, checklist, itemsReq)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.InsertChecklistItems(ctx, items); err != nil {
+		return nil, err
+	}
+	return s.repo.GetChecklist(ctx, repository.NewChecklistFilter().ByID(checklist.ID))
+}
+
+// makeChecklistItems validates the new items of the checklist and puts them after the existing ones
+func (s *service) makeChecklistItems(ctx context.Context, userID uuid.UUID, checklist *model.Checklist, itemsReq []api.ChecklistItemReq) ([]model.ChecklistItem, error) {
+	isPM, err := s.isProjectManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := 0
+	for _, item := range checklist.Items {
+		if item.Position >= position {
+			position = item.Position + 1
+		}
+	}
+
+	now := time.Now()
+	items := make([]model.ChecklistItem, 0, len(itemsReq))
+	for i := range itemsReq {
+		item := model.ChecklistItem{
+			ChecklistID: checklist.ID,
+			ProjectID:   checklist.ProjectID,
+			Position:    position + i,
+		}
+		if err = s.mergeChecklistItemFields(ctx, &item, &itemsReq[i], isPM); err != nil {
+			return nil, err
+		}
+		if itemsReq[i].Checked {
+			item.Checked = true
+			item.CheckedBy = uuid.NullUUID{UUID: userID, Valid: true}
+			item.CheckedAt.Scan(now)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *service) mergeChecklistItemFields(ctx context.Context, item *model.ChecklistItem, itemReq *api.ChecklistItemReq, isPM bool) error {
+	if item.Name = strings.TrimSpace(itemReq.Name); item.Name == "" {
+		return ierr.ErrChecklistItemIsInvalid
+	}
+
+	item.ParticipantID.Valid = false
+	if itemReq.ParticipantID != nil {
+		if _, err := s.repo.GetParticipant(ctx, repository.NewParticipantFilter().
+			ByID(*itemReq.ParticipantID).ByProjectID(item.ProjectID)); err != nil {
+			return ierr.ErrChecklistItemIsInvalid
+		}
+		item.ParticipantID.Scan(int64(*itemReq.ParticipantID))
+	}
+
+	item.DueDate.Valid = false
+	if itemReq.DueDate != nil && !itemReq.DueDate.IsZero() {
+		item.DueDate.Scan(*itemReq.DueDate)
+	}
+
+	if isPM {
+		item.Locked = itemReq.Locked
+	}
+	return nil
+}
+
+// saveChecklistItem remembers who and when has checked the item and notifies the team about it
+func (s *service) saveChecklistItem(ctx context.Context, item *model.ChecklistItem, userID uuid.UUID, checked bool) error {
+	justChecked := checked && !item.Checked
+	if checked != item.Checked {
+		item.Checked = checked
+		item.CheckedBy = uuid.NullUUID{UUID: userID, Valid: checked}
+		item.CheckedAt.Valid = false
+		if checked {
+			item.CheckedAt.Scan(time.Now())
+		}
+	}
+
+	if err := s.repo.UpdateChecklistItem(ctx, item); err != nil {
+		return err
+	}
+	if justChecked {
+		s.notifyChecklistChecked(ctx, item.ProjectID, item.Name)
+	}
+	return nil
+}
+
+// checkChecklistItemsUnlocked refuses to remove the locked items unless the user is a PM,
+// otherwise a student could delete the locked item and add it again checked
+func (s *service) checkChecklistItemsUnlocked(ctx context.Context, userID uuid.UUID, items ...model.ChecklistItem) error {
+	locked := false
+	for _, item := range items {
+		locked = locked || item.Locked
+	}
+	if !locked {
+		return nil
+	}
+
+	isPM, err := s.isProjectManager(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !isPM {
+		return ierr.ErrChecklistItemIsLocked
+	}
+	return nil
+}
+
+// isProjectManager tells if the user is a PM or an admin, they can lock the checklist items
+func (s *service) isProjectManager(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.repo.GetUser(ctx, repository.NewUserFilter().ByID(userID))
+	if err != nil {
+		return false, err
+	}
+	return user.Role == model.ProjectManager || user.Role == model.Admin, nil
+}
+
+func (s *service) notifyChecklistChecked(ctx context.Context, projectID int, name string) {
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(projectID))
+	if err != nil {
+		s.logger.Errorf("failed to get project participants: %v", err)
+		return
+	}
+	userIDs := make([]uuid.UUID, 0, len(participants))
+	for _, participant := range participants {
+		userIDs = append(userIDs, participant.ShortUser.ID)
+	}
+
+	s.notify(ctx, model.EventChecklistChecked, projectID, 0,
+		fmt.Sprintf("Пункт чек-листа «%s» отмечен выполненным", name),
+		userIDs...)
 }
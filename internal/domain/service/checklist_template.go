@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func (s *service) GetChecklistTemplates(ctx context.Context, userID uuid.UUID) ([]model.ChecklistTemplate, error) {
+	return s.repo.GetChecklistTemplates(ctx, repository.NewChecklistTemplateFilter().
+		ByOwnerID(userID).WithPaginator(db.MaxLimit, 0))
+}
+
+// GetChecklistTemplate returns the template if it belongs to the PM
+func (s *service) GetChecklistTemplate(ctx context.Context, userID uuid.UUID, templateID int) (*model.ChecklistTemplate, error) {
+	return s.repo.GetChecklistTemplate(ctx, repository.NewChecklistTemplateFilter().ByID(templateID).ByOwnerID(userID))
+}
+
+func (s *service) CreateChecklistTemplate(ctx context.Context, templateReq *api.ChecklistTemplateReq) (*model.ChecklistTemplate, error) {
+	now := time.Now()
+	template := &model.ChecklistTemplate{
+		OwnerID:   templateReq.OwnerID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := mergeChecklistTemplateFields(template, templateReq); err != nil {
+		return nil, err
+	}
+
+	return template, s.repo.InsertChecklistTemplate(ctx, template)
+}
+
+// UpdateChecklistTemplate saves the template and, if asked, brings the checklists made of it up to date
+func (s *service) UpdateChecklistTemplate(ctx context.Context, templateReq *api.ChecklistTemplateReq) (*model.ChecklistTemplate, error) {
+	template, err := s.GetChecklistTemplate(ctx, templateReq.OwnerID, templateReq.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = mergeChecklistTemplateFields(template, templateReq); err != nil {
+		return nil, err
+	}
+	template.UpdatedAt = time.Now()
+
+	return template, s.repo.UpdateChecklistTemplate(ctx, template, templateReq.Propagate)
+}
+
+func (s *service) DeleteChecklistTemplate(ctx context.Context, userID uuid.UUID, templateID int) error {
+	if _, err := s.GetChecklistTemplate(ctx, userID, templateID); err != nil {
+		return err
+	}
+	return s.repo.DeleteChecklistTemplate(ctx, templateID)
+}
+
+// ApplyChecklistTemplate adds the checklist made of the template to the selected projects of the PM,
+// by default to the projects of the template course. Projects which already have it are skipped.
+func (s *service) ApplyChecklistTemplate(ctx context.Context, applyReq *api.ApplyChecklistTemplateReq) (*model.TemplateApplication, error) {
+	template, err := s.GetChecklistTemplate(ctx, applyReq.UserID, applyReq.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.NewProjectFilter().ByOwnerID(applyReq.UserID).WithPaginator(db.MaxLimit, 0)
+	if len(applyReq.ProjectIDs) == 0 {
+		course := strings.TrimSpace(applyReq.Course)
+		if course == "" {
+			course = template.Course.String
+		}
+		if course == "" {
+			return nil, ierr.ErrTemplateProjectsNotSelected
+		}
+		filter = filter.ByCourse(course)
+	}
+	projects, err := s.repo.GetProjects(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(applyReq.ProjectIDs) != 0 {
+		owned := make(map[int]model.Project, len(projects))
+		for _, project := range projects {
+			owned[project.ID] = project
+		}
+		projects = make([]model.Project, 0, len(applyReq.ProjectIDs))
+		for _, id := range applyReq.ProjectIDs {
+			project, ok := owned[id]
+			if !ok {
+				return nil, ierr.ErrProjectNotFound
+			}
+			projects = append(projects, project)
+		}
+	}
+
+	res := &model.TemplateApplication{Applied: make([]int, 0), Skipped: make([]int, 0)}
+	for _, project := range projects {
+		applied, err := s.applyChecklistTemplate(ctx, template, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			res.Applied = append(res.Applied, project.ID)
+		} else {
+			res.Skipped = append(res.Skipped, project.ID)
+		}
+	}
+	return res, nil
+}
+
+func (s *service) applyChecklistTemplate(ctx context.Context, template *model.ChecklistTemplate, projectID int) (bool, error) {
+	checklists, err := s.GetChecklists(ctx, projectID)
+	if err != nil {
+		return false, err
+	}
+
+	checklist := &model.Checklist{
+		ProjectID: projectID,
+		Name:      template.Name,
+		CreatedAt: time.Now(),
+		Items:     make([]model.ChecklistItem, 0, len(template.Items)),
+	}
+	checklist.TemplateID.Scan(int64(template.ID))
+	for _, existing := range checklists {
+		if existing.TemplateID.Valid && existing.TemplateID.Int64 == int64(template.ID) {
+			return false, nil
+		}
+		if existing.Position >= checklist.Position {
+			checklist.Position = existing.Position + 1
+		}
+	}
+
+	for _, templateItem := range template.Items {
+		item := model.ChecklistItem{
+			ProjectID: projectID,
+			Name:      templateItem.Name,
+			Position:  templateItem.Position,
+			Locked:    templateItem.Locked,
+			DueDate:   templateItem.DueDate,
+		}
+		item.TemplateItemID.Scan(int64(templateItem.ID))
+		checklist.Items = append(checklist.Items, item)
+	}
+	return true, s.repo.InsertChecklist(ctx, checklist)
+}
+
+func mergeChecklistTemplateFields(template *model.ChecklistTemplate, templateReq *api.ChecklistTemplateReq) error {
+	name := strings.TrimSpace(templateReq.Name)
+	if name == "" || len(templateReq.Items) == 0 {
+		return ierr.ErrChecklistTemplateIsInvalid
+	}
+	template.Name = name
+	template.Course.String = strings.TrimSpace(templateReq.Course)
+	template.Course.Valid = template.Course.String != ""
+
+	existing := make(map[int]struct{}, len(template.Items))
+	for _, item := range template.Items {
+		existing[item.ID] = struct{}{}
+	}
+
+	items := make([]model.ChecklistTemplateItem, 0, len(templateReq.Items))
+	for i, itemReq := range templateReq.Items {
+		if itemReq.ID != 0 {
+			if _, ok := existing[itemReq.ID]; !ok {
+				return ierr.ErrChecklistItemNotFound
+			}
+		}
+
+		item := model.ChecklistTemplateItem{
+			ID:         itemReq.ID,
+			TemplateID: template.ID,
+			Name:       strings.TrimSpace(itemReq.Name),
+			Position:   i,
+			Locked:     itemReq.Locked,
+		}
+		if item.Name == "" {
+			return ierr.ErrChecklistTemplateIsInvalid
+		}
+		if itemReq.DueDate != nil && !itemReq.DueDate.IsZero() {
+			item.DueDate.Scan(*itemReq.DueDate)
+		}
+		items = append(items, item)
+	}
+
+	template.Items = items
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// overdueDays limits how long ago a deadline may have been missed to be reminded about,
+// so old projects are not reminded about when the scheduler starts for the first time
+const overdueDays = 7
+
+// SendDeadlineReminders notifies about the project and task deadlines which are due in
+// daysBefore days or were missed. Project reminders are sent to all the participants,
+// task reminders to the assignee and the project owner. Every reminder is sent once.
+func (s *service) SendDeadlineReminders(ctx context.Context, now time.Time, daysBefore int) error {
+	reminders, err := s.repo.ClaimDeadlineReminders(ctx, now, daysBefore, overdueDays)
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		if err = s.sendDeadlineReminder(ctx, reminder); err != nil {
+			s.logger.Errorf("failed to send deadline reminder for project %v: %v", reminder.ProjectID, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) sendDeadlineReminder(ctx context.Context, reminder model.DeadlineReminder) error {
+	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(reminder.ProjectID))
+	if err != nil {
+		return err
+	}
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(reminder.ProjectID))
+	if err != nil {
+		return err
+	}
+
+	event := model.EventDeadlineUpcoming
+	if reminder.Kind == model.DeadlineOverdue {
+		event = model.EventDeadlineOverdue
+	}
+	dueDate := reminder.DueDate.Format("02.01.2006")
+
+	if !reminder.TaskID.Valid {
+		userIDs := make([]uuid.UUID, 0, len(participants))
+		for _, participant := range participants {
+			userIDs = append(userIDs, participant.ShortUser.ID)
+		}
+
+		message := fmt.Sprintf("Срок сдачи проекта «%s» — %s", project.Name, dueDate)
+		if event == model.EventDeadlineOverdue {
+			message = fmt.Sprintf("Срок сдачи проекта «%s» истёк %s", project.Name, dueDate)
+		}
+		s.notify(ctx, event, project.ID, 0, message, userIDs...)
+		return nil
+	}
+
+	task, err := s.repo.GetTask(ctx, repository.NewTaskFilter().ByID(int(reminder.TaskID.Int64)))
+	if err != nil {
+		return err
+	}
+	userIDs := make([]uuid.UUID, 0, 2)
+	for _, participant := range participants {
+		if participant.Role == model.RoleOwner ||
+			(task.ParticipantID.Valid && int64(participant.ID) == task.ParticipantID.Int64) {
+			userIDs = append(userIDs, participant.ShortUser.ID)
+		}
+	}
+
+	message := fmt.Sprintf("Срок выполнения задачи «%s» — %s", task.Name, dueDate)
+	if event == model.EventDeadlineOverdue {
+		message = fmt.Sprintf("Срок выполнения задачи «%s» истёк %s", task.Name, dueDate)
+	}
+	s.notify(ctx, event, project.ID, task.ID, message, userIDs...)
+	return nil
+}
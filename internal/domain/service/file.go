@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	signedURLTTL       = 15 * time.Minute
+	fileCleanupBatch   = 100
+	sniffContentLength = 512
+)
+
+var (
+	// fileExtensionTypes tell apart the zip and the text files by the extension, the other extensions
+	// never change the detected type, so e.g. an html or an svg file is not served as a page
+	fileExtensionTypes = map[string]map[string]string{
+		"application/zip": {
+			".zip":  "application/zip",
+			".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+			".odt":  "application/vnd.oasis.opendocument.text",
+			".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+			".odp":  "application/vnd.oasis.opendocument.presentation",
+		},
+		"text/plain": {
+			".txt": "text/plain",
+			".csv": "text/csv",
+			".md":  "text/markdown",
+		},
+	}
+	// fileTypeExtensions are the extensions of the keys of the files with the other names,
+	// the local storage serves the files by the extensions of the keys
+	fileTypeExtensions = map[string]string{
+		"application/pdf":  ".pdf",
+		"application/zip":  ".zip",
+		"application/json": ".json",
+		"text/plain":       ".txt",
+		"image/png":        ".png",
+		"image/jpeg":       ".jpg",
+		"image/gif":        ".gif",
+		"image/webp":       ".webp",
+	}
+)
+
+// UploadProjectAvatar saves the image and makes it the project's avatar
+func (s *service) UploadProjectAvatar(ctx context.Context, fileReq *api.UploadFileReq) (*model.File, error) {
+	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(fileReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := s.uploadFile(ctx, model.FileAvatar, fileReq)
+	if err != nil {
+		return nil, err
+	}
+
+	project.PhotoURL.Scan(api.FileURL(file.ID))
+	if err = s.repo.UpdateProject(ctx, project); err != nil {
+		return nil, err
+	}
+
+	s.replaceFiles(ctx, repository.NewFileFilter().ByProjectID(project.ID).ByKind(model.FileAvatar), file.ID)
+	return file, nil
+}
+
+func (s *service) UploadTaskAttachment(ctx context.Context, fileReq *api.UploadFileReq) (*model.File, error) {
+	if _, err := s.getProjectTask(ctx, fileReq.ProjectID, fileReq.TaskID); err != nil {
+		return nil, err
+	}
+	return s.uploadFile(ctx, model.FileAttachment, fileReq)
+}
+
+func (s *service) GetTaskAttachments(ctx context.Context, projectID, taskID int) ([]model.File, error) {
+	if _, err := s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFiles(ctx, repository.NewFileFilter().
+		ByTaskID(taskID).ByKind(model.FileAttachment).WithPaginator(0, 0))
+}
+
+func (s *service) DeleteTaskAttachment(ctx context.Context, projectID, taskID, fileID int) error {
+	if _, err := s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return err
+	}
+
+	filter := repository.NewFileFilter().ByID(fileID).ByTaskID(taskID)
+	if _, err := s.repo.GetFile(ctx, filter); err != nil {
+		return err
+	}
+	return s.repo.MarkFilesDeleted(ctx, filter, 0)
+}
+
+// GetFileURL returns a short-lived download url of the file.
+// Files are available to the participants of the file's project and to admins.
+func (s *service) GetFileURL(ctx context.Context, userID uuid.UUID, fileID int) (string, error) {
+	file, err := s.repo.GetFile(ctx, repository.NewFileFilter().ByID(fileID))
+	if err != nil {
+		return "", err
+	}
+	if !file.ProjectID.Valid {
+		return "", ierr.ErrFileNotFound
+	}
+
+	if _, err = s.VerifyParticipant(ctx, userID, int(file.ProjectID.Int64)); err != nil {
+		user, userErr := s.repo.GetUser(ctx, repository.NewUserFilter().ByID(userID))
+		if userErr != nil || user.Role != model.Admin {
+			return "", err
+		}
+	}
+
+	return s.files.SignedURL(ctx, file.Key, file.Name, signedURLTTL)
+}
+
+// CleanupOrphanedFiles removes the deleted and replaced files and the files of deleted
+// projects and tasks from the storage. Files which fail to be removed are retried next time.
+func (s *service) CleanupOrphanedFiles(ctx context.Context) error {
+	for {
+		files, err := s.repo.GetOrphanedFiles(ctx, fileCleanupBatch)
+		if err != nil {
+			return err
+		}
+
+		removed := make([]int, 0, len(files))
+		for _, file := range files {
+			if err = s.files.Delete(ctx, file.Key); err != nil {
+				s.logger.Errorf("failed to delete file %v from storage: %v", file.Key, err)
+				continue
+			}
+			removed = append(removed, file.ID)
+		}
+		if len(removed) > 0 {
+			if err = s.repo.DeleteFiles(ctx, removed); err != nil {
+				return err
+			}
+		}
+
+		if len(files) < fileCleanupBatch || len(removed) == 0 {
+			return nil
+		}
+	}
+}
+
+// uploadFile checks the size and the content type of the file, puts it to the storage
+// and saves it. The content type is detected by the content, the name only refines the whitelisted types.
+func (s *service) uploadFile(ctx context.Context, kind model.FileKind, fileReq *api.UploadFileReq) (*model.File, error) {
+	rule := model.FileRules[kind]
+	switch {
+	case fileReq.Size <= 0:
+		return nil, ierr.ErrFileIsEmpty
+	case fileReq.Size > rule.MaxSize:
+		return nil, fmt.Errorf("%w: max size is %d MB", ierr.ErrFileTooLarge, rule.MaxSize>>20)
+	}
+
+	head := make([]byte, sniffContentLength)
+	n, err := io.ReadFull(fileReq.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("error while reading file: %w", err)
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if _, ok := rule.ContentTypes[contentType]; !ok {
+		return nil, fmt.Errorf("%w: %s", ierr.ErrFileTypeNotAllowed, contentType)
+	}
+
+	name := filepath.Base(strings.TrimSpace(fileReq.Name))
+	ext := strings.ToLower(filepath.Ext(name))
+	if byExt, ok := fileExtensionTypes[contentType][ext]; ok {
+		contentType = byExt
+	} else {
+		ext = fileTypeExtensions[contentType]
+	}
+
+	file := &model.File{
+		Key:         fmt.Sprintf("%s/%d/%s%s", strings.ToLower(string(kind)), fileReq.ProjectID, uuid.New(), ext),
+		Name:        name,
+		ContentType: contentType,
+		Size:        fileReq.Size,
+		Kind:        kind,
+		CreatedAt:   time.Now(),
+	}
+	file.ProjectID.Scan(int64(fileReq.ProjectID))
+	if fileReq.TaskID != 0 {
+		file.TaskID.Scan(int64(fileReq.TaskID))
+	}
+	if userID := domain.UserIDFromContext(ctx); userID != uuid.Nil {
+		file.UploadedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+
+	if err = s.files.Put(ctx, file.Key, io.MultiReader(bytes.NewReader(head), fileReq.Content),
+		file.Size, file.ContentType); err != nil {
+		return nil, err
+	}
+	if err = s.repo.InsertFile(ctx, file); err != nil {
+		if deleteErr := s.files.Delete(ctx, file.Key); deleteErr != nil {
+			s.logger.Errorf("failed to delete file %v from storage: %v", file.Key, deleteErr)
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// replaceFiles marks the files matching the filter except the new one as deleted
+func (s *service) replaceFiles(ctx context.Context, filter *repository.FileFilter, newFileID int) {
+	if err := s.repo.MarkFilesDeleted(ctx, filter, newFileID); err != nil {
+		s.logger.Errorf("failed to mark replaced files as deleted: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
+)
+
+const (
+	// githubSyncBatch limits how many repositories are synced by one run of the job
+	githubSyncBatch = 10
+	// githubSyncLease is how long a claimed repository is not given to the other replicas
+	githubSyncLease = 10 * time.Minute
+	// githubComputingRetry is when to ask again after github answered that the statistics are being computed
+	githubComputingRetry = time.Minute
+	githubRetryBase      = 5 * time.Minute
+	githubRetryMax       = 6 * time.Hour
+	// githubRateReserve is the part of the rate limit left to the requests of the users
+	githubRateReserve = 100
+)
+
+// SyncGithubStats syncs the contributor statistics of the repositories which were synced more than period ago
+// or whose retry is due. The run stops calling a provider once its rate limit is exhausted, the rest of the
+// claimed repositories at it are retried after the limit resets.
+func (s *service) SyncGithubStats(ctx context.Context, now time.Time, period time.Duration) error {
+	syncs, err := s.repo.ClaimGithubSyncs(ctx, now, now.Add(githubSyncLease), now.Add(-period), githubSyncBatch)
+	if err != nil {
+		return err
+	}
+
+	limited := make(map[string]time.Time)
+	for i := range syncs {
+		if err = s.syncProjectStats(ctx, &syncs[i], now, limited); err != nil {
+			s.logger.Errorf("failed to sync github stats of repository %v of project %v: %v",
+				syncs[i].RepositoryID, syncs[i].ProjectID, err)
+		}
+	}
+	return nil
+}
+
+// ResyncGithubStats syncs the contributor statistics of every repository of the project right away
+func (s *service) ResyncGithubStats(ctx context.Context, projectID int) ([]model.GithubSync, error) {
+	syncs, err := s.GetGithubSyncs(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	limited := make(map[string]time.Time)
+	for i := range syncs {
+		if err = s.syncProjectStats(ctx, &syncs[i], now, limited); err != nil {
+			return nil, err
+		}
+	}
+	return syncs, nil
+}
+
+// GetGithubSyncs returns the sync of every repository of the project,
+// the repositories which were not synced yet are pending
+func (s *service) GetGithubSyncs(ctx context.Context, projectID int) ([]model.GithubSync, error) {
+	repositories, err := s.GetProjectRepositories(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	syncs, err := s.repo.GetGithubSyncs(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	syncByRepository := make(map[int]model.GithubSync, len(syncs))
+	for _, sync := range syncs {
+		syncByRepository[sync.RepositoryID] = sync
+	}
+
+	res := make([]model.GithubSync, 0, len(repositories))
+	for _, repository := range repositories {
+		sync, ok := syncByRepository[repository.ID]
+		if !ok {
+			sync = model.GithubSync{
+				RepositoryID:  repository.ID,
+				RepositoryURL: repository.URL,
+				ProjectID:     projectID,
+				Status:        model.SyncPending,
+				NextSyncAt:    time.Now(),
+			}
+		}
+		res = append(res, sync)
+	}
+	return res, nil
+}
+
+func (s *service) GetAllGithubSyncs(ctx context.Context) ([]model.GithubSync, error) {
+	return s.repo.GetGithubSyncs(ctx, 0)
+}
+
+// syncProjectStats fetches the statistics and the pull requests of the repository and saves them with the result of the attempt.
+// limited keeps the hosts which must not be called until the time their rate limit resets.
+// The repository which can not be read or has no provider is failed too, so it is retried later instead of every time.
+func (s *service) syncProjectStats(ctx context.Context, sync *model.GithubSync, now time.Time,
+	limited map[string]time.Time) error {
+	projectRepository, err := s.repo.GetProjectRepository(ctx, repository.NewProjectRepositoryFilter().
+		ByID(sync.RepositoryID))
+	if err != nil {
+		return s.failGithubSync(ctx, sync, now, err)
+	}
+	provider, repo, err := s.repositoryVCS(projectRepository.URL)
+	if err != nil {
+		return s.failGithubSync(ctx, sync, now, err)
+	}
+	if reset, ok := limited[repo.Host]; ok {
+		sync.Status = model.SyncRateLimited
+		sync.NextSyncAt = reset
+		return s.repo.SaveGithubSync(ctx, sync)
+	}
+
+	sync.AttemptedAt.Scan(now)
+	weeks, rate, err := s.commitProvider(provider).ContributorStats(ctx, repo)
+	var (
+		prs     []model.PullRequest
+		reviews []model.Review
+	)
+	if err == nil {
+		prs, reviews, err = pullRequestActivity(ctx, provider, repo, sync.SyncedAt.Time)
+	}
+
+	var rateLimitErr *vcs.RateLimitError
+	switch {
+	case errors.Is(err, vcs.ErrStatsComputing):
+		sync.Status = model.SyncComputing
+		sync.NextSyncAt = now.Add(githubComputingRetry)
+		return s.repo.SaveGithubSync(ctx, sync)
+	case errors.As(err, &rateLimitErr):
+		limited[repo.Host] = rateLimitErr.Reset
+		sync.Status = model.SyncRateLimited
+		sync.NextSyncAt = rateLimitErr.Reset
+		return s.repo.SaveGithubSync(ctx, sync)
+	case err != nil:
+		return s.failGithubSync(ctx, sync, now, err)
+	}
+
+	sync.Status = model.SyncOK
+	sync.Attempts = 0
+	sync.NextSyncAt = now
+	sync.SyncedAt.Scan(now)
+	sync.Error.Valid = false
+	if rate.Limit > 0 && rate.Remaining < githubRateReserve {
+		limited[repo.Host] = rate.Reset
+	}
+	if err = s.repo.SavePullRequests(ctx, sync, prs, reviews); err != nil {
+		return err
+	}
+	return s.repo.ReplaceContributorStats(ctx, weeks, sync)
+}
+
+// failGithubSync saves the failed attempt with the error and postpones the next one
+func (s *service) failGithubSync(ctx context.Context, sync *model.GithubSync, now time.Time, err error) error {
+	sync.AttemptedAt.Scan(now)
+	sync.Status = model.SyncFailed
+	sync.Attempts++
+	sync.NextSyncAt = now.Add(githubRetryDelay(sync.Attempts))
+	sync.Error.Scan(err.Error())
+	return s.repo.SaveGithubSync(ctx, sync)
+}
+
+// pullRequestActivity returns the pull requests updated since the last sync with their reviews
+func pullRequestActivity(ctx context.Context, provider vcs.VCSProvider, repo vcs.Repo,
+	since time.Time) ([]model.PullRequest, []model.Review, error) {
+	prs, err := provider.ListPullRequests(ctx, repo, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reviews := make([]model.Review, 0)
+	for _, pr := range prs {
+		prReviews, err := provider.ListReviews(ctx, repo, pr.Number)
+		if err != nil {
+			return nil, nil, err
+		}
+		reviews = append(reviews, prReviews...)
+	}
+	return prs, reviews, nil
+}
+
+// githubRetryDelay doubles the delay after every failed attempt
+func githubRetryDelay(attempts int) time.Duration {
+	delay := githubRetryBase
+	for i := 1; i < attempts && delay < githubRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > githubRetryMax {
+		return githubRetryMax
+	}
+	return delay
+}
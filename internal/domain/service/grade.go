@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var gradeBookHeader = []string{"Проект", "Фамилия", "Имя", "Группа", "Имя Github"}
+
+func (s *service) GetRubrics(ctx context.Context, userID uuid.UUID) ([]model.Rubric, error) {
+	return s.repo.GetRubrics(ctx, repository.NewRubricFilter().
+		ByOwnerID(userID).WithPaginator(db.MaxLimit, 0))
+}
+
+// GetRubric returns the rubric if it belongs to the PM
+func (s *service) GetRubric(ctx context.Context, userID uuid.UUID, rubricID int) (*model.Rubric, error) {
+	return s.repo.GetRubric(ctx, repository.NewRubricFilter().ByID(rubricID).ByOwnerID(userID))
+}
+
+func (s *service) CreateRubric(ctx context.Context, rubricReq *api.RubricReq) (*model.Rubric, error) {
+	rubric := &model.Rubric{
+		OwnerID:   rubricReq.OwnerID,
+		CreatedAt: time.Now(),
+	}
+	if err := mergeRubricFields(rubric, rubricReq); err != nil {
+		return nil, err
+	}
+
+	return rubric, s.repo.InsertRubric(ctx, rubric)
+}
+
+func (s *service) UpdateRubric(ctx context.Context, rubricReq *api.RubricReq) (*model.Rubric, error) {
+	rubric, err := s.GetRubric(ctx, rubricReq.OwnerID, rubricReq.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = mergeRubricFields(rubric, rubricReq); err != nil {
+		return nil, err
+	}
+
+	return rubric, s.repo.UpdateRubric(ctx, rubric)
+}
+
+func (s *service) DeleteRubric(ctx context.Context, userID uuid.UUID, rubricID int) error {
+	if _, err := s.GetRubric(ctx, userID, rubricID); err != nil {
+		return err
+	}
+	return s.repo.DeleteRubric(ctx, rubricID)
+}
+
+func (s *service) GetProjectGrades(ctx context.Context, userID uuid.UUID, rubricID, projectID int) ([]model.Grade, error) {
+	if _, err := s.getGradedProject(ctx, userID, rubricID, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGrades(ctx, rubricID, []int{projectID})
+}
+
+// UpdateProjectGrades saves the points put by the PM, they are never overwritten by the autofill
+func (s *service) UpdateProjectGrades(ctx context.Context, gradesReq *api.UpdateGradesReq) ([]model.Grade, error) {
+	rubric, err := s.getGradedProject(ctx, gradesReq.UserID, gradesReq.RubricID, gradesReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.gradedParticipants(ctx, gradesReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	participantIDs := make(map[int]struct{}, len(participants))
+	for _, participant := range participants {
+		participantIDs[participant.ID] = struct{}{}
+	}
+
+	// The same grade sent twice can't be upserted in one statement, the last one wins
+	type gradeKey struct{ criterionID, participantID int }
+	index := make(map[gradeKey]int, len(gradesReq.Grades))
+
+	now := time.Now()
+	grades := make([]model.Grade, 0, len(gradesReq.Grades))
+	for _, gradeReq := range gradesReq.Grades {
+		criterion, ok := findCriterion(rubric, gradeReq.CriterionID)
+		if !ok {
+			return nil, ierr.ErrCriterionNotFound
+		}
+		if gradeReq.Points < 0 || gradeReq.Points > criterion.MaxPoints {
+			return nil, ierr.ErrGradeIsInvalid
+		}
+
+		grade := model.Grade{
+			CriterionID: criterion.ID,
+			ProjectID:   gradesReq.ProjectID,
+			Points:      gradeReq.Points,
+			GradedBy:    uuid.NullUUID{UUID: gradesReq.UserID, Valid: true},
+			UpdatedAt:   now,
+		}
+		switch _, ok = participantIDs[gradeReq.ParticipantID]; {
+		case criterion.Scope == model.ScopeProject && gradeReq.ParticipantID != 0,
+			criterion.Scope == model.ScopeParticipant && !ok:
+			return nil, ierr.ErrGradeIsInvalid
+		case criterion.Scope == model.ScopeParticipant:
+			grade.ParticipantID.Scan(int64(gradeReq.ParticipantID))
+		}
+		if comment := strings.TrimSpace(gradeReq.Comment); comment != "" {
+			grade.Comment.Scan(comment)
+		}
+
+		key := gradeKey{gradeReq.CriterionID, gradeReq.ParticipantID}
+		if i, ok := index[key]; ok {
+			grades[i] = grade
+			continue
+		}
+		index[key] = len(grades)
+		grades = append(grades, grade)
+	}
+
+	if err = s.repo.UpsertGrades(ctx, grades, false); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGrades(ctx, gradesReq.RubricID, []int{gradesReq.ProjectID})
+}
+
+// AutofillGrades scores the criteria bound to a metric by the project statistics.
+// Project criteria take the sum over the team, checklist completion is in percents.
+// Participants not rated in the latest finished peer round keep their peer score grade.
+func (s *service) AutofillGrades(ctx context.Context, userID uuid.UUID, rubricID, projectID int) ([]model.Grade, error) {
+	rubric, err := s.getGradedProject(ctx, userID, rubricID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.gradedParticipants(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var withCommits, withChecklist, withPeerScore bool
+	for _, criterion := range rubric.Criteria {
+		withCommits = withCommits || criterion.Metric == model.MetricCommits
+		withChecklist = withChecklist || criterion.Metric == model.MetricChecklist
+		withPeerScore = withPeerScore || criterion.Metric == model.MetricPeerScore
+	}
+
+	metrics, err := s.participantMetrics(ctx, projectID, withCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	var checklistDone float64
+	if withChecklist {
+		progress, err := s.repo.GetChecklistProgress(ctx, []int{projectID})
+		if err != nil {
+			return nil, err
+		}
+		if p := progress[projectID]; p.Total != 0 {
+			checklistDone = float64(p.Checked) * 100 / float64(p.Total)
+		}
+	}
+
+	peerScores := make(map[int]float64)
+	if withPeerScore {
+		if peerScores, err = s.peerScores(ctx, projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	grades := make([]model.Grade, 0)
+	for _, criterion := range rubric.Criteria {
+		if criterion.Metric == model.MetricNone {
+			continue
+		}
+
+		if criterion.Scope == model.ScopeParticipant {
+			for _, participant := range participants {
+				value := metricValue(criterion.Metric, metrics[participant.ShortUser.ID])
+				if criterion.Metric == model.MetricPeerScore {
+					score, ok := peerScores[participant.ID]
+					if !ok {
+						continue
+					}
+					value = score
+				}
+
+				grade := model.Grade{
+					CriterionID: criterion.ID,
+					ProjectID:   projectID,
+					Points:      metricPoints(criterion, value),
+					Auto:        true,
+					UpdatedAt:   now,
+				}
+				grade.ParticipantID.Scan(int64(participant.ID))
+				grades = append(grades, grade)
+			}
+			continue
+		}
+
+		value := checklistDone
+		if criterion.Metric != model.MetricChecklist {
+			value = 0
+			for _, participant := range participants {
+				value += metricValue(criterion.Metric, metrics[participant.ShortUser.ID])
+			}
+		}
+		grades = append(grades, model.Grade{
+			CriterionID: criterion.ID,
+			ProjectID:   projectID,
+			Points:      metricPoints(criterion, value),
+			Auto:        true,
+			UpdatedAt:   now,
+		})
+	}
+
+	if err = s.repo.UpsertGrades(ctx, grades, true); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGrades(ctx, rubricID, []int{projectID})
+}
+
+// GetGradeBook returns a row for every student of the PM's projects of the course.
+// The course defaults to the rubric's one, the students can be filtered by the group.
+func (s *service) GetGradeBook(ctx context.Context, bookReq *api.GradeBookReq) (*model.GradeBook, error) {
+	rubric, err := s.GetRubric(ctx, bookReq.UserID, bookReq.RubricID)
+	if err != nil {
+		return nil, err
+	}
+
+	course := strings.TrimSpace(bookReq.Course)
+	if course == "" {
+		course = rubric.Course.String
+	}
+	projects, err := s.repo.GetProjects(ctx, repository.NewProjectFilter().
+		ByOwnerID(bookReq.UserID).ByCourse(course).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	book := &model.GradeBook{Rubric: *rubric, Rows: make([]model.GradeBookRow, 0)}
+	if len(projects) == 0 {
+		return book, nil
+	}
+
+	projectIDs := make([]int, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	grades, err := s.repo.GetGrades(ctx, rubric.ID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	projectPoints := make(map[int]map[int]float64, len(projects))
+	participantPoints := make(map[int64]map[int]float64)
+	for _, grade := range grades {
+		if grade.ParticipantID.Valid {
+			if participantPoints[grade.ParticipantID.Int64] == nil {
+				participantPoints[grade.ParticipantID.Int64] = make(map[int]float64)
+			}
+			participantPoints[grade.ParticipantID.Int64][grade.CriterionID] = grade.Points
+			continue
+		}
+		if projectPoints[grade.ProjectID] == nil {
+			projectPoints[grade.ProjectID] = make(map[int]float64)
+		}
+		projectPoints[grade.ProjectID][grade.CriterionID] = grade.Points
+	}
+
+	group := strings.TrimSpace(bookReq.Group)
+	for _, project := range projects {
+		participants, err := s.gradedParticipants(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, participant := range participants {
+			if group != "" && !strings.EqualFold(participant.Group, group) {
+				continue
+			}
+
+			row := model.GradeBookRow{
+				Project:     project,
+				Participant: participant,
+				Points:      make(map[int]float64, len(rubric.Criteria)),
+			}
+			var weighted, weights float64
+			for _, criterion := range rubric.Criteria {
+				points := projectPoints[project.ID][criterion.ID]
+				if criterion.Scope == model.ScopeParticipant {
+					points = participantPoints[int64(participant.ID)][criterion.ID]
+				}
+				row.Points[criterion.ID] = points
+				weighted += criterion.Weight * points / criterion.MaxPoints
+				weights += criterion.Weight
+			}
+			if weights != 0 {
+				row.Total = math.Round(weighted/weights*10000) / 100
+			}
+			book.Rows = append(book.Rows, row)
+		}
+	}
+
+	sort.SliceStable(book.Rows, func(i, j int) bool {
+		a, b := book.Rows[i], book.Rows[j]
+		if a.Participant.Group != b.Participant.Group {
+			return a.Participant.Group < b.Participant.Group
+		}
+		return a.Participant.LastName+a.Participant.FirstName < b.Participant.LastName+b.Participant.FirstName
+	})
+	return book, nil
+}
+
+func (s *service) ExportGradeBook(ctx context.Context, bookReq *api.GradeBookReq) ([]byte, error) {
+	if _, ok := model.GradeBookFormats[bookReq.Format]; !ok {
+		return nil, ierr.ErrInvalidGradeBookFormat
+	}
+
+	book, err := s.GetGradeBook(ctx, bookReq)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]string{}, gradeBookHeader...)
+	for _, criterion := range book.Rubric.Criteria {
+		header = append(header, criterion.Name)
+	}
+	header = append(header, "Итого (%)")
+
+	rows := make([][]interface{}, 0, len(book.Rows))
+	for _, row := range book.Rows {
+		line := []interface{}{row.Project.Name, row.Participant.LastName, row.Participant.FirstName,
+			row.Participant.Group, row.Participant.GithubUsername}
+		for _, criterion := range book.Rubric.Criteria {
+			line = append(line, row.Points[criterion.ID])
+		}
+		rows = append(rows, append(line, row.Total))
+	}
+
+	if bookReq.Format == model.GradeBookCSV {
+		return exportTableCSV(header, rows)
+	}
+	return exportTableXLSX(header, rows)
+}
+
+// getGradedProject returns the rubric if the PM owns both the rubric and the project
+func (s *service) getGradedProject(ctx context.Context, userID uuid.UUID, rubricID, projectID int) (*model.Rubric, error) {
+	rubric, err := s.GetRubric(ctx, userID, rubricID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.VerifyParticipantRole(ctx, userID, projectID, model.RoleOwner); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+// gradedParticipants returns the project participants except the PM
+func (s *service) gradedParticipants(ctx context.Context, projectID int) ([]model.Participant, error) {
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Participant, 0, len(participants))
+	for _, participant := range participants {
+		if participant.Role != model.RoleOwner {
+			res = append(res, participant)
+		}
+	}
+	return res, nil
+}
+
+// participantMetrics returns the statistics of the participants by the ids of the users,
+// the commits are requested from github only if they are needed
+func (s *service) participantMetrics(ctx context.Context, projectID int, withCommits bool) (map[uuid.UUID]model.CommitsInfo, error) {
+	res := make(map[uuid.UUID]model.CommitsInfo)
+	if withCommits {
+		infos, err := s.GetProjectCommits(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			res[info.ID] = info
+		}
+		return res, nil
+	}
+
+	tasks, err := s.repo.GetCompletedTasksCountByUser(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		info := res[task.UserID]
+		info.TotalTasksDone = task.TotalDone
+		res[task.UserID] = info
+	}
+
+	worklogs, err := s.repo.GetWorklogTotalsByUser(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, worklog := range worklogs {
+		info := res[worklog.UserID]
+		info.TotalTasksActual = worklog.TotalMinutes
+		res[worklog.UserID] = info
+	}
+	return res, nil
+}
+
+func mergeRubricFields(rubric *model.Rubric, rubricReq *api.RubricReq) error {
+	name := strings.TrimSpace(rubricReq.Name)
+	if name == "" || len(rubricReq.Criteria) == 0 {
+		return ierr.ErrRubricIsInvalid
+	}
+	rubric.Name = name
+	rubric.Course.String = strings.TrimSpace(rubricReq.Course)
+	rubric.Course.Valid = rubric.Course.String != ""
+
+	criteria := make([]model.Criterion, 0, len(rubricReq.Criteria))
+	for i, criterionReq := range rubricReq.Criteria {
+		if criterionReq.ID != 0 {
+			if _, ok := findCriterion(rubric, criterionReq.ID); !ok {
+				return ierr.ErrCriterionNotFound
+			}
+		}
+
+		criterion := model.Criterion{
+			ID:           criterionReq.ID,
+			RubricID:     rubric.ID,
+			Name:         strings.TrimSpace(criterionReq.Name),
+			Weight:       criterionReq.Weight,
+			MaxPoints:    criterionReq.MaxPoints,
+			Scope:        model.CriterionScope(criterionReq.Scope),
+			Metric:       model.CriterionMetric(criterionReq.Metric),
+			MetricTarget: criterionReq.MetricTarget,
+			Position:     i,
+		}
+		if criterion.Scope == "" {
+			criterion.Scope = model.ScopeProject
+		}
+		if err := validateCriterion(criterion); err != nil {
+			return err
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	rubric.Criteria = criteria
+	return nil
+}
+
+func validateCriterion(criterion model.Criterion) error {
+	metrics, ok := model.CriterionMetrics[criterion.Scope]
+	if !ok {
+		return ierr.ErrInvalidCriterionScope
+	}
+	if _, ok = metrics[criterion.Metric]; !ok {
+		return ierr.ErrInvalidCriterionMetric
+	}
+	if criterion.Name == "" || criterion.Weight <= 0 || criterion.MaxPoints <= 0 ||
+		(criterion.Metric != model.MetricNone && criterion.MetricTarget <= 0) {
+		return ierr.ErrRubricIsInvalid
+	}
+	return nil
+}
+
+func findCriterion(rubric *model.Rubric, id int) (model.Criterion, bool) {
+	for _, criterion := range rubric.Criteria {
+		if criterion.ID == id {
+			return criterion, true
+		}
+	}
+	return model.Criterion{}, false
+}
+
+func metricValue(metric model.CriterionMetric, info model.CommitsInfo) float64 {
+	switch metric {
+	case model.MetricTasksDone:
+		return float64(info.TotalTasksDone)
+	case model.MetricCommits:
+		return float64(info.TotalCommits)
+	case model.MetricActualHours:
+		return float64(info.TotalTasksActual) / 60
+	default:
+		return 0
+	}
+}
+
+// metricPoints gives MaxPoints for reaching the MetricTarget and the proportional part otherwise
+func metricPoints(criterion model.Criterion, value float64) float64 {
+	points := criterion.MaxPoints * value / criterion.MetricTarget
+	return math.Round(math.Min(points, criterion.MaxPoints)*100) / 100
+}
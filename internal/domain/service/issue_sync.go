@@ -0,0 +1,480 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
+)
+
+const (
+	issueOpen   = "open"
+	issueClosed = "closed"
+)
+
+// issueStatusLabels keep the status of the task at its issue, DONE is kept by closing the issue
+var issueStatusLabels = map[model.TaskStatus]string{
+	model.TODO:       "status: backlog",
+	model.InProgress: "status: in progress",
+	model.InReview:   "status: review",
+}
+
+// issueSyncRun is the state of one sync of the project tasks with the issues
+type issueSyncRun struct {
+	sync    *model.IssueSync
+	tracker vcs.IssueTracker
+	repo    vcs.Repo
+	now     time.Time
+	dryRun  bool
+	// logins are the github usernames of the participants by their ids,
+	// participantIDs are the ids by the lowercased usernames
+	logins         map[int64]string
+	participantIDs map[string]int64
+	links          []model.TaskIssue
+	entries        []model.IssueSyncEntry
+}
+
+func (s *service) GetIssueSync(ctx context.Context, projectID int) (*model.IssueSync, error) {
+	return s.repo.GetIssueSync(ctx, projectID)
+}
+
+// SetIssueSync turns the sync of the project on or off and selects the repository of the issues
+func (s *service) SetIssueSync(ctx context.Context, syncReq *api.IssueSyncReq) (*model.IssueSync, error) {
+	projectRepository, err := s.repo.GetProjectRepository(ctx, repository.NewProjectRepositoryFilter().
+		ByID(syncReq.RepositoryID).ByProjectID(syncReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err = s.issueTracker(projectRepository.URL); err != nil {
+		return nil, err
+	}
+
+	sync, err := s.repo.GetIssueSync(ctx, syncReq.ProjectID)
+	switch {
+	case errors.Is(err, ierr.ErrIssueSyncNotFound):
+		sync = &model.IssueSync{ProjectID: syncReq.ProjectID}
+	case err != nil:
+		return nil, err
+	}
+	if sync.RepositoryID != projectRepository.ID {
+		sync.SyncedAt = sql.NullTime{}
+	}
+	sync.RepositoryID = projectRepository.ID
+	sync.RepositoryURL = projectRepository.URL
+	sync.Enabled = syncReq.Enabled
+
+	if err = s.repo.SaveIssueSync(ctx, sync); err != nil {
+		return nil, err
+	}
+	return sync, nil
+}
+
+// SyncIssues syncs the tasks of the project with the issues right away. The dry run changes nothing
+// and keeps no log, it returns the changes the sync would make.
+func (s *service) SyncIssues(ctx context.Context, projectID int, dryRun bool) ([]model.IssueSyncEntry, error) {
+	sync, err := s.repo.GetIssueSync(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !sync.Enabled {
+		return nil, ierr.ErrIssueSyncIsDisabled
+	}
+	return s.syncIssues(ctx, sync, time.Now(), dryRun)
+}
+
+// SyncAllIssues syncs the tasks of every project with the sync enabled
+func (s *service) SyncAllIssues(ctx context.Context) error {
+	syncs, err := s.repo.GetEnabledIssueSyncs(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range syncs {
+		if _, err = s.syncIssues(ctx, &syncs[i], time.Now(), false); err != nil {
+			s.logger.Errorf("failed to sync issues of project %v: %v", syncs[i].ProjectID, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) GetIssueSyncLog(ctx context.Context, logReq *api.GetIssueSyncLogReq) ([]model.IssueSyncEntry, error) {
+	filter := repository.NewIssueSyncLogFilter().
+		ByProjectID(logReq.ProjectID).
+		WithPaginator(uint64(logReq.Limit), uint64(logReq.Offset))
+	if logReq.TaskID > 0 {
+		filter.ByTaskID(logReq.TaskID)
+	}
+	return s.repo.GetIssueSyncLog(ctx, filter)
+}
+
+// syncIssues mirrors the tasks to the issues and back. The open tasks and issues are linked when they
+// are met first, the linked ones follow the side changed since the last run, the one modified last
+// if both were. The changes made before the provider limits the rate are saved.
+func (s *service) syncIssues(ctx context.Context, sync *model.IssueSync, now time.Time,
+	dryRun bool) ([]model.IssueSyncEntry, error) {
+	tracker, repo, err := s.issueTracker(sync.RepositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(sync.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+	run := &issueSyncRun{
+		sync:           sync,
+		tracker:        tracker,
+		repo:           repo,
+		now:            now,
+		dryRun:         dryRun,
+		logins:         make(map[int64]string, len(participants)),
+		participantIDs: make(map[string]int64, len(participants)),
+		links:          make([]model.TaskIssue, 0),
+		entries:        make([]model.IssueSyncEntry, 0),
+	}
+	for _, participant := range participants {
+		if participant.GithubUsername != "" {
+			run.logins[int64(participant.ID)] = participant.GithubUsername
+			run.participantIDs[strings.ToLower(participant.GithubUsername)] = int64(participant.ID)
+		}
+	}
+
+	tasks, err := s.projectTasks(ctx, sync.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.repo.GetTaskIssues(ctx, sync.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := tracker.ListIssues(ctx, repo, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	issueByNumber := make(map[int]model.Issue, len(issues))
+	for _, issue := range issues {
+		issueByNumber[issue.Number] = issue
+	}
+	linkByTask := make(map[int64]model.TaskIssue, len(links))
+	linked := make(map[int]struct{}, len(links))
+	for _, link := range links {
+		if link.TaskID.Valid {
+			linkByTask[link.TaskID.Int64] = link
+		}
+		linked[link.Number] = struct{}{}
+	}
+
+	var rateLimitErr *vcs.RateLimitError
+	for i := range tasks {
+		task := &tasks[i]
+		var taskErr error
+		link, ok := linkByTask[int64(task.ID)]
+		switch {
+		case !ok && task.Status != model.Done:
+			taskErr = s.createIssue(ctx, run, task)
+		case ok:
+			// the issue is missing if it was deleted or moved to another repository
+			if issue, found := issueByNumber[link.Number]; found {
+				taskErr = s.syncTaskIssue(ctx, run, task, issue, link)
+			}
+		}
+		if errors.As(taskErr, &rateLimitErr) {
+			break
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	for _, issue := range issues {
+		if _, ok := linked[issue.Number]; ok || issue.State != issueOpen {
+			continue
+		}
+		s.createTaskFromIssue(ctx, run, issue)
+	}
+
+	if dryRun {
+		return run.entries, nil
+	}
+	if rateLimitErr == nil {
+		sync.SyncedAt = sql.NullTime{Time: now, Valid: true}
+	}
+	if err = s.repo.SaveIssueSyncRun(ctx, sync, run.links, run.entries); err != nil {
+		return nil, err
+	}
+	if rateLimitErr != nil {
+		return run.entries, rateLimitErr
+	}
+	return run.entries, nil
+}
+
+// issueTracker returns the provider of the repository if it can change the issues
+func (s *service) issueTracker(url string) (vcs.IssueTracker, vcs.Repo, error) {
+	provider, repo, err := s.repositoryVCS(url)
+	if err != nil {
+		return nil, vcs.Repo{}, err
+	}
+	tracker, ok := provider.(vcs.IssueTracker)
+	if !ok {
+		return nil, vcs.Repo{}, ierr.ErrIssueSyncNotSupported
+	}
+	return tracker, repo, nil
+}
+
+func (s *service) createIssue(ctx context.Context, run *issueSyncRun, task *model.Task) error {
+	entry := model.IssueSyncEntry{
+		TaskID:    sql.NullInt64{Int64: int64(task.ID), Valid: true},
+		Direction: model.IssueSyncToIssue,
+		Action:    model.IssueSyncCreate,
+		Message:   task.Name,
+	}
+	if run.dryRun {
+		run.record(entry, nil)
+		return nil
+	}
+
+	issue, err := run.tracker.CreateIssue(ctx, run.repo, issueFromTask(task, model.Issue{}, run.logins))
+	if err != nil {
+		run.record(entry, err)
+		return err
+	}
+	entry.Number = issue.Number
+	run.record(entry, nil)
+	run.link(task, issue)
+	return nil
+}
+
+// syncTaskIssue copies the side changed since the last run to the other one
+func (s *service) syncTaskIssue(ctx context.Context, run *issueSyncRun, task *model.Task,
+	issue model.Issue, link model.TaskIssue) error {
+	taskChanged := task.UpdatedAt.After(link.TaskUpdatedAt)
+	issueChanged := issue.UpdatedAt.After(link.IssueUpdatedAt)
+	diff := issueDiff(task, issue, run.participantIDs)
+	if len(diff) == 0 {
+		if (taskChanged || issueChanged) && !run.dryRun {
+			run.link(task, &issue)
+		}
+		return nil
+	}
+
+	entry := model.IssueSyncEntry{
+		TaskID:    sql.NullInt64{Int64: int64(task.ID), Valid: true},
+		Number:    issue.Number,
+		Direction: model.IssueSyncToIssue,
+		Action:    model.IssueSyncUpdate,
+		Conflict:  taskChanged && issueChanged,
+		Message:   strings.Join(diff, ", "),
+	}
+	if issueChanged && (!taskChanged || issue.UpdatedAt.After(task.UpdatedAt)) {
+		entry.Direction = model.IssueSyncToTask
+	}
+	if run.dryRun {
+		run.record(entry, nil)
+		return nil
+	}
+
+	if entry.Direction == model.IssueSyncToTask {
+		newTask, err := s.UpdateTask(ctx, taskReqFromIssue(task, issue, run.participantIDs))
+		if err != nil {
+			run.record(entry, err)
+			return err
+		}
+		run.record(entry, nil)
+		run.link(newTask, &issue)
+		return nil
+	}
+
+	updated, err := run.tracker.UpdateIssue(ctx, run.repo, issueFromTask(task, issue, run.logins))
+	if err != nil {
+		run.record(entry, err)
+		return err
+	}
+	run.record(entry, nil)
+	run.link(task, updated)
+	return nil
+}
+
+// createTaskFromIssue imports the open issue met first, the author becomes the creator if they are a participant
+func (s *service) createTaskFromIssue(ctx context.Context, run *issueSyncRun, issue model.Issue) {
+	entry := model.IssueSyncEntry{
+		Number:    issue.Number,
+		Direction: model.IssueSyncToTask,
+		Action:    model.IssueSyncCreate,
+		Message:   issue.Title,
+	}
+	if run.dryRun {
+		run.record(entry, nil)
+		return
+	}
+
+	task := &model.Task{
+		ShortTask: model.ShortTask{
+			Name:          issue.Title,
+			ParticipantID: issueAssignee(issue, run.participantIDs),
+			Status:        taskStatusFromIssue(issue, ""),
+			CreatedAt:     run.now,
+			UpdatedAt:     run.now,
+		},
+		ProjectID: run.sync.ProjectID,
+	}
+	if body := issueBody(issue); body != "" {
+		task.Description.Scan(body)
+	}
+	if id, ok := run.participantIDs[strings.ToLower(issue.AuthorLogin)]; ok {
+		task.CreatorID.Scan(id)
+	}
+
+	var err error
+	if task.Rank, err = s.lastRankInColumn(ctx, task.ProjectID, task.Status); err != nil {
+		run.record(entry, err)
+		return
+	}
+	if err = s.repo.InsertTask(ctx, task); err != nil {
+		run.record(entry, err)
+		return
+	}
+	s.notifyTaskChanged(ctx, nil, task)
+
+	entry.TaskID = sql.NullInt64{Int64: int64(task.ID), Valid: true}
+	run.record(entry, nil)
+	run.link(task, &issue)
+}
+
+// record logs the change, the failed one with its error
+func (r *issueSyncRun) record(entry model.IssueSyncEntry, err error) {
+	entry.ProjectID = r.sync.ProjectID
+	entry.CreatedAt = r.now
+	if err != nil {
+		entry.Action = model.IssueSyncFailed
+		entry.Message = err.Error()
+	}
+	r.entries = append(r.entries, entry)
+}
+
+// link remembers the versions of the task and the issue which are in sync now
+func (r *issueSyncRun) link(task *model.Task, issue *model.Issue) {
+	r.links = append(r.links, model.TaskIssue{
+		RepositoryID:   r.sync.RepositoryID,
+		Number:         issue.Number,
+		TaskID:         sql.NullInt64{Int64: int64(task.ID), Valid: true},
+		URL:            issue.URL,
+		TaskUpdatedAt:  task.UpdatedAt,
+		IssueUpdatedAt: issue.UpdatedAt,
+		SyncedAt:       r.now,
+	})
+}
+
+// issueDiff returns the fields the task and its issue differ in
+func issueDiff(task *model.Task, issue model.Issue, participantIDs map[string]int64) []string {
+	diff := make([]string, 0)
+	if task.Name != issue.Title {
+		diff = append(diff, "title")
+	}
+	if task.Description.String != issueBody(issue) {
+		diff = append(diff, "description")
+	}
+	if task.ParticipantID != issueAssignee(issue, participantIDs) {
+		diff = append(diff, "assignee")
+	}
+	if task.Status != taskStatusFromIssue(issue, task.Status) {
+		diff = append(diff, "status")
+	}
+	return diff
+}
+
+// issueFromTask changes the issue to follow the task, the labels other than the status are kept
+func issueFromTask(task *model.Task, issue model.Issue, logins map[int64]string) model.Issue {
+	issue.Title = task.Name
+	issue.Body = task.Description.String
+	issue.Assignees = make([]string, 0, 1)
+	if login, ok := logins[task.ParticipantID.Int64]; ok && task.ParticipantID.Valid {
+		issue.Assignees = append(issue.Assignees, login)
+	}
+
+	labels := make([]string, 0, len(issue.Labels)+1)
+	for _, label := range issue.Labels {
+		if _, ok := issueLabelStatus(label); !ok {
+			labels = append(labels, label)
+		}
+	}
+	if label, ok := issueStatusLabels[task.Status]; ok {
+		labels = append(labels, label)
+	}
+	issue.Labels = labels
+
+	issue.State = issueOpen
+	if task.Status == model.Done {
+		issue.State = issueClosed
+	}
+	return issue
+}
+
+// taskReqFromIssue changes the task to follow the issue. The task stays assigned if the assignee
+// of the issue is not a participant of the project.
+func taskReqFromIssue(task *model.Task, issue model.Issue, participantIDs map[string]int64) *api.UpdateTaskReq {
+	name := issue.Title
+	description := issueBody(issue)
+	status := string(taskStatusFromIssue(issue, task.Status))
+	taskReq := &api.UpdateTaskReq{
+		ID:          task.ID,
+		ProjectID:   task.ProjectID,
+		Name:        &name,
+		Description: &description,
+		Status:      &status,
+	}
+	if assignee := issueAssignee(issue, participantIDs); assignee.Valid {
+		participantID := int(assignee.Int64)
+		taskReq.ParticipantID = &participantID
+	} else if len(issue.Assignees) == 0 {
+		participantID := 0
+		taskReq.ParticipantID = &participantID
+	}
+	return taskReq
+}
+
+// taskStatusFromIssue returns DONE for the closed issue and the status of the label for the open one.
+// The open issue without the label keeps the status of the task, the reopened one goes to the backlog.
+func taskStatusFromIssue(issue model.Issue, current model.TaskStatus) model.TaskStatus {
+	if issue.State == issueClosed {
+		return model.Done
+	}
+	for _, label := range issue.Labels {
+		if status, ok := issueLabelStatus(label); ok {
+			return status
+		}
+	}
+	if current == "" || current == model.Done {
+		return model.TODO
+	}
+	return current
+}
+
+func issueLabelStatus(label string) (model.TaskStatus, bool) {
+	for status, statusLabel := range issueStatusLabels {
+		if strings.EqualFold(label, statusLabel) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// issueAssignee returns the participant of the first assignee of the issue known in the project
+func issueAssignee(issue model.Issue, participantIDs map[string]int64) sql.NullInt64 {
+	for _, login := range issue.Assignees {
+		if id, ok := participantIDs[strings.ToLower(login)]; ok {
+			return sql.NullInt64{Int64: id, Valid: true}
+		}
+	}
+	return sql.NullInt64{}
+}
+
+// issueBody returns the body with the line breaks github saves as \r\n
+func issueBody(issue model.Issue) string {
+	return strings.ReplaceAll(issue.Body, "\r\n", "\n")
+}
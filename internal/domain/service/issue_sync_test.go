@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/vcs"
+)
+
+var (
+	issueRepo = vcs.Repo{Host: vcs.GithubHost, Owner: "team", Name: "app"}
+	// lastIssueSync is when the linked tasks and issues were synced before the tests
+	lastIssueSync = time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// newIssueSyncTest returns the project with the sync of its issues enabled and two participants, alice and bob
+func newIssueSyncTest(t *testing.T, tracker vcs.VCSProvider) (*service, *stubRepository) {
+	t.Helper()
+	repo := &stubRepository{
+		participants: []model.Participant{
+			{ID: 1, ProjectID: 1, ShortUser: model.ShortUser{GithubUsername: "alice"}},
+			{ID: 2, ProjectID: 1, ShortUser: model.ShortUser{GithubUsername: "bob"}},
+		},
+		issueSync: &model.IssueSync{ProjectID: 1, RepositoryID: 10, RepositoryURL: "https://github.com/team/app",
+			Enabled: true},
+	}
+	return newTestService(t, repo, vcs.Providers{vcs.GithubHost: tracker}), repo
+}
+
+// linkTask adds the task with its issue synced at lastIssueSync
+func (r *stubRepository) linkTask(task model.Task, number int) {
+	task.ProjectID = 1
+	r.tasks = append(r.tasks, task)
+	r.taskIssues = append(r.taskIssues, model.TaskIssue{RepositoryID: 10, Number: number,
+		TaskID: nullInt64(int64(task.ID)), TaskUpdatedAt: lastIssueSync, IssueUpdatedAt: lastIssueSync})
+}
+
+func nullInt64(v int64) (res sql.NullInt64) {
+	res.Scan(v)
+	return res
+}
+
+func TestSyncIssuesResolvesConflicts(t *testing.T) {
+	tracker := vcs.NewFakeProvider()
+	s, repo := newIssueSyncTest(t, tracker)
+	var (
+		earlier = lastIssueSync.Add(time.Hour)
+		later   = lastIssueSync.Add(2 * time.Hour)
+	)
+	// both sides of the first two are changed, the one modified last wins
+	repo.linkTask(model.Task{ShortTask: model.ShortTask{ID: 1, Name: "Login page", Status: model.TODO,
+		UpdatedAt: earlier}}, 1)
+	repo.linkTask(model.Task{ShortTask: model.ShortTask{ID: 2, Name: "Signup v2", Status: model.TODO,
+		UpdatedAt: later}}, 2)
+	repo.linkTask(model.Task{ShortTask: model.ShortTask{ID: 3, Name: "Logout", Status: model.InProgress,
+		UpdatedAt: lastIssueSync}}, 3)
+	tracker.AddIssues(issueRepo,
+		model.Issue{Number: 1, Title: "Login form", State: "open", UpdatedAt: later},
+		model.Issue{Number: 2, Title: "Signup", State: "open", UpdatedAt: earlier},
+		// only the issue is changed, it is closed
+		model.Issue{Number: 3, Title: "Logout", State: "closed", Labels: []string{"status: in progress"},
+			UpdatedAt: earlier},
+	)
+
+	entries, err := s.SyncIssues(context.Background(), 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.IssueSyncEntry{
+		{Number: 1, Direction: model.IssueSyncToTask, Action: model.IssueSyncUpdate, Conflict: true, Message: "title"},
+		{Number: 2, Direction: model.IssueSyncToIssue, Action: model.IssueSyncUpdate, Conflict: true, Message: "title"},
+		{Number: 3, Direction: model.IssueSyncToTask, Action: model.IssueSyncUpdate, Message: "status"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("SyncIssues() = %+v", entries)
+	}
+	for i, entry := range entries {
+		if entry.Number != want[i].Number || entry.Direction != want[i].Direction || entry.Action != want[i].Action ||
+			entry.Conflict != want[i].Conflict || entry.Message != want[i].Message {
+			t.Errorf("entry %d = %+v, want %+v", i, entry, want[i])
+		}
+	}
+
+	if task := repo.tasks[0]; task.Name != "Login form" {
+		t.Errorf("task changed before its issue = %+v", task)
+	}
+	if issue := tracker.Issues[issueRepo.FullName()][1]; issue.Title != "Signup v2" || issue.State != "open" {
+		t.Errorf("issue changed before its task = %+v", issue)
+	}
+	if task := repo.tasks[2]; task.Status != model.Done {
+		t.Errorf("task of the closed issue = %+v", task)
+	}
+
+	if len(repo.issueSyncRuns) != 1 {
+		t.Fatalf("runs = %+v", repo.issueSyncRuns)
+	}
+	run := repo.issueSyncRuns[0]
+	if !run.sync.SyncedAt.Valid || len(run.links) != 3 {
+		t.Errorf("saved run = %+v", run)
+	}
+	for _, link := range run.links {
+		if !link.TaskUpdatedAt.After(lastIssueSync) || !link.IssueUpdatedAt.After(lastIssueSync) {
+			t.Errorf("link keeps the old versions: %+v", link)
+		}
+	}
+}
+
+func TestSyncIssuesDryRun(t *testing.T) {
+	tracker := vcs.NewFakeProvider()
+	s, repo := newIssueSyncTest(t, tracker)
+	repo.linkTask(model.Task{ShortTask: model.ShortTask{ID: 1, Name: "Login page", Status: model.TODO,
+		UpdatedAt: lastIssueSync.Add(time.Hour)}}, 1)
+	repo.tasks = append(repo.tasks, model.Task{ShortTask: model.ShortTask{ID: 2, Name: "Signup", Status: model.TODO},
+		ProjectID: 1})
+	tracker.AddIssues(issueRepo,
+		model.Issue{Number: 1, Title: "Login", State: "open", UpdatedAt: lastIssueSync},
+		model.Issue{Number: 2, Title: "Crash on start", State: "open"},
+	)
+
+	entries, err := s.SyncIssues(context.Background(), 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		direction model.IssueSyncDirection
+		action    model.IssueSyncAction
+	}{
+		{model.IssueSyncToIssue, model.IssueSyncUpdate},
+		{model.IssueSyncToIssue, model.IssueSyncCreate},
+		{model.IssueSyncToTask, model.IssueSyncCreate},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("SyncIssues() = %+v", entries)
+	}
+	for i, entry := range entries {
+		if entry.Direction != want[i].direction || entry.Action != want[i].action {
+			t.Errorf("entry %d = %+v, want %+v", i, entry, want[i])
+		}
+	}
+
+	// the dry run changes nothing and keeps no log
+	if repo.taskWrites != 0 || len(repo.issueSyncRuns) != 0 {
+		t.Errorf("dry run wrote %d tasks and saved %d runs", repo.taskWrites, len(repo.issueSyncRuns))
+	}
+	issues := tracker.Issues[issueRepo.FullName()]
+	if len(issues) != 2 || issues[0].Title != "Login" {
+		t.Errorf("dry run changed the issues: %+v", issues)
+	}
+}
+
+func TestSyncIssuesReadsEveryTask(t *testing.T) {
+	s, repo := newIssueSyncTest(t, vcs.NewFakeProvider())
+	for i := 1; i <= db.MaxLimit+1; i++ {
+		repo.tasks = append(repo.tasks, model.Task{ShortTask: model.ShortTask{ID: i, Status: model.TODO}, ProjectID: 1})
+	}
+
+	entries, err := s.SyncIssues(context.Background(), 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the tasks past the first page get their issues too
+	if len(entries) != db.MaxLimit+1 {
+		t.Errorf("SyncIssues() planned %d entries, want %d", len(entries), db.MaxLimit+1)
+	}
+}
+
+func TestSyncIssuesImportsUnlinkedIssues(t *testing.T) {
+	tracker := vcs.NewFakeProvider()
+	s, repo := newIssueSyncTest(t, tracker)
+	// the task of the second issue was deleted, the issue is not imported again
+	repo.taskIssues = append(repo.taskIssues, model.TaskIssue{RepositoryID: 10, Number: 2})
+	tracker.AddIssues(issueRepo,
+		model.Issue{Number: 1, Title: "Crash on start", Body: "Steps:\r\n1. run", State: "open", AuthorLogin: "Bob",
+			Assignees: []string{"stranger", "Alice"}, Labels: []string{"bug", "status: in progress"},
+			URL: "https://github.com/team/app/issues/1"},
+		model.Issue{Number: 2, Title: "Deleted", State: "open"},
+		model.Issue{Number: 3, Title: "Closed", State: "closed"},
+	)
+
+	entries, err := s.SyncIssues(context.Background(), 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Number != 1 || entries[0].Action != model.IssueSyncCreate ||
+		entries[0].Direction != model.IssueSyncToTask {
+		t.Fatalf("SyncIssues() = %+v", entries)
+	}
+	if len(repo.tasks) != 1 {
+		t.Fatalf("tasks = %+v, want the open issue imported", repo.tasks)
+	}
+	task := repo.tasks[0]
+	if task.Name != "Crash on start" || task.Description.String != "Steps:\n1. run" || task.ProjectID != 1 ||
+		task.Status != model.InProgress || task.ParticipantID.Int64 != 1 || task.CreatorID.Int64 != 2 {
+		t.Errorf("imported task = %+v", task)
+	}
+	if task.Rank == "" {
+		t.Error("imported task is not ranked")
+	}
+	if entries[0].TaskID.Int64 != int64(task.ID) {
+		t.Errorf("entry task = %d, want %d", entries[0].TaskID.Int64, task.ID)
+	}
+
+	links := repo.issueSyncRuns[0].links
+	if len(links) != 1 || links[0].Number != 1 || links[0].TaskID.Int64 != int64(task.ID) ||
+		links[0].URL != "https://github.com/team/app/issues/1" {
+		t.Errorf("links = %+v", links)
+	}
+}
+
+// rateLimitedTracker limits the rate after creating the given number of issues
+type rateLimitedTracker struct {
+	*vcs.FakeProvider
+	creates int
+	reset   time.Time
+}
+
+func (t *rateLimitedTracker) CreateIssue(ctx context.Context, repo vcs.Repo, issue model.Issue) (*model.Issue, error) {
+	if t.creates == 0 {
+		return nil, &vcs.RateLimitError{Reset: t.reset}
+	}
+	t.creates--
+	return t.FakeProvider.CreateIssue(ctx, repo, issue)
+}
+
+func TestSyncIssuesStopsAtRateLimit(t *testing.T) {
+	tracker := &rateLimitedTracker{FakeProvider: vcs.NewFakeProvider(), creates: 1,
+		reset: time.Date(2023, 3, 6, 13, 0, 0, 0, time.UTC)}
+	s, repo := newIssueSyncTest(t, tracker)
+	for i, name := range []string{"Login", "Signup", "Logout"} {
+		repo.tasks = append(repo.tasks, model.Task{ShortTask: model.ShortTask{ID: i + 1, Name: name,
+			Status: model.TODO}, ProjectID: 1})
+	}
+
+	entries, err := s.SyncIssues(context.Background(), 1, false)
+	var rateLimitErr *vcs.RateLimitError
+	if !errors.As(err, &rateLimitErr) || !rateLimitErr.Reset.Equal(tracker.reset) {
+		t.Fatalf("SyncIssues() error = %v, want the rate limit", err)
+	}
+	// the third task is not tried after the limit
+	if len(entries) != 2 || entries[0].Action != model.IssueSyncCreate || entries[0].Number != 1 ||
+		entries[1].Action != model.IssueSyncFailed || entries[1].TaskID.Int64 != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	// the issue created before the limit is linked, the run is synced again from the start
+	if len(repo.issueSyncRuns) != 1 {
+		t.Fatalf("runs = %+v", repo.issueSyncRuns)
+	}
+	run := repo.issueSyncRuns[0]
+	if run.sync.SyncedAt.Valid {
+		t.Errorf("run stopped by the rate limit is synced at %v", run.sync.SyncedAt.Time)
+	}
+	if len(run.links) != 1 || run.links[0].TaskID.Int64 != 1 || run.links[0].Number != 1 {
+		t.Errorf("links = %+v", run.links)
+	}
+	if len(run.entries) != 2 {
+		t.Errorf("saved entries = %+v", run.entries)
+	}
+}
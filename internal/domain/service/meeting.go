@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// meetingLabel marks the tasks made of the meeting action items
+const meetingLabel = "meeting"
+
+func (s *service) GetMeetings(ctx context.Context, projectID int) ([]model.Meeting, error) {
+	return s.repo.GetMeetings(ctx, repository.NewMeetingFilter().
+		ByProjectID(projectID).WithPaginator(db.MaxLimit, 0))
+}
+
+func (s *service) GetMeeting(ctx context.Context, projectID, meetingID int) (*model.Meeting, error) {
+	return s.repo.GetMeeting(ctx, repository.NewMeetingFilter().ByID(meetingID).ByProjectID(projectID))
+}
+
+// CreateMeeting saves the meeting record and makes a task of every action item
+func (s *service) CreateMeeting(ctx context.Context, meetingReq *api.MeetingReq) (*model.Meeting, error) {
+	now := time.Now()
+	meeting := &model.Meeting{
+		ProjectID: meetingReq.ProjectID,
+		CreatedBy: uuid.NullUUID{UUID: meetingReq.UserID, Valid: true},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.mergeMeetingFields(ctx, meeting, meetingReq); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.InsertMeeting(ctx, meeting); err != nil {
+		return nil, err
+	}
+	return meeting, s.createActionItemTasks(ctx, meetingReq.UserID, meeting)
+}
+
+// UpdateMeeting replaces the meeting record, new action items become tasks
+// and the tasks of the removed ones are kept
+func (s *service) UpdateMeeting(ctx context.Context, meetingReq *api.MeetingReq) (*model.Meeting, error) {
+	meeting, err := s.GetMeeting(ctx, meetingReq.ProjectID, meetingReq.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.mergeMeetingFields(ctx, meeting, meetingReq); err != nil {
+		return nil, err
+	}
+	meeting.UpdatedAt = time.Now()
+
+	if err = s.repo.UpdateMeeting(ctx, meeting); err != nil {
+		return nil, err
+	}
+	return meeting, s.createActionItemTasks(ctx, meetingReq.UserID, meeting)
+}
+
+func (s *service) DeleteMeeting(ctx context.Context, projectID, meetingID int) error {
+	if _, err := s.GetMeeting(ctx, projectID, meetingID); err != nil {
+		return err
+	}
+	return s.repo.DeleteMeeting(ctx, meetingID)
+}
+
+func (s *service) GetMeetingAttendance(ctx context.Context, projectID int) ([]model.Attendance, error) {
+	return s.repo.GetAttendance(ctx, projectID)
+}
+
+func (s *service) mergeMeetingFields(ctx context.Context, meeting *model.Meeting, meetingReq *api.MeetingReq) error {
+	meeting.Title = strings.TrimSpace(meetingReq.Title)
+	meeting.Kind = model.MeetingKind(meetingReq.Kind)
+	if meeting.Kind == "" {
+		meeting.Kind = model.MeetingWeekly
+	}
+	if _, ok := model.MeetingKinds[meeting.Kind]; !ok || meeting.Title == "" || meetingReq.HeldAt.IsZero() {
+		return ierr.ErrMeetingIsInvalid
+	}
+	meeting.HeldAt = meetingReq.HeldAt
+	meeting.Notes = strings.TrimSpace(meetingReq.Notes)
+	meeting.Decisions = strings.TrimSpace(meetingReq.Decisions)
+
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(meeting.ProjectID))
+	if err != nil {
+		return err
+	}
+	onProject := make(map[int]struct{}, len(participants))
+	for _, participant := range participants {
+		onProject[participant.ID] = struct{}{}
+	}
+
+	present := make(map[int]struct{}, len(meetingReq.Attendees))
+	for _, id := range meetingReq.Attendees {
+		if _, ok := onProject[id]; !ok {
+			return ierr.ErrMeetingIsInvalid
+		}
+		present[id] = struct{}{}
+	}
+	meeting.Attendees = make([]model.MeetingAttendee, 0, len(participants))
+	for _, participant := range participants {
+		_, ok := present[participant.ID]
+		meeting.Attendees = append(meeting.Attendees, model.MeetingAttendee{ParticipantID: participant.ID, Present: ok})
+	}
+
+	existing := make(map[int]model.ActionItem, len(meeting.ActionItems))
+	for _, item := range meeting.ActionItems {
+		existing[item.ID] = item
+	}
+	items := make([]model.ActionItem, 0, len(meetingReq.ActionItems))
+	for i, itemReq := range meetingReq.ActionItems {
+		item := model.ActionItem{
+			ID:       itemReq.ID,
+			Text:     strings.TrimSpace(itemReq.Text),
+			Position: i,
+		}
+		if itemReq.ID != 0 {
+			old, ok := existing[itemReq.ID]
+			if !ok {
+				return ierr.ErrActionItemIsInvalid
+			}
+			item.TaskID = old.TaskID
+		}
+		if item.Text == "" {
+			return ierr.ErrActionItemIsInvalid
+		}
+		if itemReq.ParticipantID != nil {
+			if _, ok := onProject[*itemReq.ParticipantID]; !ok {
+				return ierr.ErrActionItemIsInvalid
+			}
+			item.ParticipantID.Scan(int64(*itemReq.ParticipantID))
+		}
+		if itemReq.DueDate != nil && !itemReq.DueDate.IsZero() {
+			item.DueDate.Scan(*itemReq.DueDate)
+		}
+		items = append(items, item)
+	}
+	meeting.ActionItems = items
+	return nil
+}
+
+// createActionItemTasks makes a task of every action item which does not have one yet,
+// so an item whose task has failed to be created gets it on the next update
+func (s *service) createActionItemTasks(ctx context.Context, userID uuid.UUID, meeting *model.Meeting) error {
+	for i := range meeting.ActionItems {
+		item := &meeting.ActionItems[i]
+		if item.TaskID.Valid {
+			continue
+		}
+
+		taskReq := &api.CreateTaskReq{
+			Name: item.Text,
+			Description: fmt.Sprintf("Решение встречи «%s» от %s",
+				meeting.Title, meeting.HeldAt.Format("02.01.2006")),
+			Labels:    []string{meetingLabel},
+			ProjectID: meeting.ProjectID,
+		}
+		if item.ParticipantID.Valid {
+			participantID := int(item.ParticipantID.Int64)
+			taskReq.ParticipantID = &participantID
+		}
+		if item.DueDate.Valid {
+			taskReq.DueDate = &item.DueDate.Time
+		}
+
+		task, err := s.CreateTask(ctx, userID, taskReq)
+		if err != nil {
+			return fmt.Errorf("failed to create the task of action item %v: %w", item.ID, err)
+		}
+		if err = s.repo.SetActionItemTask(ctx, item.ID, task.ID); err != nil {
+			return err
+		}
+		item.TaskID.Scan(int64(task.ID))
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func (s *service) GetNotifications(ctx context.Context, notificationReq *api.GetNotificationsReq) ([]model.Notification, int, error) {
+	filter := repository.NewNotificationFilter().
+		WithPaginator(uint64(notificationReq.Limit), uint64(notificationReq.Offset)).
+		ByUserID(notificationReq.UserID)
+	if notificationReq.Unread {
+		filter.ByUnread()
+	}
+
+	notifications, err := s.repo.GetNotifications(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread, err := s.GetUnreadNotificationCount(ctx, notificationReq.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (s *service) GetUnreadNotificationCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.GetNotificationCountByFilter(ctx, repository.NewNotificationFilter().
+		ByUserID(userID).ByUnread())
+}
+
+func (s *service) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []int) error {
+	return s.repo.MarkNotificationsRead(ctx, userID, ids)
+}
+
+// GetNotificationPreferences returns preferences for every event,
+// the events the user has not set up are enabled
+func (s *service) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	saved, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[model.NotificationEvent]bool, len(saved))
+	for _, preference := range saved {
+		enabled[preference.Event] = preference.Enabled
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(model.NotificationEventOrder))
+	for _, event := range model.NotificationEventOrder {
+		preference := model.NotificationPreference{Event: event, Enabled: true}
+		if v, ok := enabled[event]; ok {
+			preference.Enabled = v
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (s *service) UpdateNotificationPreferences(ctx context.Context, userID uuid.UUID,
+	preferences []model.NotificationPreference) ([]model.NotificationPreference, error) {
+	for _, preference := range preferences {
+		if _, ok := model.NotificationEvents[preference.Event]; !ok {
+			return nil, ierr.ErrInvalidNotificationEvent
+		}
+	}
+
+	if err := s.repo.UpsertNotificationPreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetNotificationPreferences(ctx, userID)
+}
+
+// GetUserChannels returns settings for every delivery channel, the channels
+// the user has not set up are disabled
+func (s *service) GetUserChannels(ctx context.Context, userID uuid.UUID) ([]model.UserChannel, error) {
+	saved, err := s.repo.GetUserChannels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	savedByName := make(map[model.DeliveryChannel]model.UserChannel, len(saved))
+	for _, channel := range saved {
+		savedByName[channel.Channel] = channel
+	}
+
+	channels := make([]model.UserChannel, 0, len(model.DeliveryChannelOrder))
+	for _, name := range model.DeliveryChannelOrder {
+		channel, ok := savedByName[name]
+		if !ok {
+			channel = model.UserChannel{Channel: name}
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+func (s *service) UpdateUserChannels(ctx context.Context, userID uuid.UUID,
+	channels []model.UserChannel) ([]model.UserChannel, error) {
+	for i, channel := range channels {
+		if _, ok := model.DeliveryChannels[channel.Channel]; !ok {
+			return nil, ierr.ErrInvalidDeliveryChannel
+		}
+		channels[i].Address = strings.TrimSpace(channel.Address)
+		if channel.Enabled && channel.Channel != model.ChannelEmail && channels[i].Address == "" {
+			return nil, fmt.Errorf("%w: address is required for %s", ierr.ErrInvalidDeliveryChannel, channel.Channel)
+		}
+	}
+
+	if err := s.repo.UpsertUserChannels(ctx, userID, channels); err != nil {
+		return nil, err
+	}
+	return s.GetUserChannels(ctx, userID)
+}
+
+func (s *service) WatchTask(ctx context.Context, userID uuid.UUID, projectID, taskID int) error {
+	if _, err := s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	return s.repo.AddTaskWatcher(ctx, taskID, userID)
+}
+
+func (s *service) UnwatchTask(ctx context.Context, userID uuid.UUID, projectID, taskID int) error {
+	if _, err := s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTaskWatcher(ctx, taskID, userID)
+}
+
+func (s *service) GetTaskWatchers(ctx context.Context, projectID, taskID int) ([]model.ShortUser, error) {
+	if _, err := s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTaskWatchers(ctx, taskID)
+}
+
+// notify creates notifications for the users. The user who made the request
+// and the users who turned the event off are skipped. Notifications are not
+// essential for the action which caused them, so errors are only logged.
+func (s *service) notify(ctx context.Context, event model.NotificationEvent, projectID, taskID int,
+	message string, userIDs ...uuid.UUID) {
+	var (
+		actorID = domain.UserIDFromContext(ctx)
+		seen    = make(map[uuid.UUID]struct{}, len(userIDs))
+		unique  = make([]uuid.UUID, 0, len(userIDs))
+	)
+	for _, id := range userIDs {
+		if _, ok := seen[id]; ok || id == uuid.Nil || id == actorID {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	if len(unique) == 0 {
+		return
+	}
+
+	muted, err := s.repo.GetMutedUserIDs(ctx, event, unique)
+	if err != nil {
+		s.logger.Errorf("failed to get notification preferences: %v", err)
+		return
+	}
+	for _, id := range muted {
+		delete(seen, id)
+	}
+
+	notifications := make([]model.Notification, 0, len(unique))
+	for _, id := range unique {
+		if _, ok := seen[id]; !ok {
+			continue
+		}
+		notification := model.Notification{
+			UserID:    id,
+			Event:     event,
+			Message:   message,
+			CreatedAt: time.Now(),
+		}
+		if projectID != 0 {
+			notification.ProjectID.Scan(int64(projectID))
+		}
+		if taskID != 0 {
+			notification.TaskID.Scan(int64(taskID))
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = s.repo.InsertNotifications(ctx, notifications); err != nil {
+		s.logger.Errorf("failed to save notifications: %v", err)
+	}
+}
+
+// notifyTaskChanged notifies about the assignee and the status changes, oldTask is nil for a new task
+func (s *service) notifyTaskChanged(ctx context.Context, oldTask, newTask *model.Task) {
+	if newTask.ParticipantID.Valid && (oldTask == nil || oldTask.ParticipantID != newTask.ParticipantID) {
+		s.notify(ctx, model.EventTaskAssigned, newTask.ProjectID, newTask.ID,
+			fmt.Sprintf("Вам назначена задача «%s»", newTask.Name),
+			s.participantUserID(ctx, newTask.ParticipantID.Int64))
+	}
+
+	if oldTask == nil || oldTask.Status == newTask.Status {
+		return
+	}
+
+	watchers, err := s.repo.GetTaskWatchers(ctx, newTask.ID)
+	if err != nil {
+		s.logger.Errorf("failed to get task watchers: %v", err)
+	}
+	watcherIDs := make([]uuid.UUID, 0, len(watchers))
+	for _, watcher := range watchers {
+		watcherIDs = append(watcherIDs, watcher.ID)
+	}
+	s.notify(ctx, model.EventTaskStatusChanged, newTask.ProjectID, newTask.ID,
+		fmt.Sprintf("Задача «%s» перемещена в %s", newTask.Name, newTask.Status),
+		watcherIDs...)
+
+	if newTask.Status == model.InReview && newTask.CreatorID.Valid {
+		s.notify(ctx, model.EventTaskInReview, newTask.ProjectID, newTask.ID,
+			fmt.Sprintf("Задача «%s» ожидает проверки", newTask.Name),
+			s.participantUserID(ctx, newTask.CreatorID.Int64))
+	}
+}
+
+func (s *service) participantUserID(ctx context.Context, participantID int64) uuid.UUID {
+	participant, err := s.repo.GetParticipant(ctx, repository.NewParticipantFilter().ByID(int(participantID)))
+	if err != nil {
+		s.logger.Errorf("failed to get participant %v: %v", participantID, err)
+		return uuid.Nil
+	}
+	return participant.ShortUser.ID
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+func (r *stubRepository) GetNotificationPreferences(context.Context, uuid.UUID) ([]model.NotificationPreference, error) {
+	return []model.NotificationPreference{{Event: model.EventDeadlineOverdue, Enabled: false}}, nil
+}
+
+func (r *stubRepository) GetUserChannels(context.Context, uuid.UUID) ([]model.UserChannel, error) {
+	return []model.UserChannel{{Channel: model.ChannelTelegram, Address: "42", Enabled: true}}, nil
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	s := newTestService(t, &stubRepository{}, nil)
+
+	preferences, err := s.GetNotificationPreferences(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preferences) != len(model.NotificationEvents) {
+		t.Fatalf("GetNotificationPreferences() returned %d events, want %d",
+			len(preferences), len(model.NotificationEvents))
+	}
+	// the events keep their order, so the settings page does not reshuffle
+	for i, preference := range preferences {
+		if preference.Event != model.NotificationEventOrder[i] {
+			t.Errorf("event %d = %s, want %s", i, preference.Event, model.NotificationEventOrder[i])
+		}
+		if wantEnabled := preference.Event != model.EventDeadlineOverdue; preference.Enabled != wantEnabled {
+			t.Errorf("%s enabled = %v, want %v", preference.Event, preference.Enabled, wantEnabled)
+		}
+	}
+}
+
+func TestGetUserChannels(t *testing.T) {
+	s := newTestService(t, &stubRepository{}, nil)
+
+	channels, err := s.GetUserChannels(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != len(model.DeliveryChannels) {
+		t.Fatalf("GetUserChannels() returned %d channels, want %d", len(channels), len(model.DeliveryChannels))
+	}
+	for i, channel := range channels {
+		if channel.Channel != model.DeliveryChannelOrder[i] {
+			t.Errorf("channel %d = %s, want %s", i, channel.Channel, model.DeliveryChannelOrder[i])
+		}
+		if wantEnabled := channel.Channel == model.ChannelTelegram; channel.Enabled != wantEnabled {
+			t.Errorf("%s enabled = %v, want %v", channel.Channel, channel.Enabled, wantEnabled)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"be-project-monitoring/internal/api"
 	"be-project-monitoring/internal/domain/model"
@@ -68,6 +69,13 @@ func (s *service) AddParticipant(ctx context.Context, isOwnerCreation bool, part
 		return nil, err
 	}
 
+	if project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().
+		ByID(participant.ProjectID)); err == nil {
+		s.notify(ctx, model.EventParticipantAdded, project.ID, 0,
+			fmt.Sprintf("Вас добавили в проект «%s»", project.Name),
+			participant.ShortUser.ID)
+	}
+
 	return s.GetParticipantByID(ctx, participant.ID)
 }
 
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// OpenPeerRound starts a round in which the team members rate each other
+func (s *service) OpenPeerRound(ctx context.Context, roundReq *api.PeerRoundReq) (*model.PeerRound, error) {
+	round := &model.PeerRound{
+		ProjectID: roundReq.ProjectID,
+		Name:      strings.TrimSpace(roundReq.Name),
+		MaxScore:  roundReq.MaxScore,
+		OpenedBy:  uuid.NullUUID{UUID: roundReq.UserID, Valid: true},
+		OpenedAt:  time.Now(),
+	}
+	if round.MaxScore == 0 {
+		round.MaxScore = model.DefaultPeerMaxScore
+	}
+	if round.Name == "" || round.MaxScore < 2 || round.MaxScore > 100 || len(roundReq.Criteria) == 0 {
+		return nil, ierr.ErrPeerRoundIsInvalid
+	}
+
+	unique := make(map[string]struct{}, len(roundReq.Criteria))
+	for _, criterion := range roundReq.Criteria {
+		criterion = strings.TrimSpace(criterion)
+		if _, ok := unique[strings.ToLower(criterion)]; ok || criterion == "" {
+			return nil, ierr.ErrPeerRoundIsInvalid
+		}
+		unique[strings.ToLower(criterion)] = struct{}{}
+		round.Criteria = append(round.Criteria, criterion)
+	}
+
+	if roundReq.ClosesAt != nil {
+		if !roundReq.ClosesAt.After(round.OpenedAt) {
+			return nil, ierr.ErrPeerRoundIsInvalid
+		}
+		round.ClosesAt.Scan(*roundReq.ClosesAt)
+	}
+
+	if err := s.repo.InsertPeerRound(ctx, round); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, model.EventPeerRoundOpened, round.ProjectID, 0,
+		fmt.Sprintf("Открыта взаимная оценка «%s», оцените участников команды", round.Name),
+		s.projectUserIDs(ctx, round.ProjectID, model.RoleTeamlead, model.RoleParticipant)...)
+	return round, nil
+}
+
+func (s *service) GetPeerRounds(ctx context.Context, projectID int) ([]model.PeerRound, error) {
+	return s.repo.GetPeerRounds(ctx, repository.NewPeerRoundFilter().
+		ByProjectID(projectID).WithPaginator(db.MaxLimit, 0))
+}
+
+func (s *service) ClosePeerRound(ctx context.Context, projectID, roundID int) (*model.PeerRound, error) {
+	round, err := s.repo.GetPeerRound(ctx, repository.NewPeerRoundFilter().ByID(roundID).ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+	if round.ClosedAt.Valid {
+		return round, nil
+	}
+
+	round.ClosedAt.Scan(time.Now())
+	return round, s.repo.ClosePeerRound(ctx, round)
+}
+
+// GetPeerAssignment returns the teammates the user has to rate and the reviews already submitted
+func (s *service) GetPeerAssignment(ctx context.Context, userID uuid.UUID, projectID, roundID int) (*model.PeerAssignment, error) {
+	round, err := s.repo.GetPeerRound(ctx, repository.NewPeerRoundFilter().ByID(roundID).ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	reviewer, err := s.getPeerReviewer(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getPeerAssignment(ctx, round, reviewer)
+}
+
+// SubmitPeerReviews saves the ratings of the teammates, they can be changed while the round is open
+func (s *service) SubmitPeerReviews(ctx context.Context, reviewsReq *api.SubmitPeerReviewsReq) (*model.PeerAssignment, error) {
+	round, err := s.repo.GetPeerRound(ctx, repository.NewPeerRoundFilter().
+		ByID(reviewsReq.RoundID).ByProjectID(reviewsReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !round.IsOpen(now) {
+		return nil, ierr.ErrPeerRoundIsClosed
+	}
+
+	reviewer, err := s.getPeerReviewer(ctx, reviewsReq.UserID, reviewsReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.gradedParticipants(ctx, reviewsReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	teammates := make(map[int]struct{}, len(team))
+	for _, participant := range team {
+		if participant.ID != reviewer.ID {
+			teammates[participant.ID] = struct{}{}
+		}
+	}
+
+	reviews := make([]model.PeerReview, 0, len(reviewsReq.Reviews))
+	reviewed := make(map[int]struct{}, len(reviewsReq.Reviews))
+	for _, reviewReq := range reviewsReq.Reviews {
+		_, isTeammate := teammates[reviewReq.RevieweeID]
+		_, isReviewed := reviewed[reviewReq.RevieweeID]
+		if !isTeammate || isReviewed || len(reviewReq.Scores) != len(round.Criteria) {
+			return nil, ierr.ErrPeerReviewIsInvalid
+		}
+		for _, score := range reviewReq.Scores {
+			if score < 1 || score > int64(round.MaxScore) {
+				return nil, ierr.ErrPeerReviewIsInvalid
+			}
+		}
+		reviewed[reviewReq.RevieweeID] = struct{}{}
+
+		review := model.PeerReview{
+			RoundID:     round.ID,
+			ReviewerID:  reviewer.ID,
+			RevieweeID:  reviewReq.RevieweeID,
+			Scores:      reviewReq.Scores,
+			SubmittedAt: now,
+		}
+		if comment := strings.TrimSpace(reviewReq.Comment); comment != "" {
+			review.Comment.Scan(comment)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = s.repo.UpsertPeerReviews(ctx, reviews); err != nil {
+		return nil, err
+	}
+	return s.getPeerAssignment(ctx, round, reviewer)
+}
+
+// GetPeerRoundResults returns who has filled the round and the aggregated anonymous ratings
+func (s *service) GetPeerRoundResults(ctx context.Context, projectID, roundID int) (*model.PeerRoundResults, error) {
+	round, err := s.repo.GetPeerRound(ctx, repository.NewPeerRoundFilter().ByID(roundID).ByProjectID(projectID))
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.gradedParticipants(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.repo.GetPeerReviews(ctx, repository.NewPeerReviewFilter().ByRoundID(round.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	results := &model.PeerRoundResults{
+		Round:      *round,
+		Completion: make([]model.PeerCompletion, 0, len(team)),
+		Results:    make([]model.PeerResult, 0, len(team)),
+	}
+	for _, participant := range team {
+		completion := model.PeerCompletion{Participant: participant, Expected: len(team) - 1}
+		received := make([]model.PeerReview, 0)
+		for _, review := range reviews {
+			if review.ReviewerID == participant.ID && isTeamMember(team, review.RevieweeID) {
+				completion.Done++
+			}
+			if review.RevieweeID == participant.ID && isTeamMember(team, review.ReviewerID) {
+				received = append(received, review)
+			}
+		}
+		results.Completion = append(results.Completion, completion)
+		results.Results = append(results.Results, aggregatePeerReviews(*round, participant, received))
+	}
+	return results, nil
+}
+
+// peerScores returns the mean peer rating of the participants in percents of the max score,
+// taken from the latest finished round of the project. The map is empty if there is no such round.
+func (s *service) peerScores(ctx context.Context, projectID int) (map[int]float64, error) {
+	rounds, err := s.GetPeerRounds(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int]float64)
+	now := time.Now()
+	for _, round := range rounds {
+		if round.IsOpen(now) {
+			continue
+		}
+
+		results, err := s.GetPeerRoundResults(ctx, projectID, round.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results.Results {
+			if result.Reviews != 0 {
+				res[result.Participant.ID] = result.Mean * 100 / float64(round.MaxScore)
+			}
+		}
+		break
+	}
+	return res, nil
+}
+
+func (s *service) getPeerReviewer(ctx context.Context, userID uuid.UUID, projectID int) (*model.Participant, error) {
+	reviewer, err := s.VerifyParticipant(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer.Role == model.RoleOwner {
+		return nil, ierr.ErrAccessDeniedWrongParticipantRole
+	}
+	return reviewer, nil
+}
+
+func (s *service) getPeerAssignment(ctx context.Context, round *model.PeerRound, reviewer *model.Participant) (*model.PeerAssignment, error) {
+	team, err := s.gradedParticipants(ctx, round.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.repo.GetPeerReviews(ctx, repository.NewPeerReviewFilter().
+		ByRoundID(round.ID).ByReviewerID(reviewer.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &model.PeerAssignment{
+		Round:     *round,
+		Teammates: make([]model.Participant, 0, len(team)),
+		Reviews:   reviews,
+	}
+	for _, participant := range team {
+		if participant.ID != reviewer.ID {
+			assignment.Teammates = append(assignment.Teammates, participant)
+		}
+	}
+	return assignment, nil
+}
+
+// aggregatePeerReviews averages the reviews of the participant. A review is an outlier if its
+// mean deviates from the median of all the reviews by more than PeerOutlierShare of the scale,
+// the outliers are left out of Means and Mean.
+func aggregatePeerReviews(round model.PeerRound, participant model.Participant, reviews []model.PeerReview) model.PeerResult {
+	result := model.PeerResult{
+		Participant: participant,
+		Reviews:     len(reviews),
+		Means:       make([]float64, len(round.Criteria)),
+		Comments:    make([]string, 0),
+	}
+	if len(reviews) == 0 {
+		return result
+	}
+
+	means := make([]float64, len(reviews))
+	var total float64
+	for i, review := range reviews {
+		for _, score := range review.Scores {
+			means[i] += float64(score)
+		}
+		means[i] /= float64(len(review.Scores))
+		total += means[i]
+
+		if review.Comment.Valid {
+			result.Comments = append(result.Comments, review.Comment.String)
+		}
+	}
+	result.RawMean = roundScore(total / float64(len(reviews)))
+	// Comments are sorted so their order does not give the reviewers away
+	sort.Strings(result.Comments)
+
+	median := medianScore(means)
+	kept := make([]model.PeerReview, 0, len(reviews))
+	for i, review := range reviews {
+		if len(reviews) >= model.PeerOutlierMinReviews &&
+			math.Abs(means[i]-median) > model.PeerOutlierShare*float64(round.MaxScore-1) {
+			result.Outliers++
+			continue
+		}
+		kept = append(kept, review)
+	}
+	if len(kept) == 0 {
+		kept = reviews
+	}
+
+	var mean float64
+	for i := range result.Means {
+		for _, review := range kept {
+			if i < len(review.Scores) {
+				result.Means[i] += float64(review.Scores[i])
+			}
+		}
+		result.Means[i] /= float64(len(kept))
+		mean += result.Means[i]
+		result.Means[i] = roundScore(result.Means[i])
+	}
+	result.Mean = roundScore(mean / float64(len(result.Means)))
+	return result
+}
+
+func isTeamMember(team []model.Participant, participantID int) bool {
+	for _, participant := range team {
+		if participant.ID == participantID {
+			return true
+		}
+	}
+	return false
+}
+
+func medianScore(scores []float64) float64 {
+	sorted := append([]float64{}, scores...)
+	sort.Float64s(sorted)
+	if n := len(sorted); n%2 == 0 {
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return sorted[len(sorted)/2]
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"strings"
 	"time"
@@ -10,6 +11,8 @@ import (
 	"be-project-monitoring/internal/domain/model"
 	ierr "be-project-monitoring/internal/errors"
 	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
 )
 
 func (s *service) GetProjects(ctx context.Context, projectReq *api.GetProjectsReq) ([]model.Project, int, error) {
@@ -59,6 +62,9 @@ func (s *service) CreateProject(ctx context.Context, projectReq *api.CreateProje
 	if strings.TrimSpace(projectReq.PhotoURL) != "" {
 		project.PhotoURL.Scan(projectReq.PhotoURL)
 	}
+	if strings.TrimSpace(projectReq.Course) != "" {
+		project.Course.Scan(strings.TrimSpace(projectReq.Course))
+	}
 
 	return project, s.repo.InsertProject(ctx, project)
 }
@@ -76,6 +82,12 @@ func (s *service) UpdateProject(ctx context.Context, projectReq *api.UpdateProje
 		return nil, err
 	}
 
+	// the repo of the project is its main repository, see setMainRepository
+	if newProject.RepoURL != oldProject.RepoURL {
+		if err = s.setMainRepository(ctx, newProject.ID, newProject.RepoURL.String); err != nil {
+			return nil, err
+		}
+	}
 	return newProject, s.repo.UpdateProject(ctx, newProject)
 }
 
@@ -84,8 +96,7 @@ func (s *service) DeleteProject(ctx context.Context, id int) error {
 }
 func (s *service) GetProjectCommits(ctx context.Context, id int) ([]model.CommitsInfo, error) {
 
-	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(id))
-	if err != nil {
+	if _, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(id)); err != nil {
 		return nil, err
 	}
 
@@ -94,61 +105,102 @@ func (s *service) GetProjectCommits(ctx context.Context, id int) ([]model.Commit
 		return nil, err
 	}
 
-	usersCommitsInfo := make(map[string]model.CommitsInfo, len(users))
+	usersCommitsInfo := make(map[uuid.UUID]model.CommitsInfo, len(users))
 	for _, user := range users {
-		usersCommitsInfo[user.GithubUsername] = model.CommitsInfo{ShortUser: user}
+		usersCommitsInfo[user.ID] = model.CommitsInfo{ShortUser: user}
 	}
 
-	tasks, err := s.GetCompletedTasksCountByGHUsername(ctx, id)
+	tasks, err := s.GetCompletedTasksCountByUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, task := range tasks {
-		info := usersCommitsInfo[task.GithubUsername]
+		info, ok := usersCommitsInfo[task.UserID]
+		if !ok {
+			continue
+		}
 		info.TotalTasksDone = task.TotalDone
 		info.TotalTasksEstimate = task.TotalEstimate
-		usersCommitsInfo[task.GithubUsername] = info
+		usersCommitsInfo[task.UserID] = info
+	}
+
+	worklogs, err := s.repo.GetWorklogTotalsByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, worklog := range worklogs {
+		info, ok := usersCommitsInfo[worklog.UserID]
+		if !ok {
+			continue
+		}
+		info.TotalTasksActual = worklog.TotalMinutes
+		usersCommitsInfo[worklog.UserID] = info
 	}
 
-	if !project.RepoURL.Valid {
-		return nil, ierr.ErrRepositoryURLIsEmpty
+	repositories, err := s.GetProjectRepositories(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	logins, err := s.repositoryUserIDs(ctx, repositories, users)
+	if err != nil {
+		return nil, err
 	}
 
-	repoURL := strings.Split(project.RepoURL.String, "/") //https://github.com/Exresist/be-project-monitoring
-	if len(repoURL) != 5 {
-		return nil, ierr.ErrRepositoryURLWrongFormat
+	index := make(map[int]int, len(repositories))
+	for i, projectRepository := range repositories {
+		index[projectRepository.ID] = i
+	}
+	for userID, info := range usersCommitsInfo {
+		info.Repositories = make([]model.RepositoryCommits, 0, len(repositories))
+		for _, projectRepository := range repositories {
+			info.Repositories = append(info.Repositories, model.RepositoryCommits{
+				RepositoryID: projectRepository.ID,
+				URL:          projectRepository.URL,
+				Role:         projectRepository.Role,
+			})
+		}
+		usersCommitsInfo[userID] = info
 	}
-	// owner := repoURL[3]
-	// repoName := repoURL[4]
 
-	stats, _, err := s.githubCl.Repositories.ListContributorsStats(ctx, repoURL[3], repoURL[4])
+	// the statistics are synced from the repositories in the background, see SyncGithubStats
+	stats, err := s.repo.GetContributorStats(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, stat := range stats {
-		ghUsername := stat.Author.GetLogin()
-		if info, ok := usersCommitsInfo[ghUsername]; ok {
-			info.TotalCommits = stat.GetTotal()
-			for _, week := range stat.Weeks {
-				info.NumberOfAdditions += week.GetAdditions()
-				info.NumberOfDeletions += week.GetDeletions()
-			}
-			usersCommitsInfo[ghUsername] = info
+		userID, ok := logins[stat.RepositoryID][strings.ToLower(stat.GithubUsername)]
+		if !ok {
+			continue
 		}
+		// the commits of an author may be counted by the login and by the email
+		info := usersCommitsInfo[userID]
+		info.TotalCommits += stat.Commits
+		info.NumberOfAdditions += stat.Additions
+		info.NumberOfDeletions += stat.Deletions
+		if i, ok := index[stat.RepositoryID]; ok {
+			info.Repositories[i].Commits += stat.Commits
+			info.Repositories[i].Additions += stat.Additions
+			info.Repositories[i].Deletions += stat.Deletions
+		}
+		usersCommitsInfo[userID] = info
 	}
 
+	return commitsInfoList(usersCommitsInfo), nil
+}
+
+func commitsInfoList(usersCommitsInfo map[uuid.UUID]model.CommitsInfo) []model.CommitsInfo {
 	res := make([]model.CommitsInfo, 0, len(usersCommitsInfo))
 	for _, commitInfo := range usersCommitsInfo {
 		res = append(res, commitInfo)
 	}
-
-	return res, nil
+	return res
 }
 
-func (s *service) GetCompletedTasksCountByGHUsername(ctx context.Context, projectID int) ([]model.TaskCount, error) {
-	return s.repo.GetCompletedTasksCountByGHUsername(ctx, projectID)
+func (s *service) GetCompletedTasksCountByUser(ctx context.Context, projectID int) ([]model.TaskCount, error) {
+	return s.repo.GetCompletedTasksCountByUser(ctx, projectID)
 }
 
 func (s *service) GetProjectInfo(ctx context.Context, id int) (*model.ProjectInfo, error) {
@@ -168,7 +220,12 @@ func (s *service) GetProjectInfo(ctx context.Context, id int) (*model.ProjectInf
 		return nil, err
 	}
 
-	checklist, err := s.repo.GetProjectChecklist(ctx, id)
+	checklists, err := s.GetChecklists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	repositories, err := s.GetProjectRepositories(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -177,7 +234,8 @@ func (s *service) GetProjectInfo(ctx context.Context, id int) (*model.ProjectInf
 		Project:      *project,
 		Participants: participants,
 		Tasks:        tasks,
-		Checklist:    checklist,
+		Checklists:   checklists,
+		Repositories: repositories,
 	}
 	return projectInfo, nil
 }
@@ -219,22 +277,28 @@ func mergeProjectFields(oldProject *model.Project, projectReq *api.UpdateProject
 		newProject.PhotoURL.Scan(*projectReq.PhotoURL)
 	}
 
-	if projectReq.ReportURL == nil {
-		newProject.ReportURL = oldProject.ReportURL
+	// the report is changed only by the submissions, see SubmitProjectReport
+	newProject.ReportURL = oldProject.ReportURL
+	newProject.ReportName = oldProject.ReportName
+
+	if projectReq.RepoURL == nil {
+		newProject.RepoURL = oldProject.RepoURL
 	} else {
-		newProject.ReportURL.Scan(*projectReq.ReportURL)
+		newProject.RepoURL.Scan(*projectReq.RepoURL)
 	}
 
-	if projectReq.ReportName == nil {
-		newProject.ReportName = oldProject.ReportName
+	if projectReq.AutoTransitionTasks == nil {
+		newProject.AutoTransitionTasks = oldProject.AutoTransitionTasks
 	} else {
-		newProject.ReportName.Scan(*projectReq.ReportName)
+		newProject.AutoTransitionTasks = *projectReq.AutoTransitionTasks
 	}
 
-	if projectReq.RepoURL == nil {
-		newProject.RepoURL = oldProject.RepoURL
+	if projectReq.Course == nil {
+		newProject.Course = oldProject.Course
+	} else if strings.TrimSpace(*projectReq.Course) == "" {
+		newProject.Course = sql.NullString{}
 	} else {
-		newProject.RepoURL.Scan(*projectReq.RepoURL)
+		newProject.Course.Scan(strings.TrimSpace(*projectReq.Course))
 	}
 
 	return newProject, nil
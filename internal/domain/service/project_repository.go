@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
+)
+
+// GetProjectRepositories returns the repositories of the project, the first one is the main repository
+func (s *service) GetProjectRepositories(ctx context.Context, projectID int) ([]model.ProjectRepository, error) {
+	return s.repo.GetProjectRepositories(ctx, repository.NewProjectRepositoryFilter().ByProjectID(projectID))
+}
+
+func (s *service) AddProjectRepository(ctx context.Context, repositoryReq *api.ProjectRepositoryReq) (*model.ProjectRepository, error) {
+	if _, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(repositoryReq.ProjectID)); err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSpace(repositoryReq.URL)
+	provider, err := s.checkRepositoryURL(ctx, repositoryReq.ProjectID, 0, url)
+	if err != nil {
+		return nil, err
+	}
+
+	projectRepository := &model.ProjectRepository{
+		ProjectID:     repositoryReq.ProjectID,
+		URL:           url,
+		Provider:      provider,
+		DefaultBranch: strings.TrimSpace(repositoryReq.DefaultBranch),
+		Role:          strings.TrimSpace(repositoryReq.Role),
+		CreatedAt:     time.Now(),
+	}
+	return projectRepository, s.repo.InsertProjectRepository(ctx, projectRepository)
+}
+
+// UpdateProjectRepository changes the repository, the statistics of the old url are dropped
+// and the new one is synced as soon as possible
+func (s *service) UpdateProjectRepository(ctx context.Context, repositoryReq *api.ProjectRepositoryReq) (*model.ProjectRepository, error) {
+	projectRepository, err := s.repo.GetProjectRepository(ctx, repository.NewProjectRepositoryFilter().
+		ByID(repositoryReq.RepositoryID).ByProjectID(repositoryReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSpace(repositoryReq.URL)
+	provider, err := s.checkRepositoryURL(ctx, projectRepository.ProjectID, projectRepository.ID, url)
+	if err != nil {
+		return nil, err
+	}
+
+	urlChanged := url != projectRepository.URL
+	projectRepository.URL = url
+	projectRepository.Provider = provider
+	projectRepository.DefaultBranch = strings.TrimSpace(repositoryReq.DefaultBranch)
+	projectRepository.Role = strings.TrimSpace(repositoryReq.Role)
+	if err = s.repo.UpdateProjectRepository(ctx, projectRepository); err != nil {
+		return nil, err
+	}
+	if urlChanged {
+		return projectRepository, s.repo.ResetGithubSync(ctx, projectRepository, time.Now())
+	}
+	return projectRepository, nil
+}
+
+func (s *service) DeleteProjectRepository(ctx context.Context, projectID, repositoryID int) error {
+	projectRepository, err := s.repo.GetProjectRepository(ctx, repository.NewProjectRepositoryFilter().
+		ByID(repositoryID).ByProjectID(projectID))
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteProjectRepository(ctx, projectRepository)
+}
+
+// setMainRepository makes url the url of the main repository of the project,
+// the main repository is added if the project has none and deleted if url is empty
+func (s *service) setMainRepository(ctx context.Context, projectID int, url string) error {
+	repositories, err := s.GetProjectRepositories(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	url = strings.TrimSpace(url)
+	if url == "" {
+		if len(repositories) == 0 {
+			return nil
+		}
+		return s.repo.DeleteProjectRepository(ctx, &repositories[0])
+	}
+
+	if len(repositories) == 0 {
+		provider, err := s.checkRepositoryURL(ctx, projectID, 0, url)
+		if err != nil {
+			return err
+		}
+		return s.repo.InsertProjectRepository(ctx, &model.ProjectRepository{
+			ProjectID: projectID,
+			URL:       url,
+			Provider:  provider,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	mainRepository := &repositories[0]
+	if mainRepository.URL == url {
+		return nil
+	}
+	if mainRepository.Provider, err = s.checkRepositoryURL(ctx, projectID, mainRepository.ID, url); err != nil {
+		return err
+	}
+	mainRepository.URL = url
+	if err = s.repo.UpdateProjectRepository(ctx, mainRepository); err != nil {
+		return err
+	}
+	return s.repo.ResetGithubSync(ctx, mainRepository, time.Now())
+}
+
+// checkRepositoryURL checks that the url points to a repository at a configured provider
+// and is not added to the project yet, it returns the host of the provider
+func (s *service) checkRepositoryURL(ctx context.Context, projectID, repositoryID int, url string) (string, error) {
+	_, repo, err := s.repositoryVCS(url)
+	if err != nil {
+		return "", err
+	}
+
+	repositories, err := s.GetProjectRepositories(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	for _, projectRepository := range repositories {
+		if projectRepository.ID != repositoryID && strings.EqualFold(projectRepository.URL, url) {
+			return "", ierr.ErrProjectRepositoryAlreadyExists
+		}
+	}
+	return repo.Host, nil
+}
+
+// repositoryVCS returns the provider of the repository and the repository itself
+func (s *service) repositoryVCS(url string) (vcs.VCSProvider, vcs.Repo, error) {
+	if strings.TrimSpace(url) == "" {
+		return nil, vcs.Repo{}, ierr.ErrRepositoryURLIsEmpty
+	}
+
+	repo, ok := s.providers.ParseRepoURL(url) //https://github.com/Exresist/be-project-monitoring
+	if !ok {
+		return nil, vcs.Repo{}, ierr.ErrRepositoryURLWrongFormat
+	}
+	provider, ok := s.providers[repo.Host]
+	if !ok {
+		return nil, vcs.Repo{}, ierr.ErrRepositoryHostNotSupported
+	}
+	return provider, repo, nil
+}
+
+// commitProvider returns the provider which reads the commits of the repository:
+// the local git analysis if it is configured, the provider of the repository otherwise
+func (s *service) commitProvider(provider vcs.VCSProvider) vcs.VCSProvider {
+	if local, ok := s.providers[vcs.LocalHost]; ok {
+		return local
+	}
+	return provider
+}
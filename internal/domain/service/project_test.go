@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/vcs"
+
+	"github.com/google/uuid"
+)
+
+func TestGetProjectCommits(t *testing.T) {
+	var (
+		week  = time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC)
+		alice = model.ShortUser{ID: uuid.New(), GithubUsername: "Alice", Email: "alice@example.com"}
+		// bob and cid have no github accounts, their metrics must not be merged
+		bob    = model.ShortUser{ID: uuid.New(), Email: "bob@example.com"}
+		cid    = model.ShortUser{ID: uuid.New()}
+		github = vcs.NewFakeProvider()
+		gitlab = vcs.NewFakeProvider()
+	)
+	github.SetStats(vcs.Repo{Host: vcs.GithubHost, Owner: "team", Name: "backend"},
+		model.ContributorWeek{GithubUsername: "alice", WeekStart: week, Commits: 3, Additions: 30, Deletions: 3},
+		// the commits of the authors without a login are counted by the email
+		model.ContributorWeek{GithubUsername: "bob@example.com", WeekStart: week, Commits: 1, Additions: 10},
+		model.ContributorWeek{GithubUsername: "stranger", WeekStart: week, Commits: 9, Additions: 90},
+	)
+	gitlab.SetStats(vcs.Repo{Host: "gitlab.example.com", Owner: "team", Name: "frontend"},
+		model.ContributorWeek{GithubUsername: "bobby", WeekStart: week, Commits: 2, Additions: 20, Deletions: 2},
+		model.ContributorWeek{GithubUsername: "cid", WeekStart: week, Commits: 4, Additions: 40, Deletions: 4},
+	)
+	repo := &stubRepository{
+		project: model.Project{ShortProject: model.ShortProject{ID: 1}},
+		users:   []model.ShortUser{alice, bob, cid},
+		repositories: []model.ProjectRepository{
+			{ID: 10, ProjectID: 1, URL: "https://github.com/team/backend"},
+			{ID: 20, ProjectID: 1, URL: "https://gitlab.example.com/team/frontend"},
+		},
+		vcsUsernames: map[string]map[uuid.UUID]string{
+			"gitlab.example.com": {bob.ID: "Bobby", cid.ID: "cid"},
+		},
+		taskCounts: []model.TaskCount{
+			{UserID: cid.ID, TotalDone: 2, TotalEstimate: 5},
+			{UserID: uuid.New(), TotalDone: 7},
+		},
+		worklogs: []model.WorklogCount{{UserID: bob.ID, TotalMinutes: 90}},
+	}
+	s := newTestService(t, repo, vcs.Providers{vcs.GithubHost: github, "gitlab.example.com": gitlab})
+	ctx := context.Background()
+
+	syncs, err := s.ResyncGithubStats(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sync := range syncs {
+		if sync.Status != model.SyncOK {
+			t.Errorf("sync of repository %d = %+v", sync.RepositoryID, sync)
+		}
+	}
+
+	infos, err := s.GetProjectCommits(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("GetProjectCommits() = %+v, want a row for every participant", infos)
+	}
+	byUser := make(map[uuid.UUID]model.CommitsInfo, len(infos))
+	for _, info := range infos {
+		byUser[info.ID] = info
+	}
+
+	tests := []struct {
+		name       string
+		user       model.ShortUser
+		commits    int
+		additions  int
+		tasks      int
+		minutes    int
+		repository [2]int
+	}{
+		{"alice", alice, 3, 30, 0, 0, [2]int{3, 0}},
+		{"bob", bob, 3, 30, 0, 90, [2]int{1, 2}},
+		{"cid", cid, 4, 40, 2, 0, [2]int{0, 4}},
+	}
+	for _, tt := range tests {
+		info := byUser[tt.user.ID]
+		if info.TotalCommits != tt.commits || info.NumberOfAdditions != tt.additions ||
+			info.TotalTasksDone != tt.tasks || info.TotalTasksActual != tt.minutes {
+			t.Errorf("%s: GetProjectCommits() = %+v", tt.name, info)
+		}
+		if len(info.Repositories) != 2 || info.Repositories[0].Commits != tt.repository[0] ||
+			info.Repositories[1].Commits != tt.repository[1] {
+			t.Errorf("%s: repositories = %+v, want commits %v", tt.name, info.Repositories, tt.repository)
+		}
+	}
+}
+
+func TestResyncGithubStatsFailsUnsupportedHost(t *testing.T) {
+	repo := &stubRepository{
+		project:      model.Project{ShortProject: model.ShortProject{ID: 1}},
+		repositories: []model.ProjectRepository{{ID: 10, ProjectID: 1, URL: "https://bitbucket.org/team/backend"}},
+	}
+	s := newTestService(t, repo, vcs.Providers{vcs.GithubHost: vcs.NewFakeProvider()})
+
+	before := time.Now()
+	if _, err := s.ResyncGithubStats(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	// the repository is retried after the back-off instead of on every run
+	sync := repo.syncs[10]
+	if sync.Status != model.SyncFailed || sync.Attempts != 1 || !sync.Error.Valid ||
+		sync.NextSyncAt.Before(before.Add(githubRetryBase)) {
+		t.Errorf("sync = %+v", sync)
+	}
+}
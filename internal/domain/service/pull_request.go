@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// GetPullRequestMetrics counts the pull requests and the reviews of every participant of the project.
+// The pull requests are synced from the repositories in the background with the statistics, see SyncGithubStats.
+// The reviews of the own pull requests are not counted.
+func (s *service) GetPullRequestMetrics(ctx context.Context, projectID int) ([]model.PullRequestMetrics, error) {
+	if _, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(projectID)); err != nil {
+		return nil, err
+	}
+
+	users, err := s.repo.GetPartialUsers(ctx, repository.NewUserFilter().ByAtProject(projectID))
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := s.GetProjectRepositories(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	logins, err := s.repositoryUserIDs(ctx, repositories, users)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := s.repo.GetPullRequests(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.repo.GetPullRequestReviews(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uuid.UUID]int, len(users))
+	metrics := make([]model.PullRequestMetrics, 0, len(users))
+	for _, user := range users {
+		index[user.ID] = len(metrics)
+		metrics = append(metrics, model.PullRequestMetrics{ShortUser: user})
+	}
+
+	type prKey struct {
+		repositoryID int
+		number       int
+	}
+	authors := make(map[prKey]string, len(prs))
+	mergeTimes := make(map[int][]time.Duration)
+	for _, pr := range prs {
+		authors[prKey{pr.RepositoryID, pr.Number}] = strings.ToLower(pr.AuthorLogin)
+		userID, ok := logins[pr.RepositoryID][strings.ToLower(pr.AuthorLogin)]
+		if !ok {
+			continue
+		}
+		i := index[userID]
+		metrics[i].Opened++
+		if pr.MergedAt.Valid {
+			metrics[i].Merged++
+			mergeTimes[i] = append(mergeTimes[i], pr.MergedAt.Time.Sub(pr.CreatedAt))
+		}
+	}
+
+	for _, review := range reviews {
+		login := strings.ToLower(review.AuthorLogin)
+		userID, ok := logins[review.RepositoryID][login]
+		if !ok || authors[prKey{review.RepositoryID, review.Number}] == login {
+			continue
+		}
+		i := index[userID]
+		metrics[i].ReviewsGiven++
+		metrics[i].ReviewComments += review.Comments
+		if review.State == model.ReviewApproved {
+			metrics[i].Approvals++
+		}
+	}
+
+	for i, durations := range mergeTimes {
+		metrics[i].MedianTimeToMerge = medianDuration(durations)
+	}
+	return metrics, nil
+}
+
+func medianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	middle := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[middle-1] + durations[middle]) / 2
+	}
+	return durations[middle]
+}
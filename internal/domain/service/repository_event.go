@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
+
+	"github.com/google/uuid"
+)
+
+// ReceiveGithubWebhook saves the activity of the delivery to every project the repository is added to
+// and maps it to the users of the project. The events of the repositories unknown to the application,
+// the events without activity and the redelivered ones are skipped.
+func (s *service) ReceiveGithubWebhook(ctx context.Context, delivery *model.WebhookDelivery, payload []byte) error {
+	if strings.TrimSpace(delivery.ID) == "" {
+		return ierr.ErrWebhookDeliveryIDIsEmpty
+	}
+
+	webhook, err := vcs.ParseGithubWebhook(delivery.Event, payload)
+	if errors.Is(err, vcs.ErrEventNotSupported) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	repositories, err := s.repo.GetProjectRepositories(ctx, repository.NewProjectRepositoryFilter().
+		ByURLLike(webhook.Repo.Host+"/"+webhook.Repo.FullName()))
+	if err != nil {
+		return err
+	}
+
+	events := make([]model.RepositoryEvent, 0)
+	for _, projectRepository := range repositories {
+		// the url is matched by a substring, so the repository named as a prefix of another one is skipped here
+		repo, ok := s.providers.ParseRepoURL(projectRepository.URL)
+		if !ok || !strings.EqualFold(repo.Host, webhook.Repo.Host) ||
+			!strings.EqualFold(repo.FullName(), webhook.Repo.FullName()) {
+			continue
+		}
+
+		users, err := s.repo.GetPartialUsers(ctx, repository.NewUserFilter().ByAtProject(projectRepository.ProjectID))
+		if err != nil {
+			return err
+		}
+		userIDs, err := s.loginUserIDs(ctx, repo.Host, users)
+		if err != nil {
+			return err
+		}
+		for _, event := range webhook.Events {
+			event.RepositoryID = projectRepository.ID
+			event.ProjectID = projectRepository.ProjectID
+			if event.OccurredAt.IsZero() {
+				event.OccurredAt = delivery.ReceivedAt
+			}
+			if userID, ok := userIDs[strings.ToLower(event.GithubUsername)]; ok {
+				event.UserID = uuid.NullUUID{UUID: userID, Valid: true}
+			}
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	err = s.repo.InsertRepositoryEvents(ctx, delivery, events)
+	if errors.Is(err, ierr.ErrWebhookDeliveryAlreadyReceived) {
+		s.logger.Infof("skipped github webhook delivery %v received before", delivery.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the delivery is saved already and would be skipped if redelivered, so the links are not retried
+	if err = s.linkTasks(ctx, events); err != nil {
+		s.logger.Errorf("failed to link tasks to github webhook delivery %v: %v", delivery.ID, err)
+	}
+	return nil
+}
+
+// GetRepositoryEvents returns the activity in the repositories of the project from the latest event
+func (s *service) GetRepositoryEvents(ctx context.Context, eventsReq *api.GetRepositoryEventsReq) ([]model.RepositoryEvent, error) {
+	filter := repository.NewRepositoryEventFilter().
+		WithPaginator(uint64(eventsReq.Limit), uint64(eventsReq.Offset)).
+		ByProjectID(eventsReq.ProjectID).
+		ByKind(eventsReq.Kind)
+	if eventsReq.UserID != uuid.Nil {
+		filter.ByUserID(eventsReq.UserID)
+	}
+	return s.repo.GetRepositoryEvents(ctx, filter)
+}
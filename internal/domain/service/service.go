@@ -2,18 +2,25 @@ package service
 
 import (
 	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/storage"
+	"be-project-monitoring/internal/vcs"
 
-	"github.com/google/go-github/v49/github"
+	"go.uber.org/zap"
 )
 
 type service struct {
-	repo     domain.Repository
-	githubCl *github.Client
+	repo domain.Repository
+	// providers are selected by the host of the project repository, the users are looked up on github
+	providers vcs.Providers
+	files     storage.Storage
+	logger    *zap.SugaredLogger
 }
 
-func NewService(store domain.Repository, githubCl *github.Client) *service {
+func NewService(store domain.Repository, providers vcs.Providers, files storage.Storage, logger *zap.SugaredLogger) *service {
 	return &service{
-		repo:     store,
-		githubCl: githubCl,
+		repo:      store,
+		providers: providers,
+		files:     files,
+		logger:    logger,
 	}
 }
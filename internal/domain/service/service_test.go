@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// stubRepository keeps the data of one project the tests read and write,
+// the methods which are not overridden panic
+type stubRepository struct {
+	domain.Repository
+
+	project      model.Project
+	users        []model.ShortUser
+	repositories []model.ProjectRepository
+	vcsUsernames map[string]map[uuid.UUID]string
+	taskCounts   []model.TaskCount
+	worklogs     []model.WorklogCount
+	syncs        map[int]model.GithubSync
+	weeks        map[int][]model.ContributorWeek
+	pullRequests map[int][]model.PullRequest
+	participants []model.Participant
+	tasks        []model.Task
+	issueSync    *model.IssueSync
+	taskIssues   []model.TaskIssue
+	// taskWrites counts the inserted and the updated tasks, issueSyncRuns keeps the saved runs
+	taskWrites    int
+	issueSyncRuns []issueSyncRunRecord
+}
+
+type issueSyncRunRecord struct {
+	sync    model.IssueSync
+	links   []model.TaskIssue
+	entries []model.IssueSyncEntry
+}
+
+func (r *stubRepository) GetProject(_ context.Context, filter *repository.ProjectFilter) (*model.Project, error) {
+	if filter.ID != r.project.ID {
+		return nil, ierr.ErrProjectNotFound
+	}
+	project := r.project
+	return &project, nil
+}
+
+func (r *stubRepository) GetPartialUsers(context.Context, *repository.UserFilter) ([]model.ShortUser, error) {
+	return append([]model.ShortUser{}, r.users...), nil
+}
+
+func (r *stubRepository) GetProjectRepositories(_ context.Context,
+	filter *repository.ProjectRepositoryFilter) ([]model.ProjectRepository, error) {
+	res := make([]model.ProjectRepository, 0)
+	for _, projectRepository := range r.repositories {
+		if projectRepository.ProjectID == filter.ProjectID {
+			res = append(res, projectRepository)
+		}
+	}
+	return res, nil
+}
+
+func (r *stubRepository) GetProjectRepository(_ context.Context,
+	filter *repository.ProjectRepositoryFilter) (*model.ProjectRepository, error) {
+	for _, projectRepository := range r.repositories {
+		if projectRepository.ID == filter.ID {
+			return &projectRepository, nil
+		}
+	}
+	return nil, ierr.ErrProjectRepositoryNotFound
+}
+
+func (r *stubRepository) GetVCSUsernames(_ context.Context, host string, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	res := make(map[uuid.UUID]string)
+	for _, id := range userIDs {
+		if username, ok := r.vcsUsernames[host][id]; ok {
+			res[id] = username
+		}
+	}
+	return res, nil
+}
+
+func (r *stubRepository) GetCompletedTasksCountByUser(context.Context, int) ([]model.TaskCount, error) {
+	return r.taskCounts, nil
+}
+
+func (r *stubRepository) GetWorklogTotalsByUser(context.Context, int) ([]model.WorklogCount, error) {
+	return r.worklogs, nil
+}
+
+func (r *stubRepository) GetGithubSyncs(context.Context, int) ([]model.GithubSync, error) {
+	res := make([]model.GithubSync, 0, len(r.syncs))
+	for _, sync := range r.syncs {
+		res = append(res, sync)
+	}
+	return res, nil
+}
+
+func (r *stubRepository) SaveGithubSync(_ context.Context, sync *model.GithubSync) error {
+	if r.syncs == nil {
+		r.syncs = make(map[int]model.GithubSync)
+	}
+	r.syncs[sync.RepositoryID] = *sync
+	return nil
+}
+
+func (r *stubRepository) ReplaceContributorStats(ctx context.Context, weeks []model.ContributorWeek,
+	sync *model.GithubSync) error {
+	if r.weeks == nil {
+		r.weeks = make(map[int][]model.ContributorWeek)
+	}
+	r.weeks[sync.RepositoryID] = weeks
+	return r.SaveGithubSync(ctx, sync)
+}
+
+func (r *stubRepository) SavePullRequests(_ context.Context, sync *model.GithubSync, prs []model.PullRequest,
+	_ []model.Review) error {
+	if r.pullRequests == nil {
+		r.pullRequests = make(map[int][]model.PullRequest)
+	}
+	r.pullRequests[sync.RepositoryID] = prs
+	return nil
+}
+
+// GetContributorStats sums the synced weeks by the repositories and the logins like the sql does
+func (r *stubRepository) GetContributorStats(context.Context, int) ([]model.ContributorStats, error) {
+	res := make([]model.ContributorStats, 0)
+	type contributor struct {
+		repositoryID int
+		login        string
+	}
+	index := make(map[contributor]int)
+	for repositoryID, weeks := range r.weeks {
+		for _, week := range weeks {
+			key := contributor{repositoryID, week.GithubUsername}
+			i, ok := index[key]
+			if !ok {
+				i = len(res)
+				index[key] = i
+				res = append(res, model.ContributorStats{RepositoryID: repositoryID, GithubUsername: week.GithubUsername})
+			}
+			res[i].Commits += week.Commits
+			res[i].Additions += week.Additions
+			res[i].Deletions += week.Deletions
+		}
+	}
+	return res, nil
+}
+
+func (r *stubRepository) GetParticipant(_ context.Context, filter *repository.ParticipantFilter) (*model.Participant, error) {
+	for _, participant := range r.participants {
+		if participant.ID == filter.ID && (filter.ProjectID == 0 || participant.ProjectID == filter.ProjectID) {
+			return &participant, nil
+		}
+	}
+	return nil, ierr.ErrParticipantNotFound
+}
+
+func (r *stubRepository) GetParticipants(_ context.Context, filter *repository.ParticipantFilter) ([]model.Participant, error) {
+	res := make([]model.Participant, 0)
+	for _, participant := range r.participants {
+		if participant.ProjectID == filter.ProjectID {
+			res = append(res, participant)
+		}
+	}
+	return res, nil
+}
+
+func (r *stubRepository) GetTask(_ context.Context, filter *repository.TaskFilter) (*model.Task, error) {
+	for _, task := range r.tasks {
+		if task.ID == filter.ID {
+			return &task, nil
+		}
+	}
+	return nil, ierr.ErrTaskNotFound
+}
+
+func (r *stubRepository) GetTasks(_ context.Context, filter *repository.TaskFilter) ([]model.Task, error) {
+	res := make([]model.Task, 0)
+	for _, task := range r.tasks {
+		if task.ProjectID == filter.ProjectID && (filter.Status == "" || task.Status == filter.Status) {
+			res = append(res, task)
+		}
+	}
+	if filter.Paginator == nil {
+		return res, nil
+	}
+	if filter.Offset >= uint64(len(res)) {
+		return res[:0], nil
+	}
+	res = res[filter.Offset:]
+	if filter.Limit < uint64(len(res)) {
+		res = res[:filter.Limit]
+	}
+	return res, nil
+}
+
+func (r *stubRepository) InsertTask(_ context.Context, task *model.Task) error {
+	r.taskWrites++
+	task.ID = 1
+	for _, existing := range r.tasks {
+		if existing.ID >= task.ID {
+			task.ID = existing.ID + 1
+		}
+	}
+	r.tasks = append(r.tasks, *task)
+	return nil
+}
+
+func (r *stubRepository) UpdateTask(_ context.Context, task *model.Task) error {
+	r.taskWrites++
+	for i := range r.tasks {
+		if r.tasks[i].ID == task.ID {
+			r.tasks[i] = *task
+			return nil
+		}
+	}
+	return ierr.ErrTaskNotFound
+}
+
+func (r *stubRepository) GetTaskWatchers(context.Context, int) ([]model.ShortUser, error) {
+	return nil, nil
+}
+
+// GetMutedUserIDs mutes everyone, the notifications are tested by the notifier
+func (r *stubRepository) GetMutedUserIDs(_ context.Context, _ model.NotificationEvent,
+	userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	return userIDs, nil
+}
+
+func (r *stubRepository) InsertNotifications(context.Context, []model.Notification) error {
+	return nil
+}
+
+func (r *stubRepository) GetIssueSync(_ context.Context, projectID int) (*model.IssueSync, error) {
+	if r.issueSync == nil || r.issueSync.ProjectID != projectID {
+		return nil, ierr.ErrIssueSyncNotFound
+	}
+	sync := *r.issueSync
+	return &sync, nil
+}
+
+func (r *stubRepository) GetTaskIssues(_ context.Context, repositoryID int) ([]model.TaskIssue, error) {
+	res := make([]model.TaskIssue, 0)
+	for _, link := range r.taskIssues {
+		if link.RepositoryID == repositoryID {
+			res = append(res, link)
+		}
+	}
+	return res, nil
+}
+
+func (r *stubRepository) SaveIssueSyncRun(_ context.Context, sync *model.IssueSync, links []model.TaskIssue,
+	entries []model.IssueSyncEntry) error {
+	r.issueSyncRuns = append(r.issueSyncRuns, issueSyncRunRecord{sync: *sync, links: links, entries: entries})
+	return nil
+}
+
+func newTestService(t *testing.T, repo *stubRepository, providers vcs.Providers) *service {
+	t.Helper()
+	return NewService(repo, providers, nil, zap.NewNop().Sugar())
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var statusReportsHeader = []string{"Неделя", "Сделано", "Запланировано", "Блокеры",
+	"Задач выполнено", "Коммитов", "Комментарий руководителя", "Отправлен"}
+
+// GetStatusReportDraft returns what the report of the week is pre-filled with:
+// the tasks moved to DONE during the week and the commits of the team
+func (s *service) GetStatusReportDraft(ctx context.Context, projectID int, week time.Time) (*model.StatusReportDraft, error) {
+	weekStart := model.WeekStart(week)
+	tasks, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().ByProjectID(projectID).
+		ByDoneBetween(weekStart, weekStart.AddDate(0, 0, 7)).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	draft := &model.StatusReportDraft{
+		WeekStart: weekStart,
+		DoneTasks: tasks,
+		Commits:   s.weeklyCommits(ctx, projectID, weekStart),
+	}
+
+	report, err := s.repo.GetStatusReport(ctx, repository.NewStatusReportFilter().
+		ByProjectID(projectID).ByWeekStart(weekStart))
+	switch {
+	case errors.Is(err, ierr.ErrStatusReportNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		draft.Report = report
+	}
+	return draft, nil
+}
+
+// SubmitStatusReport saves the team lead's report of the week, it can be submitted
+// again to correct it
+func (s *service) SubmitStatusReport(ctx context.Context, reportReq *api.StatusReportReq) (*model.StatusReport, error) {
+	now := time.Now()
+	weekStart := model.WeekStart(reportReq.Week)
+	if reportReq.Week.IsZero() {
+		weekStart = model.WeekStart(now)
+	}
+	if weekStart.After(model.WeekStart(now)) {
+		return nil, ierr.ErrStatusReportWeekInFuture
+	}
+
+	report := &model.StatusReport{
+		ProjectID:   reportReq.ProjectID,
+		WeekStart:   weekStart,
+		Done:        strings.TrimSpace(reportReq.Done),
+		Planned:     strings.TrimSpace(reportReq.Planned),
+		Blockers:    strings.TrimSpace(reportReq.Blockers),
+		SubmittedBy: uuid.NullUUID{UUID: reportReq.UserID, Valid: true},
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	if report.Done == "" && report.Planned == "" {
+		return nil, ierr.ErrStatusReportIsEmpty
+	}
+
+	draft, err := s.GetStatusReportDraft(ctx, reportReq.ProjectID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	report.TasksDone = len(draft.DoneTasks)
+	for _, commits := range draft.Commits {
+		report.Commits += commits.Commits
+	}
+
+	if err = s.repo.UpsertStatusReport(ctx, report); err != nil {
+		return nil, err
+	}
+	if draft.Report != nil {
+		report.PMComment = draft.Report.PMComment
+		report.CommentedBy = draft.Report.CommentedBy
+		report.CommentedAt = draft.Report.CommentedAt
+	}
+
+	s.notify(ctx, model.EventStatusReportSubmitted, report.ProjectID, 0,
+		fmt.Sprintf("Команда отправила отчёт за неделю с %s", weekStart.Format("02.01.2006")),
+		s.projectUserIDs(ctx, report.ProjectID, model.RoleOwner)...)
+	return report, nil
+}
+
+func (s *service) GetStatusReports(ctx context.Context, projectID int) ([]model.StatusReport, error) {
+	return s.repo.GetStatusReports(ctx, repository.NewStatusReportFilter().
+		ByProjectID(projectID).WithPaginator(db.MaxLimit, 0))
+}
+
+// CommentStatusReport saves the PM comment on the report, an empty comment removes it
+func (s *service) CommentStatusReport(ctx context.Context, commentReq *api.StatusReportCommentReq) (*model.StatusReport, error) {
+	report, err := s.repo.GetStatusReport(ctx, repository.NewStatusReportFilter().
+		ByID(commentReq.ReportID).ByProjectID(commentReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	report.PMComment = sql.NullString{}
+	report.CommentedBy = uuid.NullUUID{}
+	report.CommentedAt = sql.NullTime{}
+	if comment := strings.TrimSpace(commentReq.Comment); comment != "" {
+		report.PMComment.Scan(comment)
+		report.CommentedBy = uuid.NullUUID{UUID: commentReq.UserID, Valid: true}
+		report.CommentedAt.Scan(time.Now())
+	}
+
+	if err = s.repo.UpdateStatusReportComment(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if report.PMComment.Valid {
+		s.notify(ctx, model.EventStatusReportCommented, report.ProjectID, 0,
+			fmt.Sprintf("Руководитель прокомментировал отчёт за неделю с %s", report.WeekStart.Format("02.01.2006")),
+			s.projectUserIDs(ctx, report.ProjectID, model.RoleTeamlead)...)
+	}
+	return report, nil
+}
+
+// GetWeekStatusReports returns the report state of the week for every active project of the PM,
+// or of all the projects if ownerID is not set. Projects without the report go first.
+func (s *service) GetWeekStatusReports(ctx context.Context, ownerID uuid.UUID, week time.Time) ([]model.ProjectStatusReport, error) {
+	weekStart := model.WeekStart(week)
+	projects, err := s.repo.GetProjects(ctx, repository.NewProjectFilter().
+		ByOwnerID(ownerID).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]model.Project, 0, len(projects))
+	projectIDs := make([]int, 0, len(projects))
+	for _, project := range projects {
+		if project.ActiveTo.IsZero() || !project.ActiveTo.Before(weekStart) {
+			active = append(active, project)
+			projectIDs = append(projectIDs, project.ID)
+		}
+	}
+
+	reports, err := s.repo.GetStatusReports(ctx, repository.NewStatusReportFilter().
+		ByProjectIDs(projectIDs).ByWeekStart(weekStart).WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+	byProject := make(map[int]*model.StatusReport, len(reports))
+	for i := range reports {
+		byProject[reports[i].ProjectID] = &reports[i]
+	}
+
+	res := make([]model.ProjectStatusReport, 0, len(active))
+	for _, project := range active {
+		res = append(res, model.ProjectStatusReport{Project: project, Report: byProject[project.ID]})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Report == nil && res[j].Report != nil
+	})
+	return res, nil
+}
+
+func (s *service) ExportStatusReports(ctx context.Context, projectID int, format model.StatusReportsFormat) ([]byte, error) {
+	if _, ok := model.StatusReportsFormats[format]; !ok {
+		return nil, ierr.ErrInvalidStatusReportsFormat
+	}
+
+	reports, err := s.GetStatusReports(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The series goes from the first week
+	rows := make([][]interface{}, 0, len(reports))
+	for i := len(reports) - 1; i >= 0; i-- {
+		report := reports[i]
+		rows = append(rows, []interface{}{
+			report.WeekStart.Format("2006-01-02"),
+			report.Done, report.Planned, report.Blockers,
+			report.TasksDone, report.Commits,
+			report.PMComment.String,
+			report.SubmittedAt.Format(time.RFC3339),
+		})
+	}
+
+	if format == model.StatusReportsCSV {
+		return exportTableCSV(statusReportsHeader, rows)
+	}
+	return exportTableXLSX(statusReportsHeader, rows)
+}
+
+// weeklyCommits counts the commits of the project users in the week. The report can be
+// written without them, so the github errors are only logged.
+func (s *service) weeklyCommits(ctx context.Context, projectID int, weekStart time.Time) []model.UserCommits {
+	users, err := s.repo.GetPartialUsers(ctx, repository.NewUserFilter().ByAtProject(projectID))
+	if err != nil {
+		s.logger.Errorf("failed to get users of project %v: %v", projectID, err)
+		return nil
+	}
+
+	res := make([]model.UserCommits, 0, len(users))
+	for _, user := range users {
+		res = append(res, model.UserCommits{ShortUser: user})
+	}
+
+	repositories, err := s.GetProjectRepositories(ctx, projectID)
+	if err != nil {
+		s.logger.Errorf("failed to get repositories of project %v: %v", projectID, err)
+		return res
+	}
+
+	counts := make(map[uuid.UUID]int)
+	for _, projectRepository := range repositories {
+		provider, repo, err := s.repositoryVCS(projectRepository.URL)
+		if err != nil {
+			continue
+		}
+
+		logins, err := s.loginUserIDs(ctx, repo.Host, users)
+		if err != nil {
+			s.logger.Errorf("failed to get vcs usernames of project %v: %v", projectID, err)
+			continue
+		}
+		commits, err := s.commitProvider(provider).ListCommits(ctx, repo, weekStart, weekStart.AddDate(0, 0, 7))
+		if err != nil {
+			s.logger.Warnf("failed to list commits of repository %v of project %v: %v",
+				repo.FullName(), projectID, err)
+			continue
+		}
+		for _, commit := range commits {
+			login := commit.AuthorLogin
+			if login == "" {
+				login = commit.AuthorEmail
+			}
+			if userID, ok := logins[strings.ToLower(login)]; ok {
+				counts[userID]++
+			}
+		}
+	}
+
+	for i := range res {
+		res[i].Commits = counts[res[i].ID]
+	}
+	return res
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SubmitProjectReport saves the uploaded file or the link as the next version of the report
+// and makes it the project's report. Submissions after the ActiveTo day are marked as late.
+func (s *service) SubmitProjectReport(ctx context.Context, submitReq *api.SubmitReportReq) (*model.ReportSubmission, error) {
+	project, err := s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(submitReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submission := &model.ReportSubmission{
+		ProjectID:   project.ID,
+		SubmittedAt: now,
+		Late:        !project.ActiveTo.IsZero() && now.After(project.ActiveTo.AddDate(0, 0, 1)),
+		Status:      model.SubmissionSubmitted,
+	}
+	if comment := strings.TrimSpace(submitReq.Comment); comment != "" {
+		submission.Comment.Scan(comment)
+	}
+	if userID := domain.UserIDFromContext(ctx); userID != uuid.Nil {
+		submission.SubmittedBy = uuid.NullUUID{UUID: userID, Valid: true}
+	}
+
+	switch link := strings.TrimSpace(submitReq.URL); {
+	case submitReq.File != nil:
+		submitReq.File.ProjectID = project.ID
+		file, err := s.uploadFile(ctx, model.FileReport, submitReq.File)
+		if err != nil {
+			return nil, err
+		}
+		submission.FileID.Scan(int64(file.ID))
+		submission.URL.Scan(api.FileURL(file.ID))
+		submission.Name = file.Name
+	case link != "":
+		if u, err := url.ParseRequestURI(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, ierr.ErrSubmissionIsEmpty
+		}
+		submission.URL.Scan(link)
+		submission.Name = strings.TrimSpace(submitReq.Name)
+		if submission.Name == "" {
+			submission.Name = link
+		}
+	default:
+		return nil, ierr.ErrSubmissionIsEmpty
+	}
+
+	// the submission becomes the report of the project in the same transaction
+	if err = s.repo.InsertSubmission(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Команда проекта «%s» отправила отчёт, версия %d", project.Name, submission.Version)
+	if submission.Late {
+		message += " (после срока сдачи)"
+	}
+	s.notify(ctx, model.EventReportSubmitted, project.ID, 0, message,
+		s.projectUserIDs(ctx, project.ID, model.RoleOwner)...)
+	return submission, nil
+}
+
+func (s *service) GetProjectSubmissions(ctx context.Context, projectID int) ([]model.ReportSubmission, error) {
+	return s.repo.GetSubmissions(ctx, repository.NewSubmissionFilter().ByProjectID(projectID).WithPaginator(0, 0))
+}
+
+func (s *service) ReviewSubmission(ctx context.Context, reviewReq *api.ReviewSubmissionReq) (*model.ReportSubmission, error) {
+	status := model.SubmissionStatus(reviewReq.Status)
+	if _, ok := model.ReviewStatuses[status]; !ok {
+		return nil, ierr.ErrInvalidReviewStatus
+	}
+
+	submission, err := s.repo.GetSubmission(ctx, repository.NewSubmissionFilter().
+		ByID(reviewReq.SubmissionID).ByProjectID(reviewReq.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+
+	submission.Status = status
+	submission.ReviewComment.Scan(nil)
+	if comment := strings.TrimSpace(reviewReq.Comment); comment != "" {
+		submission.ReviewComment.Scan(comment)
+	}
+	submission.ReviewerID = uuid.NullUUID{UUID: reviewReq.ReviewerID, Valid: true}
+	submission.ReviewedAt.Scan(time.Now())
+	if err = s.repo.UpdateSubmissionReview(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Отчёт версии %d принят", submission.Version)
+	if status == model.SubmissionChangesRequested {
+		message = fmt.Sprintf("По отчёту версии %d запрошены исправления", submission.Version)
+	}
+	s.notify(ctx, model.EventReportReviewed, submission.ProjectID, 0, message,
+		s.projectUserIDs(ctx, submission.ProjectID, model.RoleTeamlead, model.RoleParticipant)...)
+	return submission, nil
+}
+
+// GetCourseSubmissions returns the report state of every project the PM owns in the course
+func (s *service) GetCourseSubmissions(ctx context.Context, userID uuid.UUID, course string) ([]model.ProjectSubmissions, error) {
+	return s.repo.GetCourseSubmissions(ctx, userID, strings.TrimSpace(course))
+}
+
+// projectUserIDs returns the users of the project participants with the roles
+func (s *service) projectUserIDs(ctx context.Context, projectID int, roles ...model.ParticipantRole) []uuid.UUID {
+	participants, err := s.repo.GetParticipants(ctx, repository.NewParticipantFilter().ByProjectID(projectID))
+	if err != nil {
+		s.logger.Errorf("failed to get participants of project %v: %v", projectID, err)
+		return nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(participants))
+	for _, participant := range participants {
+		for _, role := range roles {
+			if participant.Role == role {
+				userIDs = append(userIDs, participant.ShortUser.ID)
+				break
+			}
+		}
+	}
+	return userIDs
+}
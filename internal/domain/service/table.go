@@ -0,0 +1,60 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const tableSheet = "Sheet1"
+
+func exportTableCSV(header []string, rows [][]interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		line := make([]string, 0, len(row))
+		for _, value := range row {
+			line = append(line, fmt.Sprint(value))
+		}
+		if err := w.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func exportTableXLSX(header []string, rows [][]interface{}) ([]byte, error) {
+	xlsx := excelize.NewFile()
+	for j, value := range header {
+		if err := setTableCell(xlsx, j+1, 1, value); err != nil {
+			return nil, err
+		}
+	}
+	for i, row := range rows {
+		for j, value := range row {
+			if err := setTableCell(xlsx, j+1, i+2, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buffer, err := xlsx.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func setTableCell(xlsx *excelize.File, col, row int, value interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	return xlsx.SetCellValue(tableSheet, cell, value)
+}
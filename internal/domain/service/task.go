@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/db"
 	"be-project-monitoring/internal/domain/model"
 	ierr "be-project-monitoring/internal/errors"
 	"be-project-monitoring/internal/repository"
@@ -95,12 +96,25 @@ func (s *service) CreateTask(ctx context.Context, creatorUserID uuid.UUID, taskR
 	if strings.TrimSpace(taskReq.Description) != "" {
 		task.Description.Scan(taskReq.Description)
 	}
+	task.Labels = normalizeLabels(taskReq.Labels)
+	if taskReq.DueDate != nil && !taskReq.DueDate.IsZero() {
+		task.DueDate.Scan(*taskReq.DueDate)
+	}
 
 	if taskReq.SuggestedEstimate != 0 {
 		task.Estimate.Scan(taskReq.SuggestedEstimate)
 	}
 
-	return task, s.repo.InsertTask(ctx, task)
+	if task.Rank, err = s.lastRankInColumn(ctx, task.ProjectID, task.Status); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.InsertTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	s.notifyTaskChanged(ctx, nil, task)
+	return task, nil
 }
 func (s *service) UpdateTask(ctx context.Context, taskReq *api.UpdateTaskReq) (*model.Task, error) {
 
@@ -133,15 +147,175 @@ func (s *service) UpdateTask(ctx context.Context, taskReq *api.UpdateTaskReq) (*
 		return nil, err
 	}
 
-	return newTask, s.repo.UpdateTask(ctx, newTask)
+	if newTask.Status != oldTask.Status {
+		if newTask.Rank, err = s.lastRankInColumn(ctx, newTask.ProjectID, newTask.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.repo.UpdateTask(ctx, newTask); err != nil {
+		return nil, err
+	}
+
+	s.notifyTaskChanged(ctx, oldTask, newTask)
+	return newTask, nil
+}
+
+// projectTasks returns every task of the project sorted by rank,
+// the tasks are read by pages, so the large projects are not cut at the limit
+func (s *service) projectTasks(ctx context.Context, projectID int) ([]model.Task, error) {
+	tasks := make([]model.Task, 0)
+	for offset := uint64(0); ; offset += db.MaxLimit {
+		page, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().
+			ByProjectID(projectID).WithPaginator(db.MaxLimit, offset))
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+		if len(page) < db.MaxLimit {
+			return tasks, nil
+		}
+	}
 }
 
 func (s *service) DeleteTask(ctx context.Context, id int) error {
 	return s.repo.DeleteTask(ctx, id)
 }
 
+// BulkUpdateTasks applies one action to the list of tasks. Every task is validated
+// first, and nothing is changed unless all of them are valid.
+func (s *service) BulkUpdateTasks(ctx context.Context, bulkReq *api.BulkTaskReq) ([]model.BulkTaskResult, error) {
+	action := model.BulkTaskAction(bulkReq.Action)
+	if _, ok := model.BulkTaskActions[action]; !ok {
+		return nil, ierr.ErrInvalidBulkAction
+	}
+	if len(bulkReq.TaskIDs) == 0 {
+		return nil, ierr.ErrTaskIDIsInvalid
+	}
+
+	// The same checks as in UpdateTask, done once for the whole list
+	updateReq := api.UpdateTaskReq{ProjectID: bulkReq.ProjectID}
+	participantID := &sql.NullInt64{}
+	switch action {
+	case model.BulkReassign:
+		if bulkReq.ParticipantID == nil {
+			return nil, ierr.ErrTaskParticipantIDNotFound
+		}
+		if *bulkReq.ParticipantID != 0 {
+			if _, err := s.repo.GetParticipant(ctx, repository.NewParticipantFilter().
+				ByID(*bulkReq.ParticipantID).ByProjectID(bulkReq.ProjectID)); err != nil {
+				return nil, ierr.ErrTaskParticipantIDNotFound
+			}
+			participantID.Scan(*bulkReq.ParticipantID)
+		}
+	case model.BulkStatus:
+		if bulkReq.Status == nil {
+			return nil, ierr.ErrInvalidStatus
+		}
+		if _, ok := model.TaskStatuses[*bulkReq.Status]; !ok {
+			return nil, ierr.ErrInvalidStatus
+		}
+		updateReq.Status = bulkReq.Status
+	case model.BulkRelabel:
+		labels := bulkReq.Labels
+		updateReq.Labels = &labels
+	case model.BulkApprove:
+		approved := true
+		if bulkReq.Approved != nil {
+			approved = *bulkReq.Approved
+		}
+		updateReq.Approved = &approved
+	}
+
+	tasks, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().
+		ByProjectID(bulkReq.ProjectID).
+		ByIDs(bulkReq.TaskIDs).
+		WithPaginator(db.MaxLimit, 0))
+	if err != nil {
+		return nil, err
+	}
+	tasksByID := make(map[int]model.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	var (
+		results = make([]model.BulkTaskResult, 0, len(bulkReq.TaskIDs))
+		ids     = make([]int, 0, len(bulkReq.TaskIDs))
+		seen    = make(map[int]struct{}, len(bulkReq.TaskIDs))
+		isValid = true
+	)
+	for _, id := range bulkReq.TaskIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if _, ok := tasksByID[id]; !ok {
+			results = append(results, model.BulkTaskResult{TaskID: id, Err: ierr.ErrTaskNotFound})
+			isValid = false
+			continue
+		}
+		results = append(results, model.BulkTaskResult{TaskID: id})
+		ids = append(ids, id)
+	}
+	if !isValid {
+		return results, ierr.ErrBulkValidationFailed
+	}
+
+	if action == model.BulkDelete {
+		return results, s.repo.DeleteTasks(ctx, ids)
+	}
+
+	var lastRank string
+	if action == model.BulkStatus {
+		if lastRank, err = s.lastRankInColumn(ctx, bulkReq.ProjectID, model.TaskStatus(*bulkReq.Status)); err != nil {
+			return nil, err
+		}
+	}
+
+	newTasks := make([]model.Task, 0, len(ids))
+	for _, id := range ids {
+		oldTask := tasksByID[id]
+		updateReq.ID = id
+
+		newParticipantID := oldTask.ParticipantID
+		if action == model.BulkReassign {
+			newParticipantID = *participantID
+		}
+
+		newTask, err := mergeTaskFields(&oldTask, &updateReq, newParticipantID)
+		if err != nil {
+			return nil, err
+		}
+		if newTask.Status != oldTask.Status {
+			newTask.Rank = lastRank
+			lastRank = model.RankBetween(lastRank, "")
+		}
+		newTasks = append(newTasks, *newTask)
+	}
+
+	if err = s.repo.UpdateTasks(ctx, newTasks); err != nil {
+		return nil, err
+	}
+
+	for i := range newTasks {
+		oldTask := tasksByID[newTasks[i].ID]
+		s.notifyTaskChanged(ctx, &oldTask, &newTasks[i])
+	}
+	return results, nil
+}
+
+// GetTaskInfo returns the task with the commits and the pull requests linked to it
 func (s *service) GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo, error) {
-	return s.repo.GetTaskInfo(ctx, id)
+	taskInfo, err := s.repo.GetTaskInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if taskInfo.Links, err = s.repo.GetTaskLinks(ctx, id); err != nil {
+		return nil, err
+	}
+	return taskInfo, nil
 }
 
 func mergeTaskFields(oldTask *model.Task, taskReq *api.UpdateTaskReq, newParticipantID sql.NullInt64) (*model.Task, error) {
@@ -150,6 +324,8 @@ func mergeTaskFields(oldTask *model.Task, taskReq *api.UpdateTaskReq, newPartici
 			ID:            taskReq.ID,
 			ParticipantID: newParticipantID,
 			CreatorID:     oldTask.CreatorID,
+			Rank:          oldTask.Rank,
+			Version:       oldTask.Version + 1,
 			CreatedAt:     oldTask.CreatedAt,
 			UpdatedAt:     time.Now(),
 		},
@@ -189,5 +365,35 @@ func mergeTaskFields(oldTask *model.Task, taskReq *api.UpdateTaskReq, newPartici
 		newTask.Approved.Scan(*taskReq.Approved)
 	}
 
+	if taskReq.Labels == nil {
+		newTask.Labels = oldTask.Labels
+	} else {
+		newTask.Labels = normalizeLabels(*taskReq.Labels)
+	}
+
+	if taskReq.DueDate == nil {
+		newTask.DueDate = oldTask.DueDate
+	} else if !taskReq.DueDate.IsZero() {
+		newTask.DueDate.Scan(*taskReq.DueDate)
+	}
+
 	return newTask, nil
 }
+
+// normalizeLabels trims labels and drops the empty and repeated ones
+func normalizeLabels(labels []string) []string {
+	res := make([]string, 0, len(labels))
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if _, ok := seen[label]; ok {
+			continue
+		}
+		seen[label] = struct{}{}
+		res = append(res, label)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/repository"
+)
+
+// taskReferencePattern matches the references to the tasks like PM-123 or #task-123
+var taskReferencePattern = regexp.MustCompile(`(?i)(?:\bPM-|#task-)(\d+)\b`)
+
+// linkTasks links the commits and the pull requests of the events to the tasks of their projects
+// they reference. The tasks of the projects with AutoTransitionTasks follow the pull requests.
+func (s *service) linkTasks(ctx context.Context, events []model.RepositoryEvent) error {
+	links := make([]model.TaskLink, 0)
+	transitions := make([]api.UpdateTaskReq, 0)
+	projects := make(map[int]*model.Project)
+	for _, event := range events {
+		if event.Kind != model.EventCommit && event.Kind != model.EventPullRequest {
+			continue
+		}
+		ids := taskReferences(event.Title, event.Ref)
+		if len(ids) == 0 {
+			continue
+		}
+
+		tasks, err := s.repo.GetTasks(ctx, repository.NewTaskFilter().
+			ByIDs(ids).ByProjectID(event.ProjectID))
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			links = append(links, model.TaskLink{
+				TaskID:         task.ID,
+				RepositoryID:   event.RepositoryID,
+				Kind:           event.Kind,
+				SHA:            event.SHA.String,
+				Number:         int(event.Number.Int64),
+				Title:          event.Title,
+				URL:            event.URL,
+				GithubUsername: event.GithubUsername,
+				UserID:         event.UserID,
+				State:          event.State,
+				Additions:      event.Additions,
+				Deletions:      event.Deletions,
+				LinkedAt:       event.OccurredAt,
+				UpdatedAt:      event.OccurredAt,
+			})
+
+			status, ok := pullRequestTaskStatus(event, task.Status)
+			if !ok {
+				continue
+			}
+			project, ok := projects[event.ProjectID]
+			if !ok {
+				if project, err = s.repo.GetProject(ctx, repository.NewProjectFilter().ByID(event.ProjectID)); err != nil {
+					return err
+				}
+				projects[event.ProjectID] = project
+			}
+			if project.AutoTransitionTasks {
+				statusReq := string(status)
+				transitions = append(transitions, api.UpdateTaskReq{
+					ID:        task.ID,
+					ProjectID: task.ProjectID,
+					Status:    &statusReq,
+				})
+			}
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	if err := s.repo.UpsertTaskLinks(ctx, links); err != nil {
+		return err
+	}
+	for i := range transitions {
+		if _, err := s.UpdateTask(ctx, &transitions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *service) GetTaskChurn(ctx context.Context, projectID int) ([]model.TaskChurn, error) {
+	return s.repo.GetTaskChurn(ctx, projectID)
+}
+
+// pullRequestTaskStatus returns the status the task moves to after the event of the pull request:
+// REVIEW when it opens and DONE when it merges. The task is never moved back.
+func pullRequestTaskStatus(event model.RepositoryEvent, current model.TaskStatus) (model.TaskStatus, bool) {
+	if event.Kind != model.EventPullRequest || current == model.Done {
+		return "", false
+	}
+	switch {
+	case event.Action == "closed" && event.State == "merged":
+		return model.Done, true
+	case (event.Action == "opened" || event.Action == "reopened" || event.Action == "ready_for_review") &&
+		current != model.InReview:
+		return model.InReview, true
+	default:
+		return "", false
+	}
+}
+
+// taskReferences returns the ids of the tasks referenced in the texts, every id once
+func taskReferences(texts ...string) []int {
+	ids := make([]int, 0)
+	seen := make(map[int]struct{})
+	for _, text := range texts {
+		for _, match := range taskReferencePattern.FindAllStringSubmatch(text, -1) {
+			id, err := strconv.Atoi(match[1])
+			if err != nil {
+				continue
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -8,6 +8,7 @@ import (
 	"be-project-monitoring/internal/domain/model"
 	ierr "be-project-monitoring/internal/errors"
 	"be-project-monitoring/internal/repository"
+	"be-project-monitoring/internal/vcs"
 
 	"github.com/AvraamMavridis/randomcolor"
 	"github.com/google/uuid"
@@ -168,9 +169,13 @@ func (s *service) DeleteUser(ctx context.Context, guid uuid.UUID) error {
 }
 
 func (s *service) FindGithubUser(ctx context.Context, username string) bool {
-	user, _, err := s.githubCl.Users.Get(ctx, username)
+	provider, ok := s.providers[vcs.GithubHost]
+	if !ok {
+		return false
+	}
+	user, err := provider.GetUser(ctx, username)
 
-	return err == nil && user.GetLogin() == username
+	return err == nil && user.Login == username
 }
 func (s *service) GetUserProfile(ctx context.Context, guid uuid.UUID) (*model.Profile, error) {
 	return s.repo.GetUserProfile(ctx, guid)
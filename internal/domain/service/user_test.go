@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/vcs"
+)
+
+func TestFindGithubUser(t *testing.T) {
+	github := vcs.NewFakeProvider()
+	github.AddUser(model.VCSUser{Login: "alice"})
+	s := newTestService(t, &stubRepository{}, vcs.Providers{vcs.GithubHost: github})
+
+	tests := map[string]bool{
+		"alice": true,
+		// the username must be written like at github
+		"Alice": false,
+		"bob":   false,
+	}
+	for username, want := range tests {
+		if got := s.FindGithubUser(context.Background(), username); got != want {
+			t.Errorf("FindGithubUser(%q) = %v, want %v", username, got, want)
+		}
+	}
+
+	github.SetErr(errors.New("github is unavailable"))
+	if s.FindGithubUser(context.Background(), "alice") {
+		t.Error("user is found while github fails")
+	}
+
+	s = newTestService(t, &stubRepository{}, vcs.Providers{"gitlab.example.com": vcs.NewFakeProvider()})
+	if s.FindGithubUser(context.Background(), "alice") {
+		t.Error("user is found without the github provider")
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/vcs"
+
+	"github.com/google/uuid"
+)
+
+// GetVCSAccounts returns the user's accounts at every configured provider but github,
+// whose username is set in the user's profile. The accounts not set up have an empty username.
+func (s *service) GetVCSAccounts(ctx context.Context, userID uuid.UUID) ([]model.VCSAccount, error) {
+	saved, err := s.repo.GetVCSAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	savedByHost := make(map[string]model.VCSAccount, len(saved))
+	for _, account := range saved {
+		savedByHost[account.Host] = account
+	}
+
+	accounts := make([]model.VCSAccount, 0, len(s.providers))
+	for host := range s.providers {
+		if host == vcs.GithubHost {
+			continue
+		}
+		account, ok := savedByHost[host]
+		if !ok {
+			account = model.VCSAccount{Host: host}
+		}
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Host < accounts[j].Host })
+	return accounts, nil
+}
+
+// UpdateVCSAccounts saves the accounts which exist at their providers, an empty username removes the account
+func (s *service) UpdateVCSAccounts(ctx context.Context, userID uuid.UUID,
+	accounts []model.VCSAccount) ([]model.VCSAccount, error) {
+	for i, account := range accounts {
+		provider, ok := s.providers[account.Host]
+		if !ok || account.Host == vcs.GithubHost {
+			return nil, ierr.ErrInvalidVCSHost
+		}
+		accounts[i].Username = strings.TrimSpace(account.Username)
+		if accounts[i].Username == "" {
+			continue
+		}
+
+		ownerID, err := s.repo.GetVCSAccountUserID(ctx, account.Host, accounts[i].Username)
+		if err != nil {
+			return nil, err
+		}
+		if ownerID != uuid.Nil && ownerID != userID {
+			return nil, ierr.ErrVCSUsernameAlreadyExists
+		}
+		user, err := provider.GetUser(ctx, accounts[i].Username)
+		if errors.Is(err, vcs.ErrUserNotFound) {
+			return nil, ierr.ErrVCSUserNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		accounts[i].Username = user.Login
+	}
+
+	if err := s.repo.UpsertVCSAccounts(ctx, userID, accounts); err != nil {
+		return nil, err
+	}
+	return s.GetVCSAccounts(ctx, userID)
+}
+
+// loginUserIDs maps the lowercased logins at host to the ids of the users the project metrics are keyed by.
+// The emails of the users are mapped too, the providers and the local git history identify the unknown authors by them.
+func (s *service) loginUserIDs(ctx context.Context, host string, users []model.ShortUser) (map[string]uuid.UUID, error) {
+	res := make(map[string]uuid.UUID, len(users))
+	if host == vcs.GithubHost || host == vcs.LocalHost {
+		for _, user := range users {
+			if user.GithubUsername != "" {
+				res[strings.ToLower(user.GithubUsername)] = user.ID
+			}
+			if user.Email != "" {
+				res[strings.ToLower(user.Email)] = user.ID
+			}
+		}
+		return res, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	usernames, err := s.repo.GetVCSUsernames(ctx, host, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if username, ok := usernames[user.ID]; ok && username != "" {
+			res[strings.ToLower(username)] = user.ID
+		}
+		if user.Email != "" {
+			res[strings.ToLower(user.Email)] = user.ID
+		}
+	}
+	return res, nil
+}
+
+// repositoryUserIDs maps the logins at the provider of every repository to the ids of the users
+func (s *service) repositoryUserIDs(ctx context.Context, repositories []model.ProjectRepository,
+	users []model.ShortUser) (map[int]map[string]uuid.UUID, error) {
+	logins := make(map[int]map[string]uuid.UUID, len(repositories))
+	loginsByHost := make(map[string]map[string]uuid.UUID)
+	for _, projectRepository := range repositories {
+		repo, ok := s.providers.ParseRepoURL(projectRepository.URL)
+		if !ok {
+			continue
+		}
+		if _, ok = loginsByHost[repo.Host]; !ok {
+			hostLogins, err := s.loginUserIDs(ctx, repo.Host, users)
+			if err != nil {
+				return nil, err
+			}
+			loginsByHost[repo.Host] = hostLogins
+		}
+		logins[projectRepository.ID] = loginsByHost[repo.Host]
+	}
+	return logins, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-project-monitoring/internal/domain/model"
+	"be-project-monitoring/internal/vcs"
+
+	"github.com/google/uuid"
+)
+
+func TestLoginUserIDs(t *testing.T) {
+	var (
+		alice = model.ShortUser{ID: uuid.New(), GithubUsername: "Alice", Email: "Alice@Example.com"}
+		bob   = model.ShortUser{ID: uuid.New(), Email: "bob@example.com"}
+		cid   = model.ShortUser{ID: uuid.New()}
+	)
+	s := newTestService(t, &stubRepository{vcsUsernames: map[string]map[uuid.UUID]string{
+		"gitlab.example.com": {bob.ID: "Bobby", cid.ID: "cid"},
+	}}, nil)
+
+	tests := []struct {
+		host string
+		want map[string]uuid.UUID
+	}{
+		// the local history is mapped by the github logins and by the emails of the authors
+		{vcs.LocalHost, map[string]uuid.UUID{
+			"alice": alice.ID, "alice@example.com": alice.ID, "bob@example.com": bob.ID,
+		}},
+		{vcs.GithubHost, map[string]uuid.UUID{
+			"alice": alice.ID, "alice@example.com": alice.ID, "bob@example.com": bob.ID,
+		}},
+		// the students without a github account are found by their accounts at the host
+		{"gitlab.example.com", map[string]uuid.UUID{
+			"alice@example.com": alice.ID, "bobby": bob.ID, "bob@example.com": bob.ID, "cid": cid.ID,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			logins, err := s.loginUserIDs(context.Background(), tt.host, []model.ShortUser{alice, bob, cid})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(logins) != len(tt.want) {
+				t.Errorf("loginUserIDs() = %v, want %v", logins, tt.want)
+			}
+			for login, id := range tt.want {
+				if logins[login] != id {
+					t.Errorf("login %q is mapped to %v, want %v", login, logins[login], id)
+				}
+			}
+		})
+	}
+}
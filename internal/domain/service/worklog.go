@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/api"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+	"be-project-monitoring/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func (s *service) AddWorklog(ctx context.Context, userID uuid.UUID, worklogReq *api.CreateWorklogReq) (*model.Worklog, error) {
+	if worklogReq.Duration <= 0 {
+		return nil, ierr.ErrWorklogDurationIsInvalid
+	}
+
+	participant, err := s.VerifyParticipant(ctx, userID, worklogReq.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.getProjectTask(ctx, worklogReq.ProjectID, worklogReq.TaskID); err != nil {
+		return nil, err
+	}
+
+	worklog := &model.Worklog{
+		TaskID:        worklogReq.TaskID,
+		ParticipantID: participant.ID,
+		Duration:      worklogReq.Duration,
+		Date:          worklogReq.Date,
+		CreatedAt:     time.Now(),
+	}
+	if worklog.Date.IsZero() {
+		worklog.Date = time.Now()
+	}
+	if strings.TrimSpace(worklogReq.Note) != "" {
+		worklog.Note.Scan(worklogReq.Note)
+	}
+
+	return worklog, s.repo.InsertWorklog(ctx, worklog)
+}
+
+func (s *service) GetTaskWorklogs(ctx context.Context, projectID, taskID int) (*model.TaskWorklogs, error) {
+	task, err := s.getProjectTask(ctx, projectID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	worklogs, err := s.repo.GetWorklogs(ctx, repository.NewWorklogFilter().ByTaskID(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	// the list is paginated, the actual time is summed over all the worklogs
+	actual, err := s.repo.GetTaskWorklogTotal(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TaskWorklogs{
+		TaskID:   task.ID,
+		Estimate: task.Estimate,
+		Actual:   actual,
+		Worklogs: worklogs,
+	}, nil
+}
+
+// DeleteWorklog deletes the worklog of the task of the project, the owner can delete the worklogs of the others
+func (s *service) DeleteWorklog(ctx context.Context, userID uuid.UUID, projectID, taskID, id int) error {
+	participant, err := s.VerifyParticipant(ctx, userID, projectID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return err
+	}
+	worklog, err := s.repo.GetWorklog(ctx, repository.NewWorklogFilter().ByID(id).ByTaskID(taskID))
+	if err != nil {
+		return err
+	}
+
+	if worklog.ParticipantID != participant.ID && participant.Role != model.RoleOwner {
+		return ierr.ErrAccessDeniedWrongParticipantRole
+	}
+
+	return s.repo.DeleteWorklog(ctx, id)
+}
+
+func (s *service) StartTimer(ctx context.Context, userID uuid.UUID, projectID, taskID int) (*model.Worklog, error) {
+	participant, err := s.VerifyParticipant(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.getProjectTask(ctx, projectID, taskID); err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.GetWorklog(ctx, repository.NewWorklogFilter().
+		ByTaskID(taskID).ByParticipantID(participant.ID).ByRunning(true))
+	if err == nil {
+		return nil, ierr.ErrTimerAlreadyStarted
+	} else if !errors.Is(err, ierr.ErrWorklogNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	worklog := &model.Worklog{
+		TaskID:        taskID,
+		ParticipantID: participant.ID,
+		Date:          now,
+		Running:       true,
+		CreatedAt:     now,
+	}
+	worklog.StartedAt.Scan(now)
+
+	return worklog, s.repo.InsertWorklog(ctx, worklog)
+}
+
+func (s *service) StopTimer(ctx context.Context, userID uuid.UUID, projectID, taskID int, note string) (*model.Worklog, error) {
+	participant, err := s.VerifyParticipant(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	worklog, err := s.repo.GetWorklog(ctx, repository.NewWorklogFilter().
+		ByTaskID(taskID).ByParticipantID(participant.ID).ByRunning(true))
+	if errors.Is(err, ierr.ErrWorklogNotFound) {
+		return nil, ierr.ErrTimerNotStarted
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Rounding up, so a timer that was stopped right away still counts as a minute
+	worklog.Duration = int(math.Ceil(time.Since(worklog.StartedAt.Time).Minutes()))
+	worklog.Running = false
+	if strings.TrimSpace(note) != "" {
+		worklog.Note.Scan(note)
+	}
+
+	return worklog, s.repo.UpdateWorklog(ctx, worklog)
+}
+
+func (s *service) GetWeeklyWorklogs(ctx context.Context, projectID int) ([]model.WeeklyWorklog, error) {
+	return s.repo.GetWeeklyWorklogs(ctx, projectID)
+}
+
+func (s *service) getProjectTask(ctx context.Context, projectID, taskID int) (*model.Task, error) {
+	task, err := s.repo.GetTask(ctx, repository.NewTaskFilter().ByID(taskID))
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID != projectID {
+		return nil, ierr.ErrTaskNotFound
+	}
+	return task, nil
+}
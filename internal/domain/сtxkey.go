@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
 const (
 	UserIDCtx    CtxKey = "user_id"
 	ProjectIDCtx CtxKey = "project_id"
@@ -8,3 +14,10 @@ const (
 type (
 	CtxKey string
 )
+
+// UserIDFromContext returns id of the user who made the request, or uuid.Nil
+// if the request was not authorized
+func UserIDFromContext(ctx context.Context) uuid.UUID {
+	id, _ := ctx.Value(UserIDCtx).(uuid.UUID)
+	return id
+}
@@ -39,4 +39,63 @@ var (
 	ErrTeamLeadAlreadyExists            = errors.New("team lead already exists")
 	ErrRepositoryURLIsEmpty             = errors.New("repository url is empty")
 	ErrRepositoryURLWrongFormat         = errors.New("repository url has wrong format")
+	ErrRepositoryHostNotSupported       = errors.New("repository host is not supported")
+	ErrWorklogNotFound                  = errors.New("worklog not found")
+	ErrWorklogDurationIsInvalid         = errors.New("worklog duration is not valid")
+	ErrTimerAlreadyStarted              = errors.New("timer is already started for this task")
+	ErrTimerNotStarted                  = errors.New("timer is not started for this task")
+	ErrTaskVersionConflict              = errors.New("task was changed by someone else, reload the board")
+	ErrWIPLimitExceeded                 = errors.New("column work in progress limit exceeded")
+	ErrWIPLimitIsInvalid                = errors.New("work in progress limit is not valid")
+	ErrInvalidBulkAction                = errors.New("invalid bulk action")
+	ErrInvalidBacklogFormat             = errors.New("invalid backlog format, use csv, json or md")
+	ErrInvalidBacklogFile               = errors.New("backlog file is not valid")
+	ErrInvalidNotificationEvent         = errors.New("invalid notification event")
+	ErrInvalidDeliveryChannel           = errors.New("invalid delivery channel")
+	ErrFileNotFound                     = errors.New("file not found")
+	ErrFileIsEmpty                      = errors.New("file is empty")
+	ErrFileTooLarge                     = errors.New("file is too large")
+	ErrFileTypeNotAllowed               = errors.New("file type is not allowed")
+	ErrSubmissionNotFound               = errors.New("report submission not found")
+	ErrSubmissionIsEmpty                = errors.New("report submission must have a file or a link")
+	ErrInvalidReviewStatus              = errors.New("invalid review status, use ACCEPTED or CHANGES_REQUESTED")
+	ErrRubricNotFound                   = errors.New("rubric not found")
+	ErrRubricIsInvalid                  = errors.New("rubric is not valid")
+	ErrInvalidCriterionScope            = errors.New("invalid criterion scope, use PROJECT or PARTICIPANT")
+	ErrInvalidCriterionMetric           = errors.New("invalid criterion metric for the scope")
+	ErrCriterionNotFound                = errors.New("criterion not found in rubric")
+	ErrGradeIsInvalid                   = errors.New("grade is not valid")
+	ErrInvalidGradeBookFormat           = errors.New("invalid grade book format, use xlsx or csv")
+	ErrPeerRoundNotFound                = errors.New("peer review round not found")
+	ErrPeerRoundIsInvalid               = errors.New("peer review round must have a name and unique criteria, max score from 2 to 100")
+	ErrPeerRoundIsClosed                = errors.New("peer review round is closed")
+	ErrPeerReviewIsInvalid              = errors.New("peer review must rate a teammate on every criterion of the round")
+	ErrStatusReportNotFound             = errors.New("status report not found")
+	ErrStatusReportIsEmpty              = errors.New("status report must say what was done or planned")
+	ErrStatusReportWeekInFuture         = errors.New("status report can't be submitted for a future week")
+	ErrInvalidStatusReportsFormat       = errors.New("invalid status reports format, use xlsx or csv")
+	ErrMeetingNotFound                  = errors.New("meeting not found")
+	ErrMeetingIsInvalid                 = errors.New("meeting must have a title, a date and a valid kind")
+	ErrActionItemIsInvalid              = errors.New("action item must have a text and an assignee from the project")
+	ErrChecklistNotFound                = errors.New("checklist not found")
+	ErrChecklistIsInvalid               = errors.New("checklist must have a name")
+	ErrChecklistItemNotFound            = errors.New("checklist item not found")
+	ErrChecklistItemIsInvalid           = errors.New("checklist item must have a name and an assignee from the project")
+	ErrChecklistItemIsLocked            = errors.New("checklist item is locked, only a project manager can change it")
+	ErrChecklistTemplateNotFound        = errors.New("checklist template not found")
+	ErrChecklistTemplateIsInvalid       = errors.New("checklist template must have a name and named items")
+	ErrTemplateProjectsNotSelected      = errors.New("select the course or the projects to apply the template to")
+	ErrInvalidVCSHost                   = errors.New("vcs host is not configured")
+	ErrVCSUserNotFound                  = errors.New("user with provided username not found in vcs")
+	ErrVCSUsernameAlreadyExists         = errors.New("vcs username already exists")
+	ErrProjectRepositoryNotFound        = errors.New("project repository not found")
+	ErrProjectRepositoryAlreadyExists   = errors.New("repository is already added to the project")
+	ErrWebhookSignatureIsInvalid        = errors.New("webhook signature is invalid")
+	ErrWebhookDeliveryIDIsEmpty         = errors.New("webhook delivery id is empty")
+	ErrWebhookDeliveryAlreadyReceived   = errors.New("webhook delivery is already received")
+	ErrIssueSyncNotFound                = errors.New("issue sync is not set up for the project")
+	ErrIssueSyncIsDisabled              = errors.New("issue sync is disabled for the project")
+	ErrIssueSyncNotSupported            = errors.New("issues of the repository host can not be changed")
+	ErrInvalidActivityGranularity       = errors.New("invalid activity granularity, expected WEEK or DAY")
+	ErrBulkValidationFailed             = errors.New("some of the tasks are not valid, nothing was changed")
 )
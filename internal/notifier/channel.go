@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// Channel sends messages to a user through an external service
+	Channel interface {
+		Name() model.DeliveryChannel
+		Send(ctx context.Context, address string, msg Message) error
+	}
+
+	// Message is one notification or a digest of notifications for one user
+	Message struct {
+		UserID  uuid.UUID `json:"user_id"`
+		Subject string    `json:"subject"`
+		Digest  bool      `json:"digest"`
+		Items   []Item    `json:"items"`
+	}
+
+	Item struct {
+		Event     model.NotificationEvent `json:"event"`
+		Message   string                  `json:"message"`
+		CreatedAt time.Time               `json:"created_at"`
+	}
+)
+
+// Text renders the message as plain text
+func (m Message) Text() string {
+	if !m.Digest && len(m.Items) == 1 {
+		return m.Items[0].Message
+	}
+
+	b := strings.Builder{}
+	b.WriteString(m.Subject)
+	b.WriteString("\n")
+	for _, item := range m.Items {
+		b.WriteString(fmt.Sprintf("\n%s — %s", item.CreatedAt.Format("02.01.2006 15:04"), item.Message))
+	}
+	return b.String()
+}
+
+func newMessage(deliveries []model.Delivery) Message {
+	msg := Message{
+		UserID:  deliveries[0].UserID,
+		Subject: "Новое уведомление",
+		Digest:  deliveries[0].Digest,
+		Items:   make([]Item, 0, len(deliveries)),
+	}
+	if msg.Digest {
+		msg.Subject = "Сводка уведомлений"
+	}
+	for _, d := range deliveries {
+		msg.Items = append(msg.Items, Item{
+			Event:     d.Event,
+			Message:   d.Message,
+			CreatedAt: d.CreatedAt,
+		})
+	}
+	return msg
+}
@@ -0,0 +1,224 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/oklog/run"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval    = time.Minute
+	defaultMaxAttempts = 5
+	batchSize          = 100
+	leaseTime          = 5 * time.Minute
+	baseBackoff        = time.Minute
+	maxBackoff         = 6 * time.Hour
+)
+
+type (
+	Store interface {
+		ClaimDeliveries(ctx context.Context, now, digestCutoff time.Time,
+			lease time.Duration, limit int) ([]model.Delivery, error)
+		MarkDeliveriesSent(ctx context.Context, ids []int, sentAt time.Time) error
+		RescheduleDeliveries(ctx context.Context, ids []int, nextAttemptAt time.Time, lastError string) error
+	}
+
+	// Dispatcher periodically sends the queued deliveries through the registered channels.
+	// Failed sends are retried with exponential back-off until maxAttempts is reached,
+	// digest deliveries are collected and sent once a day at digestHour.
+	Dispatcher struct {
+		store       Store
+		logger      *zap.SugaredLogger
+		channels    map[model.DeliveryChannel]Channel
+		interval    time.Duration
+		digestHour  int
+		maxAttempts int
+		now         func() time.Time
+	}
+
+	OptionFunc func(d *Dispatcher)
+
+	groupKey struct {
+		userID  string
+		channel model.DeliveryChannel
+		address string
+	}
+)
+
+func NewDispatcher(store Store, opts ...OptionFunc) *Dispatcher {
+	d := &Dispatcher{
+		store:       store,
+		logger:      zap.NewNop().Sugar(),
+		channels:    make(map[model.DeliveryChannel]Channel),
+		interval:    defaultInterval,
+		maxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *Dispatcher) Run(g *run.Group) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		d.logger.Info("[notifier] started")
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := d.Dispatch(ctx); err != nil {
+					d.logger.Error("[notifier] dispatch failed", zap.Error(err))
+				}
+			}
+		}
+	}, func(err error) {
+		cancel()
+		d.logger.Info("[notifier] stopped")
+	})
+}
+
+// Dispatch sends all due deliveries
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	for {
+		now := d.now()
+		deliveries, err := d.store.ClaimDeliveries(ctx, now, d.digestCutoff(now), leaseTime, batchSize)
+		if err != nil {
+			return fmt.Errorf("error while claiming deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		for _, group := range groupDeliveries(deliveries) {
+			d.send(ctx, now, group)
+		}
+
+		if len(deliveries) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, now time.Time, deliveries []model.Delivery) {
+	ids := make([]int, 0, len(deliveries))
+	attempts := 0
+	for _, v := range deliveries {
+		ids = append(ids, v.ID)
+		if v.Attempts > attempts {
+			attempts = v.Attempts
+		}
+	}
+
+	var err error
+	if ch, ok := d.channels[deliveries[0].Channel]; ok {
+		err = ch.Send(ctx, deliveries[0].Address, newMessage(deliveries))
+	} else {
+		err = fmt.Errorf("channel %s is not configured", deliveries[0].Channel)
+		attempts = d.maxAttempts
+	}
+
+	if err == nil {
+		if err = d.store.MarkDeliveriesSent(ctx, ids, now); err != nil {
+			d.logger.Error("[notifier] error while marking deliveries as sent", zap.Error(err))
+		}
+		return
+	}
+
+	d.logger.Warnf("[notifier] error while sending %s notification to user %s: %v",
+		deliveries[0].Channel, deliveries[0].UserID, err)
+
+	var next time.Time
+	if attempts+1 < d.maxAttempts {
+		next = now.Add(backoff(attempts + 1))
+	}
+	if err = d.store.RescheduleDeliveries(ctx, ids, next, err.Error()); err != nil {
+		d.logger.Error("[notifier] error while rescheduling deliveries", zap.Error(err))
+	}
+}
+
+// digestCutoff returns the last digest time, digest deliveries created before it are due
+func (d *Dispatcher) digestCutoff(now time.Time) time.Time {
+	cutoff := time.Date(now.Year(), now.Month(), now.Day(), d.digestHour, 0, 0, 0, now.Location())
+	if cutoff.After(now) {
+		cutoff = cutoff.AddDate(0, 0, -1)
+	}
+	return cutoff
+}
+
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// groupDeliveries joins digest deliveries of a user and a channel into one group,
+// other deliveries are sent one by one
+func groupDeliveries(deliveries []model.Delivery) [][]model.Delivery {
+	groups := make([][]model.Delivery, 0, len(deliveries))
+	digests := make(map[groupKey]int)
+	for _, v := range deliveries {
+		if !v.Digest {
+			groups = append(groups, []model.Delivery{v})
+			continue
+		}
+
+		key := groupKey{userID: v.UserID.String(), channel: v.Channel, address: v.Address}
+		if i, ok := digests[key]; ok {
+			groups[i] = append(groups[i], v)
+			continue
+		}
+		digests[key] = len(groups)
+		groups = append(groups, []model.Delivery{v})
+	}
+	return groups
+}
+
+func WithLogger(logger *zap.SugaredLogger) OptionFunc {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+func WithChannel(ch Channel) OptionFunc {
+	return func(d *Dispatcher) {
+		d.channels[ch.Name()] = ch
+	}
+}
+
+func WithInterval(interval time.Duration) OptionFunc {
+	return func(d *Dispatcher) {
+		if interval > 0 {
+			d.interval = interval
+		}
+	}
+}
+
+func WithDigestHour(hour int) OptionFunc {
+	return func(d *Dispatcher) {
+		d.digestHour = hour
+	}
+}
+
+func WithMaxAttempts(maxAttempts int) OptionFunc {
+	return func(d *Dispatcher) {
+		if maxAttempts > 0 {
+			d.maxAttempts = maxAttempts
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// memoryStore keeps the deliveries like the notification_deliveries table
+type memoryStore struct {
+	mu         sync.Mutex
+	deliveries map[int]*storedDelivery
+}
+
+type storedDelivery struct {
+	model.Delivery
+	status        model.DeliveryStatus
+	nextAttemptAt time.Time
+	sentAt        time.Time
+	lastError     string
+}
+
+func newMemoryStore(deliveries ...model.Delivery) *memoryStore {
+	s := &memoryStore{deliveries: make(map[int]*storedDelivery)}
+	for _, d := range deliveries {
+		s.deliveries[d.ID] = &storedDelivery{Delivery: d, status: model.DeliveryPending, nextAttemptAt: d.CreatedAt}
+	}
+	return s
+}
+
+func (s *memoryStore) ClaimDeliveries(_ context.Context, now, digestCutoff time.Time,
+	lease time.Duration, limit int) ([]model.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]model.Delivery, 0)
+	for _, d := range s.deliveries {
+		if d.status != model.DeliveryPending || d.nextAttemptAt.After(now) ||
+			d.Digest && d.CreatedAt.After(digestCutoff) {
+			continue
+		}
+		res = append(res, d.Delivery)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	for _, d := range res {
+		s.deliveries[d.ID].nextAttemptAt = now.Add(lease)
+	}
+	return res, nil
+}
+
+func (s *memoryStore) MarkDeliveriesSent(_ context.Context, ids []int, sentAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.deliveries[id].status = model.DeliverySent
+		s.deliveries[id].sentAt = sentAt
+	}
+	return nil
+}
+
+func (s *memoryStore) RescheduleDeliveries(_ context.Context, ids []int, nextAttemptAt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		d := s.deliveries[id]
+		d.Attempts++
+		d.lastError = lastError
+		if nextAttemptAt.IsZero() {
+			d.status = model.DeliveryFailed
+		} else {
+			d.nextAttemptAt = nextAttemptAt
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) get(id int) storedDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.deliveries[id]
+}
+
+// clock is the time of the dispatcher moved by the test
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	start := time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore(model.Delivery{ID: 1, UserID: uuid.New(), Channel: model.ChannelWebhook,
+		Address: "https://example.com/hook", Message: "hello", CreatedAt: start})
+	ch := NewFakeChannel(model.ChannelWebhook, nil)
+	ch.FailWith(errors.New("connection refused"))
+	c := &clock{now: start}
+	d := NewDispatcher(store, WithChannel(ch), WithMaxAttempts(5))
+	d.now = c.Now
+
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := store.get(1)
+	if got.status != model.DeliveryPending || got.Attempts != 1 || got.lastError != "connection refused" ||
+		!got.nextAttemptAt.Equal(start.Add(baseBackoff)) {
+		t.Fatalf("after the first failure %+v", got)
+	}
+
+	// the delivery is not retried before its back-off ends
+	c.now = start.Add(baseBackoff - time.Second)
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got = store.get(1); got.Attempts != 1 {
+		t.Fatalf("retried before the back-off: %+v", got)
+	}
+
+	c.now = start.Add(baseBackoff)
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got = store.get(1); got.Attempts != 2 || !got.nextAttemptAt.Equal(c.now.Add(2*baseBackoff)) {
+		t.Fatalf("after the second failure %+v, want the doubled back-off", got)
+	}
+
+	ch.FailWith(nil)
+	c.now = got.nextAttemptAt
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got = store.get(1); got.status != model.DeliverySent || !got.sentAt.Equal(c.now) {
+		t.Fatalf("after the recovery %+v", got)
+	}
+	sent := ch.Sent()
+	if len(sent) != 1 || sent[0].Address != "https://example.com/hook" || sent[0].Message.Text() != "hello" {
+		t.Errorf("sent %+v", sent)
+	}
+}
+
+func TestDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	start := time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC)
+	store := newMemoryStore(
+		model.Delivery{ID: 1, UserID: uuid.New(), Channel: model.ChannelWebhook, CreatedAt: start},
+		model.Delivery{ID: 2, UserID: uuid.New(), Channel: model.ChannelTelegram, CreatedAt: start},
+	)
+	ch := NewFakeChannel(model.ChannelWebhook, nil)
+	ch.FailWith(errors.New("timeout"))
+	c := &clock{now: start}
+	d := NewDispatcher(store, WithChannel(ch), WithMaxAttempts(2))
+	d.now = c.Now
+
+	for i := 0; i < 2; i++ {
+		if err := d.Dispatch(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		c.now = c.now.Add(maxBackoff)
+	}
+	if got := store.get(1); got.status != model.DeliveryFailed || got.Attempts != 2 {
+		t.Errorf("delivery after max attempts %+v", got)
+	}
+	// the channel which is not configured is not retried
+	if got := store.get(2); got.status != model.DeliveryFailed || got.Attempts != 1 {
+		t.Errorf("delivery to the channel which is not configured %+v", got)
+	}
+}
+
+func TestDispatcherSendsDigests(t *testing.T) {
+	var (
+		alice = uuid.New()
+		bob   = uuid.New()
+		day   = time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC)
+	)
+	store := newMemoryStore(
+		model.Delivery{ID: 1, UserID: alice, Channel: model.ChannelEmail, Address: "alice@example.com",
+			Digest: true, Message: "first", CreatedAt: day.Add(10 * time.Hour)},
+		model.Delivery{ID: 2, UserID: alice, Channel: model.ChannelEmail, Address: "alice@example.com",
+			Digest: true, Message: "second", CreatedAt: day.Add(12 * time.Hour)},
+		model.Delivery{ID: 3, UserID: bob, Channel: model.ChannelEmail, Address: "bob@example.com",
+			Digest: true, Message: "bob's", CreatedAt: day.Add(11 * time.Hour)},
+		model.Delivery{ID: 4, UserID: alice, Channel: model.ChannelEmail, Address: "alice@example.com",
+			Message: "now", CreatedAt: day.Add(12 * time.Hour)},
+		model.Delivery{ID: 5, UserID: alice, Channel: model.ChannelEmail, Address: "alice@example.com",
+			Digest: true, Message: "tomorrow", CreatedAt: day.Add(20 * time.Hour)},
+	)
+	ch := NewFakeChannel(model.ChannelEmail, nil)
+	c := &clock{now: day.Add(13 * time.Hour)}
+	d := NewDispatcher(store, WithChannel(ch), WithDigestHour(18))
+	d.now = c.Now
+
+	// the digests wait for the digest hour, the other deliveries are sent at once
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sent := ch.Sent()
+	if len(sent) != 1 || sent[0].Message.Digest || sent[0].Message.Items[0].Message != "now" {
+		t.Fatalf("sent before the digest hour %+v", sent)
+	}
+
+	c.now = day.Add(21 * time.Hour)
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	sent = ch.Sent()[1:]
+	if len(sent) != 2 {
+		t.Fatalf("sent at the digest hour %+v, want a digest for every user", sent)
+	}
+	digests := make(map[string][]string)
+	for _, msg := range sent {
+		if !msg.Message.Digest || msg.Message.Subject != "Сводка уведомлений" {
+			t.Errorf("message is not a digest: %+v", msg.Message)
+		}
+		for _, item := range msg.Message.Items {
+			digests[msg.Address] = append(digests[msg.Address], item.Message)
+		}
+	}
+	if got := digests["alice@example.com"]; len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("digest of alice %v", got)
+	}
+	if got := digests["bob@example.com"]; len(got) != 1 || got[0] != "bob's" {
+		t.Errorf("digest of bob %v", got)
+	}
+	if got := store.get(5); got.status != model.DeliveryPending {
+		t.Errorf("the delivery after the digest hour is sent: %+v", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  baseBackoff,
+		2:  2 * baseBackoff,
+		4:  8 * baseBackoff,
+		20: maxBackoff,
+	}
+	for attempt, want := range tests {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
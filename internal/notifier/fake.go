@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"go.uber.org/zap"
+)
+
+type SentMessage struct {
+	Address string
+	Message Message
+}
+
+// FakeChannel records the messages instead of sending them. It is used in tests
+// and in development instead of the channels which are not configured.
+type FakeChannel struct {
+	name   model.DeliveryChannel
+	logger *zap.SugaredLogger
+	err    error
+
+	mu   sync.Mutex
+	sent []SentMessage
+}
+
+func NewFakeChannel(name model.DeliveryChannel, logger *zap.SugaredLogger) *FakeChannel {
+	return &FakeChannel{
+		name:   name,
+		logger: logger,
+	}
+}
+
+func (ch *FakeChannel) Name() model.DeliveryChannel {
+	return ch.name
+}
+
+func (ch *FakeChannel) Send(_ context.Context, address string, msg Message) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if ch.err != nil {
+		return ch.err
+	}
+	ch.sent = append(ch.sent, SentMessage{Address: address, Message: msg})
+	if ch.logger != nil {
+		ch.logger.Infof("[notifier] fake %s message to %q: %s", ch.name, address, msg.Text())
+	}
+	return nil
+}
+
+// FailWith makes the following sends return err, nil restores them
+func (ch *FakeChannel) FailWith(err error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.err = err
+}
+
+func (ch *FakeChannel) Sent() []SentMessage {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return append([]SentMessage(nil), ch.sent...)
+}
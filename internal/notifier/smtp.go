@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+// smtpTimeout bounds the whole conversation with the mail server
+const smtpTimeout = 30 * time.Second
+
+type SMTPChannel struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPChannel(host string, port int, username, password, from string) *SMTPChannel {
+	ch := &SMTPChannel{
+		host: host,
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+	}
+	if username != "" {
+		ch.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return ch
+}
+
+func (ch *SMTPChannel) Name() model.DeliveryChannel {
+	return model.ChannelEmail
+}
+
+func (ch *SMTPChannel) Send(ctx context.Context, address string, msg Message) error {
+	if address == "" {
+		return fmt.Errorf("email address is empty")
+	}
+
+	b := strings.Builder{}
+	b.WriteString("From: " + ch.from + "\r\n")
+	b.WriteString("To: " + address + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text(), "\n", "\r\n"))
+
+	if err := ch.sendMail(ctx, address, b.String()); err != nil {
+		return fmt.Errorf("error while sending email: %w", err)
+	}
+	return nil
+}
+
+// sendMail does what smtp.SendMail does, but the connection is dialed with ctx
+// and the server is given no more than smtpTimeout to answer
+func (ch *SMTPChannel) sendMail(ctx context.Context, address, msg string) error {
+	dialer := net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", ch.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(smtpTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, ch.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: ch.host}); err != nil {
+			return err
+		}
+	}
+	if ch.auth != nil {
+		if err = client.Auth(ch.auth); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(ch.from); err != nil {
+		return err
+	}
+	if err = client.Rcpt(address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write([]byte(msg)); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSMTPChannelSendStopsAtDeadline(t *testing.T) {
+	// the server accepts the connection and never greets the client
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	ch := NewSMTPChannel(addr.IP.String(), addr.Port, "", "", "noreply@example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err = ch.Send(ctx, "user@example.com", Message{Subject: "test"}); err == nil {
+		t.Fatal("Send() to the silent server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Send() returned after %s, want it to stop at the deadline of ctx", elapsed)
+	}
+}
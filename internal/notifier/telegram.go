@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+type TelegramChannel struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewTelegramChannel creates a channel which sends messages through the telegram bot API,
+// an empty baseURL means the public API
+func NewTelegramChannel(baseURL, token string, client *http.Client) *TelegramChannel {
+	if baseURL == "" {
+		baseURL = telegramAPIURL
+	}
+	return &TelegramChannel{
+		baseURL: baseURL,
+		token:   token,
+		client:  client,
+	}
+}
+
+func (ch *TelegramChannel) Name() model.DeliveryChannel {
+	return model.ChannelTelegram
+}
+
+func (ch *TelegramChannel) Send(ctx context.Context, address string, msg Message) error {
+	if address == "" {
+		return fmt.Errorf("telegram chat id is empty")
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"chat_id": address,
+		"text":    msg.Text(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/bot%s/sendMessage", ch.baseURL, ch.token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ch.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error while sending telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result := struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("error while decoding telegram response (status %d): %w", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram api error (status %d): %s", resp.StatusCode, result.Description)
+	}
+	return nil
+}
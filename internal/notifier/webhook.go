@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+const (
+	SignatureHeader = "X-Signature-256"
+
+	webhookMaxRedirects = 10
+)
+
+var errWebhookAddress = errors.New("webhook address is not allowed")
+
+// WebhookChannel posts messages as JSON to the user's url. If a secret is set
+// the body is signed with HMAC-SHA256 in the SignatureHeader.
+type WebhookChannel struct {
+	secret string
+	client *http.Client
+}
+
+// NewWebhookChannel keeps only the timeout of client. The urls are set by the users, so only http and https
+// are sent and the loopback, the link-local and the private addresses are refused when they are dialed,
+// the names resolving to them and the redirects to them included.
+func NewWebhookChannel(secret string, client *http.Client) *WebhookChannel {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicAddressOnly,
+	}
+	webhookClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= webhookMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", webhookMaxRedirects)
+			}
+			return checkWebhookURL(req.URL)
+		},
+	}
+	if client != nil {
+		webhookClient.Timeout = client.Timeout
+	}
+	return &WebhookChannel{
+		secret: secret,
+		client: webhookClient,
+	}
+}
+
+func (ch *WebhookChannel) Name() model.DeliveryChannel {
+	return model.ChannelWebhook
+}
+
+func (ch *WebhookChannel) Send(ctx context.Context, address string, msg Message) error {
+	if address == "" {
+		return fmt.Errorf("webhook url is empty")
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("webhook url is not valid: %w", err)
+	}
+	if err = checkWebhookURL(u); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if ch.secret != "" {
+		mac := hmac.New(sha256.New, []byte(ch.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := ch.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error while sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func checkWebhookURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %s", errWebhookAddress, u.Redacted())
+	}
+	return nil
+}
+
+// publicAddressOnly is called with the resolved address, so the names cannot point to the internal services
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", errWebhookAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookChannelSend(t *testing.T) {
+	var received Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if r.Header.Get(SignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			t.Errorf("body is not signed")
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	// the test server listens on the loopback, so the client without the address check is used
+	ch := &WebhookChannel{secret: "secret", client: server.Client()}
+	msg := Message{Subject: "Новое уведомление", Items: []Item{{Message: "hello"}}}
+	if err := ch.Send(context.Background(), server.URL, msg); err != nil {
+		t.Fatal(err)
+	}
+	if received.Subject != msg.Subject || len(received.Items) != 1 || received.Items[0].Message != "hello" {
+		t.Errorf("received %+v", received)
+	}
+}
+
+func TestWebhookChannelRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("internal address is called: %s", r.URL)
+	}))
+	defer server.Close()
+
+	ch := NewWebhookChannel("", nil)
+	for _, address := range []string{
+		server.URL,
+		"http://localhost:1/hook",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"ftp://example.com/hook",
+		"file:///etc/passwd",
+		"/hook",
+	} {
+		if err := ch.Send(context.Background(), address, Message{}); !errors.Is(err, errWebhookAddress) {
+			t.Errorf("Send(%q) error = %v, want %v", address, err, errWebhookAddress)
+		}
+	}
+}
+
+func TestWebhookChannelChecksRedirects(t *testing.T) {
+	ch := NewWebhookChannel("", nil)
+	// the redirects to the internal addresses are refused by the dialer, the schemes are checked here
+	req := httptest.NewRequest(http.MethodPost, "gopher://example.com/", nil)
+	if err := ch.client.CheckRedirect(req, nil); !errors.Is(err, errWebhookAddress) {
+		t.Errorf("redirect to gopher error = %v, want %v", err, errWebhookAddress)
+	}
+	req = httptest.NewRequest(http.MethodPost, "https://example.com/", nil)
+	if err := ch.client.CheckRedirect(req, nil); err != nil {
+		t.Errorf("redirect to https error = %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// GetCommitWeeks returns the synced weekly statistics of every contributor of every repository of the projects
+func (r *Repository) GetCommitWeeks(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error) {
+	return r.getActivityCounts(ctx, r.sq.Select(
+		"project_id", "repository_id",
+		"github_username", "NULL",
+		"week_start", "commits",
+		"additions", "deletions",
+		"0").
+		From("contributor_stats").
+		Where("project_id = ANY (?)", projectIDsArray(projectIDs)))
+}
+
+// GetCommitDays counts the commits received by the webhooks by days, the lines of the commits are unknown
+func (r *Repository) GetCommitDays(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error) {
+	return r.getActivityCounts(ctx, r.sq.Select(
+		"project_id", "repository_id",
+		"github_username", "NULL",
+		"date_trunc('day', occurred_at) AS day", "COUNT(*)",
+		"0", "0",
+		"0").
+		From("repository_events").
+		Where(sq.Eq{"kind": model.EventCommit}).
+		Where("project_id = ANY (?)", projectIDsArray(projectIDs)).
+		GroupBy("project_id", "repository_id", "github_username", "day"))
+}
+
+// GetTaskDoneDays counts the tasks done by their assignees by days, approved or not
+func (r *Repository) GetTaskDoneDays(ctx context.Context, projectIDs []int) ([]model.ActivityCount, error) {
+	return r.getActivityCounts(ctx, r.sq.Select(
+		"t.project_id", "0",
+		"''", "p.user_id",
+		"date_trunc('day', t.done_at) AS day", "0",
+		"0", "0",
+		"COUNT(*)").
+		From("tasks t").
+		Join("participants p ON p.id = t.participant_id").
+		Where(sq.Eq{"t.status": model.Done}).
+		Where(sq.NotEq{"t.done_at": nil}).
+		Where("t.project_id = ANY (?)", projectIDsArray(projectIDs)).
+		GroupBy("t.project_id", "p.user_id", "day"))
+}
+
+func (r *Repository) getActivityCounts(ctx context.Context, query sq.SelectBuilder) ([]model.ActivityCount, error) {
+	rows, err := query.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	res := make([]model.ActivityCount, 0)
+	for rows.Next() {
+		count := model.ActivityCount{}
+		if err = rows.Scan(
+			&count.ProjectID, &count.RepositoryID,
+			&count.Login, &count.UserID,
+			&count.Start, &count.Commits,
+			&count.Additions, &count.Deletions,
+			&count.TasksDone,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		count.Start = count.Start.UTC()
+		res = append(res, count)
+	}
+	return res, nil
+}
+
+func projectIDsArray(projectIDs []int) pq.Int64Array {
+	ids := make(pq.Int64Array, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, int64(id))
+	}
+	return ids
+}
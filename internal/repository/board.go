@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetBoardColumns(ctx context.Context, projectID int) ([]model.BoardColumn, error) {
+	rows, err := r.sq.Select("bc.status", "bc.wip_limit").
+		From("board_columns bc").
+		Where(sq.Eq{"bc.project_id": projectID}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	columns := make([]model.BoardColumn, 0)
+	for rows.Next() {
+		column := model.BoardColumn{}
+		if err = rows.Scan(&column.Status, &column.WIPLimit); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func (r *Repository) UpsertBoardColumn(ctx context.Context, projectID int, column *model.BoardColumn) error {
+	_, err := r.sq.Insert("board_columns").
+		Columns("project_id", "status", "wip_limit").
+		Values(projectID, column.Status, column.WIPLimit).
+		Suffix("ON CONFLICT (project_id, status) DO UPDATE SET wip_limit = EXCLUDED.wip_limit").
+		ExecContext(ctx)
+	return err
+}
+
+// MoveTask changes status and rank of the task in one transaction.
+// The task row and the target column are locked, so concurrent moves are serialized
+// and the rank is taken between the neighbours the other moves left. The move is
+// rejected if the task was changed since the client had read it (version differs)
+// or if the target column is already full.
+func (r *Repository) MoveTask(ctx context.Context, move *model.TaskMove) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	var (
+		status  model.TaskStatus
+		version int
+	)
+	if err = r.sq.Select("status", "version").
+		From("tasks").
+		Where(sq.Eq{"id": move.TaskID, "project_id": move.ProjectID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).Scan(&status, &version); errors.Is(err, sql.ErrNoRows) {
+		return ierr.ErrTaskNotFound
+	} else if err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if version != move.Version {
+		return ierr.ErrTaskVersionConflict
+	}
+
+	if err = r.lockBoardColumn(ctx, tx, move.ProjectID, move.Status); err != nil {
+		return err
+	}
+	if status != move.Status {
+		if err = r.checkWIPLimit(ctx, tx, move.ProjectID, move.Status, 1); err != nil {
+			return err
+		}
+	}
+	rank, err := r.columnRank(ctx, tx, move)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if _, err = r.sq.Update("tasks").
+		SetMap(map[string]interface{}{
+			"status":     move.Status,
+			"rank":       rank,
+			"version":    sq.Expr("version + 1"),
+			"updated_at": now,
+			"done_at":    doneAt(move.Status, now),
+		}).Where(sq.Eq{"id": move.TaskID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating task: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// lockBoardColumn locks the column of the project until the end of the transaction,
+// the column without a row yet gets one without a limit
+func (r *Repository) lockBoardColumn(ctx context.Context, tx *sql.Tx, projectID int, status model.TaskStatus) error {
+	if _, err := r.sq.Insert("board_columns").
+		Columns("project_id", "status").
+		Values(projectID, status).
+		Suffix("ON CONFLICT (project_id, status) DO NOTHING").
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while inserting board column: %w", err)
+	}
+
+	var locked model.TaskStatus
+	if err := r.sq.Select("status").
+		From("board_columns").
+		Where(sq.Eq{"project_id": projectID, "status": status}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).Scan(&locked); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return nil
+}
+
+// columnRank returns the rank between the neighbours of the moved task at its position in the column.
+// The moved task itself is not a neighbour when it is moved within its column.
+func (r *Repository) columnRank(ctx context.Context, tx *sql.Tx, move *model.TaskMove) (string, error) {
+	rows, err := r.sq.Select("rank").
+		From("tasks").
+		Where(sq.Eq{"project_id": move.ProjectID, "status": move.Status}).
+		Where(sq.NotEq{"id": move.TaskID}).
+		OrderBy("rank", "id").
+		RunWith(tx).
+		QueryContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	ranks := make([]string, 0)
+	for rows.Next() {
+		var rank string
+		if err = rows.Scan(&rank); err != nil {
+			return "", fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		ranks = append(ranks, rank)
+	}
+
+	position := move.Position
+	if position < 0 || position > len(ranks) {
+		position = len(ranks)
+	}
+	var prev, next string
+	if position > 0 {
+		prev = ranks[position-1]
+	}
+	if position < len(ranks) {
+		next = ranks[position]
+	}
+	return model.RankBetween(prev, next), nil
+}
+
+// checkWIPLimit tells if the column of the project has room for the moved tasks,
+// the limit is locked until the end of the transaction
+func (r *Repository) checkWIPLimit(ctx context.Context, tx *sql.Tx, projectID int, status model.TaskStatus, moved int) error {
+	var wipLimit sql.NullInt64
+	if err := r.sq.Select("wip_limit").
+		From("board_columns").
+		Where(sq.Eq{"project_id": projectID, "status": status}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).Scan(&wipLimit); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	if !wipLimit.Valid {
+		return nil
+	}
+
+	var count int64
+	if err := r.sq.Select("COUNT(1)").
+		From("tasks").
+		Where(sq.Eq{"project_id": projectID, "status": status}).
+		RunWith(tx).
+		QueryRowContext(ctx).Scan(&count); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	if count+int64(moved) > wipLimit.Int64 {
+		return ierr.ErrWIPLimitExceeded
+	}
+	return nil
+}
+
+// checkTaskStatusChanges checks the work in progress limits of the columns the tasks move to
+func (r *Repository) checkTaskStatusChanges(ctx context.Context, tx *sql.Tx, tasks []model.Task) error {
+	ids := make(pq.Int64Array, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, int64(task.ID))
+	}
+	rows, err := r.sq.Select("id", "status").
+		From("tasks").
+		Where("id = ANY (?)", ids).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while performing sql request: %w", err)
+	}
+	statuses := make(map[int]model.TaskStatus, len(tasks))
+	for rows.Next() {
+		var (
+			id     int
+			status model.TaskStatus
+		)
+		if err = rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		statuses[id] = status
+	}
+	if err = rows.Close(); err != nil {
+		r.logger.Error("error while closing sql rows", zap.Error(err))
+	}
+
+	type column struct {
+		projectID int
+		status    model.TaskStatus
+	}
+	moved := make(map[column]int)
+	for _, task := range tasks {
+		if status, ok := statuses[task.ID]; ok && status != task.Status {
+			moved[column{task.ProjectID, task.Status}]++
+		}
+	}
+	for c, count := range moved {
+		if err = r.checkWIPLimit(ctx, tx, c.projectID, c.status, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTaskInserts checks the work in progress limits of the columns the new tasks are put to,
+// the backlog is where the new tasks go, so it takes them without a limit
+func (r *Repository) checkTaskInserts(ctx context.Context, tx *sql.Tx, tasks []model.Task) error {
+	type column struct {
+		projectID int
+		status    model.TaskStatus
+	}
+	inserted := make(map[column]int)
+	for _, task := range tasks {
+		if task.Status != model.TODO {
+			inserted[column{task.ProjectID, task.Status}]++
+		}
+	}
+	for c, count := range inserted {
+		if err := r.checkWIPLimit(ctx, tx, c.projectID, c.status, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
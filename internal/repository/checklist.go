@@ -4,77 +4,321 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
-	sq "github.com/Masterminds/squirrel"
-
+	"be-project-monitoring/internal/db"
 	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
 )
 
-func (r *Repository) GetProjectChecklist(ctx context.Context, id int) ([]model.Checklist, error) {
+func (r *Repository) GetChecklist(ctx context.Context, filter *ChecklistFilter) (*model.Checklist, error) {
+	checklists, err := r.GetChecklists(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get checklist: %w", err)
+	case len(checklists) == 0:
+		return nil, ierr.ErrChecklistNotFound
+	default:
+		return &checklists[0], nil
+	}
+}
+
+// GetChecklists returns the checklists in order, with their items
+func (r *Repository) GetChecklists(ctx context.Context, filter *ChecklistFilter) ([]model.Checklist, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
 	rows, err := r.sq.Select(
-		"id",
-		"name",
-		"project_id",
-		"checked").
-		From("checklist").
-		Where(sq.Eq{"project_id": id}).
+		"l.id", "l.project_id",
+		"l.name", "l.position",
+		"l.created_at", "l.template_id").
+		From("checklists l").
+		Where(conditionsFromChecklistFilter(filter)).
+		OrderBy("l.position", "l.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
 		QueryContext(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
 	}
 
-	var checklist []model.Checklist
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
 
+	checklists := make([]model.Checklist, 0)
+	index := make(map[int]int)
 	for rows.Next() {
-		item := model.Checklist{}
-		if err = rows.Scan(&item.ID, &item.Name,
-			&item.ProjectID, &item.Checked); err != nil && !errors.Is(err, sql.ErrNoRows) {
-			return nil, err
+		checklist := model.Checklist{}
+		if err = rows.Scan(
+			&checklist.ID, &checklist.ProjectID,
+			&checklist.Name, &checklist.Position,
+			&checklist.CreatedAt, &checklist.TemplateID,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
 		}
-		checklist = append(checklist, item)
+		checklist.Items = make([]model.ChecklistItem, 0)
+		index[checklist.ID] = len(checklists)
+		checklists = append(checklists, checklist)
+	}
+	if len(checklists) == 0 {
+		return checklists, nil
 	}
 
-	return checklist, nil
+	ids := make([]int64, 0, len(checklists))
+	for _, checklist := range checklists {
+		ids = append(ids, int64(checklist.ID))
+	}
+	items, err := r.getChecklistItems(ctx, sq.Expr("i.checklist_id = ANY (?)", pq.Int64Array(ids)))
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		i := index[item.ChecklistID]
+		checklists[i].Items = append(checklists[i].Items, item)
+	}
+	return checklists, nil
 }
 
-func (r *Repository) AddProjectChecklist(ctx context.Context, id int, checklist []model.Checklist) ([]model.Checklist, error) {
-	q := r.sq.Insert("checklist").
-		Columns("name",
-			"project_id",
-			"checked")
-
-	for _, v := range checklist {
-		q = q.Values(
-			v.Name,
-			id,
-			v.Checked,
-		)
+func (r *Repository) GetChecklistItem(ctx context.Context, filter *ChecklistItemFilter) (*model.ChecklistItem, error) {
+	items, err := r.GetChecklistItems(ctx, filter)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	case len(items) == 0:
+		return nil, ierr.ErrChecklistItemNotFound
+	default:
+		return &items[0], nil
 	}
+}
 
-	if _, err := q.ExecContext(ctx); err != nil {
-		return nil, err
+// GetChecklistItems returns the items of all the matching checklists in order of the checklists
+func (r *Repository) GetChecklistItems(ctx context.Context, filter *ChecklistItemFilter) ([]model.ChecklistItem, error) {
+	return r.getChecklistItems(ctx, conditionsFromChecklistItemFilter(filter))
+}
+
+func (r *Repository) getChecklistItems(ctx context.Context, conditions sq.Sqlizer) ([]model.ChecklistItem, error) {
+	rows, err := r.sq.Select(
+		"i.id", "i.checklist_id",
+		"i.project_id", "i.name",
+		"i.position", "i.checked",
+		"i.participant_id", "i.due_date",
+		"i.locked", "i.checked_by",
+		"i.checked_at", "i.template_item_id").
+		From("checklist i").
+		Join("checklists l ON l.id = i.checklist_id").
+		Where(conditions).
+		OrderBy("l.position", "l.id", "i.position", "i.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
 	}
 
-	return r.GetProjectChecklist(ctx, id)
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	items := make([]model.ChecklistItem, 0)
+	for rows.Next() {
+		item := model.ChecklistItem{}
+		if err = rows.Scan(
+			&item.ID, &item.ChecklistID,
+			&item.ProjectID, &item.Name,
+			&item.Position, &item.Checked,
+			&item.ParticipantID, &item.DueDate,
+			&item.Locked, &item.CheckedBy,
+			&item.CheckedAt, &item.TemplateItemID,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
 }
 
-func (r *Repository) UpdateProjectChecklist(ctx context.Context, id int, checklist *model.Checklist) ([]model.Checklist, error) {
-	if _, err := r.sq.Update("checklist").
-		Set("checked", checklist.Checked).
-		Where(sq.Eq{"id": checklist.ID}).
-		ExecContext(ctx); err != nil {
-		return nil, err
+// InsertChecklist saves the checklist with its items in one transaction
+func (r *Repository) InsertChecklist(ctx context.Context, checklist *model.Checklist) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.sq.Insert("checklists").
+		Columns("project_id", "name",
+			"position", "created_at",
+			"template_id").
+		Values(checklist.ProjectID, checklist.Name,
+			checklist.Position, checklist.CreatedAt,
+			checklist.TemplateID).
+		Suffix("RETURNING \"id\"").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&checklist.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
 	}
 
-	return r.GetProjectChecklist(ctx, id)
+	for i := range checklist.Items {
+		checklist.Items[i].ChecklistID = checklist.ID
+	}
+	if err = r.insertChecklistItems(ctx, tx, checklist.Items); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *Repository) DeleteProjectChecklist(ctx context.Context, id int, itemID int) ([]model.Checklist, error) {
-	if _, err := r.sq.Delete("checklist").
-		Where(sq.Eq{"id": itemID}).
+// UpdateChecklist saves the name and the position of the checklist and the order of its items
+func (r *Repository) UpdateChecklist(ctx context.Context, checklist *model.Checklist) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Update("checklists").
+		SetMap(map[string]interface{}{
+			"name":     checklist.Name,
+			"position": checklist.Position,
+		}).Where(sq.Eq{"id": checklist.ID}).
+		RunWith(tx).
 		ExecContext(ctx); err != nil {
-		return nil, err
+		return fmt.Errorf("error while updating checklist: %w", err)
+	}
+
+	for _, item := range checklist.Items {
+		if _, err = r.sq.Update("checklist").
+			Set("position", item.Position).
+			Where(sq.Eq{"id": item.ID, "checklist_id": checklist.ID}).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while updating checklist item %v: %w", item.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) DeleteChecklist(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("checklists").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) InsertChecklistItems(ctx context.Context, items []model.ChecklistItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.insertChecklistItems(ctx, tx, items); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) insertChecklistItems(ctx context.Context, tx *sql.Tx, items []model.ChecklistItem) error {
+	for i := range items {
+		item := &items[i]
+		if err := r.sq.Insert("checklist").
+			SetMap(checklistItemValues(item)).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&item.ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) UpdateChecklistItem(ctx context.Context, item *model.ChecklistItem) error {
+	_, err := r.sq.Update("checklist").
+		SetMap(checklistItemValues(item)).
+		Where(sq.Eq{"id": item.ID}).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) DeleteChecklistItem(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("checklist").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
+
+// GetChecklistProgress counts the items of all the checklists of every project,
+// projects without items are missing from the result
+func (r *Repository) GetChecklistProgress(ctx context.Context, projectIDs []int) (map[int]model.ChecklistProgress, error) {
+	res := make(map[int]model.ChecklistProgress, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return res, nil
 	}
 
-	return r.GetProjectChecklist(ctx, id)
+	ids := make([]int64, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, int64(id))
+	}
+	rows, err := r.sq.Select(
+		"project_id",
+		"COUNT(*)",
+		"COUNT(*) FILTER (WHERE checked)").
+		From("checklist").
+		Where("project_id = ANY (?)", pq.Int64Array(ids)).
+		GroupBy("project_id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			projectID int
+			progress  model.ChecklistProgress
+		)
+		if err = rows.Scan(&projectID, &progress.Total, &progress.Checked); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		res[projectID] = progress
+	}
+	return res, nil
+}
+
+func checklistItemValues(item *model.ChecklistItem) map[string]interface{} {
+	return map[string]interface{}{
+		"checklist_id":     item.ChecklistID,
+		"project_id":       item.ProjectID,
+		"name":             item.Name,
+		"position":         item.Position,
+		"checked":          item.Checked,
+		"participant_id":   item.ParticipantID,
+		"due_date":         item.DueDate,
+		"locked":           item.Locked,
+		"checked_by":       item.CheckedBy,
+		"checked_at":       item.CheckedAt,
+		"template_item_id": item.TemplateItemID,
+	}
 }
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetChecklistTemplate(ctx context.Context, filter *ChecklistTemplateFilter) (*model.ChecklistTemplate, error) {
+	templates, err := r.GetChecklistTemplates(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	case len(templates) == 0:
+		return nil, ierr.ErrChecklistTemplateNotFound
+	default:
+		return &templates[0], nil
+	}
+}
+
+func (r *Repository) GetChecklistTemplates(ctx context.Context, filter *ChecklistTemplateFilter) ([]model.ChecklistTemplate, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"t.id", "t.owner_id",
+		"t.name", "t.course",
+		"t.created_at", "t.updated_at").
+		From("checklist_templates t").
+		Where(conditionsFromChecklistTemplateFilter(filter)).
+		OrderBy("t.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	templates := make([]model.ChecklistTemplate, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		template := model.ChecklistTemplate{}
+		if err = rows.Scan(
+			&template.ID, &template.OwnerID,
+			&template.Name, &template.Course,
+			&template.CreatedAt, &template.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		template.Items = make([]model.ChecklistTemplateItem, 0)
+		index[template.ID] = len(templates)
+		templates = append(templates, template)
+	}
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	ids := make([]int64, 0, len(templates))
+	for _, template := range templates {
+		ids = append(ids, int64(template.ID))
+	}
+	if err = r.fillChecklistTemplateItems(ctx, ids, templates, index); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *Repository) fillChecklistTemplateItems(ctx context.Context, ids []int64, templates []model.ChecklistTemplate, index map[int]int) error {
+	rows, err := r.sq.Select(
+		"i.id", "i.template_id",
+		"i.name", "i.position",
+		"i.locked", "i.due_date").
+		From("checklist_template_items i").
+		Where("i.template_id = ANY (?)", pq.Int64Array(ids)).
+		OrderBy("i.position", "i.id").
+		QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		item := model.ChecklistTemplateItem{}
+		if err = rows.Scan(
+			&item.ID, &item.TemplateID,
+			&item.Name, &item.Position,
+			&item.Locked, &item.DueDate,
+		); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		i := index[item.TemplateID]
+		templates[i].Items = append(templates[i].Items, item)
+	}
+	return nil
+}
+
+// InsertChecklistTemplate saves the template with its items in one transaction
+func (r *Repository) InsertChecklistTemplate(ctx context.Context, template *model.ChecklistTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.sq.Insert("checklist_templates").
+		Columns("owner_id", "name",
+			"course", "created_at",
+			"updated_at").
+		Values(template.OwnerID, template.Name,
+			template.Course, template.CreatedAt,
+			template.UpdatedAt).
+		Suffix("RETURNING \"id\"").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&template.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if err = r.saveChecklistTemplateItems(ctx, tx, template); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateChecklistTemplate saves the template in one transaction, the items missing from it are deleted.
+// If propagate is set the checklists made of the template follow it: the items of the deleted template
+// items are deleted, the others get the name, the position, the lock and the due date of their template
+// item and the new items are added. Checked state, assignees and items added to a project are kept.
+func (r *Repository) UpdateChecklistTemplate(ctx context.Context, template *model.ChecklistTemplate, propagate bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Update("checklist_templates").
+		SetMap(map[string]interface{}{
+			"name":       template.Name,
+			"course":     template.Course,
+			"updated_at": template.UpdatedAt,
+		}).Where(sq.Eq{"id": template.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating checklist template: %w", err)
+	}
+
+	kept := make([]int, 0, len(template.Items))
+	for _, item := range template.Items {
+		if item.ID != 0 {
+			kept = append(kept, item.ID)
+		}
+	}
+	removed := r.sq.Select("id").
+		From("checklist_template_items").
+		Where(sq.Eq{"template_id": template.ID}).
+		Where(sq.NotEq{"id": kept})
+	if propagate {
+		query, args, err := removed.PlaceholderFormat(sq.Question).ToSql()
+		if err != nil {
+			return fmt.Errorf("error while building sql request: %w", err)
+		}
+		if _, err = r.sq.Delete("checklist").
+			Where("template_item_id IN ("+query+")", args...).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while deleting checklist items: %w", err)
+		}
+	}
+	if _, err = r.sq.Delete("checklist_template_items").
+		Where(sq.Eq{"template_id": template.ID}).
+		Where(sq.NotEq{"id": kept}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting checklist template items: %w", err)
+	}
+
+	if err = r.saveChecklistTemplateItems(ctx, tx, template); err != nil {
+		return err
+	}
+	if propagate {
+		if err = r.propagateChecklistTemplate(ctx, tx, template); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) saveChecklistTemplateItems(ctx context.Context, tx *sql.Tx, template *model.ChecklistTemplate) error {
+	for i := range template.Items {
+		item := &template.Items[i]
+		item.TemplateID = template.ID
+		values := map[string]interface{}{
+			"template_id": item.TemplateID,
+			"name":        item.Name,
+			"position":    item.Position,
+			"locked":      item.Locked,
+			"due_date":    item.DueDate,
+		}
+
+		if item.ID != 0 {
+			if _, err := r.sq.Update("checklist_template_items").
+				SetMap(values).
+				Where(sq.Eq{"id": item.ID, "template_id": template.ID}).
+				RunWith(tx).
+				ExecContext(ctx); err != nil {
+				return fmt.Errorf("error while updating checklist template item %v: %w", item.ID, err)
+			}
+			continue
+		}
+
+		if err := r.sq.Insert("checklist_template_items").
+			SetMap(values).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&item.ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) propagateChecklistTemplate(ctx context.Context, tx *sql.Tx, template *model.ChecklistTemplate) error {
+	if _, err := r.sq.Update("checklists").
+		Set("name", template.Name).
+		Where(sq.Eq{"template_id": template.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating checklists: %w", err)
+	}
+
+	for _, item := range template.Items {
+		if _, err := r.sq.Update("checklist").
+			SetMap(map[string]interface{}{
+				"name":     item.Name,
+				"position": item.Position,
+				"locked":   item.Locked,
+				"due_date": item.DueDate,
+			}).Where(sq.Eq{"template_item_id": item.ID}).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while updating checklist items of template item %v: %w", item.ID, err)
+		}
+	}
+
+	if _, err := r.sq.Insert("checklist").
+		Columns("checklist_id", "project_id",
+			"name", "position",
+			"locked", "due_date",
+			"template_item_id").
+		Select(sq.Select(
+			"l.id", "l.project_id",
+			"ti.name", "ti.position",
+			"ti.locked", "ti.due_date",
+			"ti.id").
+			From("checklists l").
+			Join("checklist_template_items ti ON ti.template_id = l.template_id").
+			Where(sq.Eq{"l.template_id": template.ID}).
+			Where("NOT EXISTS (SELECT 1 FROM checklist i WHERE i.checklist_id = l.id AND i.template_item_id = ti.id)")).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while inserting checklist items: %w", err)
+	}
+	return nil
+}
+
+// DeleteChecklistTemplate deletes the template, the checklists made of it are kept in the projects
+func (r *Repository) DeleteChecklistTemplate(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("checklist_templates").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"go.uber.org/zap"
+)
+
+// deadlineLockKey is the advisory lock which lets only one replica claim reminders at a time
+const deadlineLockKey = 7301
+
+// ClaimDeadlineReminders returns the reminders about the deadlines which are due in daysBefore
+// days or were missed during the last overdueDays days and records them, so every reminder
+// is returned once. Nothing is returned while another replica holds the lock.
+func (r *Repository) ClaimDeadlineReminders(ctx context.Context, today time.Time,
+	daysBefore, overdueDays int) ([]model.DeadlineReminder, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", deadlineLockKey).
+		Scan(&locked); err != nil {
+		return nil, fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `WITH due AS (
+					SELECT p.id project_id, NULL::BIGINT task_id, p.active_to due_date
+					FROM projects p
+					WHERE p.active_to BETWEEN $1::DATE - $3::INT AND $1::DATE + $2::INT
+					UNION ALL
+					SELECT t.project_id, t.id, t.due_date
+					FROM tasks t
+					WHERE t.status <> $4
+					  AND t.due_date BETWEEN $1::DATE - $3::INT AND $1::DATE + $2::INT
+				)
+				INSERT INTO deadline_reminders (project_id, task_id, kind, due_date)
+				SELECT project_id, task_id,
+					   CASE WHEN due_date < $1::DATE THEN $5 ELSE $6 END,
+					   due_date
+				FROM due
+				ON CONFLICT DO NOTHING
+				RETURNING project_id, task_id, kind, due_date`,
+		today, daysBefore, overdueDays, model.Done, model.DeadlineOverdue, model.DeadlineUpcoming)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	reminders := make([]model.DeadlineReminder, 0)
+	for rows.Next() {
+		reminder := model.DeadlineReminder{}
+		if err = rows.Scan(&reminder.ProjectID, &reminder.TaskID,
+			&reminder.Kind, &reminder.DueDate); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("error while closing sql rows: %w", err)
+	}
+
+	return reminders, tx.Commit()
+}
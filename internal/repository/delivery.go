@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetUserChannels(ctx context.Context, userID uuid.UUID) ([]model.UserChannel, error) {
+	rows, err := r.sq.Select("uc.channel", "uc.address", "uc.enabled", "uc.digest").
+		From("user_channels uc").
+		Where(sq.Eq{"uc.user_id": userID}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	channels := make([]model.UserChannel, 0)
+	for rows.Next() {
+		channel := model.UserChannel{}
+		if err = rows.Scan(&channel.Channel, &channel.Address,
+			&channel.Enabled, &channel.Digest); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+func (r *Repository) UpsertUserChannels(ctx context.Context, userID uuid.UUID, channels []model.UserChannel) error {
+	if len(channels) == 0 {
+		return nil
+	}
+
+	q := r.sq.Insert("user_channels").
+		Columns("user_id", "channel", "address", "enabled", "digest")
+	for _, v := range channels {
+		q = q.Values(userID, v.Channel, v.Address, v.Enabled, v.Digest)
+	}
+
+	_, err := q.Suffix(`ON CONFLICT (user_id, channel) DO UPDATE
+		SET address = EXCLUDED.address, enabled = EXCLUDED.enabled, digest = EXCLUDED.digest`).
+		ExecContext(ctx)
+	return err
+}
+
+// ClaimDeliveries takes the pending deliveries which are due and hides them from
+// other dispatchers for the lease time. Digest deliveries are due only if they were
+// created before digestCutoff, so they are collected until the digest time.
+func (r *Repository) ClaimDeliveries(ctx context.Context, now, digestCutoff time.Time,
+	lease time.Duration, limit int) ([]model.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, `UPDATE notification_deliveries
+				SET next_attempt_at = $1
+				WHERE id IN (SELECT id FROM notification_deliveries
+							 WHERE status = $2 AND next_attempt_at <= $3
+							   AND (NOT digest OR created_at <= $4)
+							 ORDER BY id
+							 LIMIT $5 FOR UPDATE SKIP LOCKED)
+				RETURNING id`,
+		now.Add(lease), model.DeliveryPending, now, digestCutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("error while closing sql rows: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return r.getDeliveries(ctx, ids)
+}
+
+func (r *Repository) getDeliveries(ctx context.Context, ids []int64) ([]model.Delivery, error) {
+	rows, err := r.sq.Select(
+		"d.id", "d.notification_id",
+		"d.user_id", "d.channel",
+		"COALESCE(NULLIF(uc.address, ''), CASE WHEN d.channel = 'EMAIL' THEN u.email ELSE '' END)",
+		"d.digest", "d.attempts",
+		"n.event", "n.message",
+		"n.created_at").
+		From("notification_deliveries d").
+		Join("notifications n ON n.id = d.notification_id").
+		Join("users u ON u.id = d.user_id").
+		LeftJoin("user_channels uc ON uc.user_id = d.user_id AND uc.channel = d.channel").
+		Where("d.id = ANY (?)", pq.Int64Array(ids)).
+		OrderBy("d.user_id", "d.channel", "n.created_at").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	deliveries := make([]model.Delivery, 0, len(ids))
+	for rows.Next() {
+		delivery := model.Delivery{}
+		if err = rows.Scan(
+			&delivery.ID, &delivery.NotificationID,
+			&delivery.UserID, &delivery.Channel,
+			&delivery.Address,
+			&delivery.Digest, &delivery.Attempts,
+			&delivery.Event, &delivery.Message,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *Repository) MarkDeliveriesSent(ctx context.Context, ids []int, sentAt time.Time) error {
+	_, err := r.sq.Update("notification_deliveries").
+		SetMap(map[string]interface{}{
+			"status":   model.DeliverySent,
+			"attempts": sq.Expr("attempts + 1"),
+			"sent_at":  sentAt,
+		}).Where(sq.Eq{"id": ids}).
+		ExecContext(ctx)
+	return err
+}
+
+// RescheduleDeliveries records a failed attempt. Deliveries are retried at nextAttemptAt,
+// or marked as failed for good if nextAttemptAt is zero.
+func (r *Repository) RescheduleDeliveries(ctx context.Context, ids []int, nextAttemptAt time.Time, lastError string) error {
+	values := map[string]interface{}{
+		"attempts":   sq.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+	if nextAttemptAt.IsZero() {
+		values["status"] = model.DeliveryFailed
+	} else {
+		values["next_attempt_at"] = nextAttemptAt
+	}
+
+	_, err := r.sq.Update("notification_deliveries").
+		SetMap(values).
+		Where(sq.Eq{"id": ids}).
+		ExecContext(ctx)
+	return err
+}
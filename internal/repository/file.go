@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetFile(ctx context.Context, filter *FileFilter) (*model.File, error) {
+	files, err := r.GetFiles(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	case len(files) == 0:
+		return nil, ierr.ErrFileNotFound
+	default:
+		return &files[0], nil
+	}
+}
+
+func (r *Repository) GetFiles(ctx context.Context, filter *FileFilter) ([]model.File, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	return r.queryFiles(ctx, r.sq.Select(fileColumns...).
+		From("files f").
+		Where(conditionsFromFileFilter(filter)).
+		OrderBy("f.created_at", "f.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset))
+}
+
+func (r *Repository) InsertFile(ctx context.Context, file *model.File) error {
+	if err := r.sq.Insert("files").
+		Columns("key", "name",
+			"content_type", "size",
+			"kind", "project_id",
+			"task_id", "uploaded_by",
+			"created_at").
+		Values(file.Key, file.Name,
+			file.ContentType, file.Size,
+			file.Kind, file.ProjectID,
+			file.TaskID, file.UploadedBy,
+			file.CreatedAt).
+		Suffix("RETURNING \"id\"").
+		QueryRowContext(ctx).
+		Scan(&file.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return nil
+}
+
+// MarkFilesDeleted hides the files, they are removed from the storage by the cleanup
+func (r *Repository) MarkFilesDeleted(ctx context.Context, filter *FileFilter, exceptID int) error {
+	_, err := r.sq.Update("files f").
+		Set("deleted_at", time.Now()).
+		Where(conditionsFromFileFilter(filter)).
+		Where(sq.NotEq{"f.id": exceptID}).
+		ExecContext(ctx)
+	return err
+}
+
+// GetOrphanedFiles returns the deleted files and the files whose project or task was deleted
+func (r *Repository) GetOrphanedFiles(ctx context.Context, limit uint64) ([]model.File, error) {
+	return r.queryFiles(ctx, r.sq.Select(fileColumns...).
+		From("files f").
+		Where(sq.Or{
+			sq.NotEq{"f.deleted_at": nil},
+			sq.Eq{"f.project_id": nil},
+			sq.And{sq.Eq{"f.kind": model.FileAttachment}, sq.Eq{"f.task_id": nil}},
+		}).
+		OrderBy("f.id").
+		Limit(limit))
+}
+
+func (r *Repository) DeleteFiles(ctx context.Context, ids []int) error {
+	_, err := r.sq.Delete("files").
+		Where(sq.Eq{"id": ids}).ExecContext(ctx)
+	return err
+}
+
+var fileColumns = []string{
+	"f.id", "f.key",
+	"f.name", "f.content_type",
+	"f.size", "f.kind",
+	"f.project_id", "f.task_id",
+	"f.uploaded_by", "f.created_at",
+	"f.deleted_at",
+}
+
+func (r *Repository) queryFiles(ctx context.Context, q sq.SelectBuilder) ([]model.File, error) {
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	files := make([]model.File, 0)
+	for rows.Next() {
+		file := model.File{}
+		if err = rows.Scan(
+			&file.ID, &file.Key,
+			&file.Name, &file.ContentType,
+			&file.Size, &file.Kind,
+			&file.ProjectID, &file.TaskID,
+			&file.UploadedBy, &file.CreatedAt,
+			&file.DeletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
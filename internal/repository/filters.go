@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"be-project-monitoring/internal/db"
 	"be-project-monitoring/internal/domain/model"
 
@@ -132,9 +134,11 @@ func conditionsFromUserFilterForProject(filter *UserFilter) sq.Sqlizer {
 }
 
 type ProjectFilter struct {
-	ID     int
-	Name   string
-	isLike bool
+	ID      int
+	Name    string
+	Course  string
+	OwnerID uuid.UUID
+	isLike  bool
 	*db.Paginator
 }
 
@@ -157,6 +161,16 @@ func (f *ProjectFilter) ByProjectNameLike(name string) *ProjectFilter {
 	return f
 }
 
+func (f *ProjectFilter) ByCourse(course string) *ProjectFilter {
+	f.Course = course
+	return f
+}
+
+func (f *ProjectFilter) ByOwnerID(id uuid.UUID) *ProjectFilter {
+	f.OwnerID = id
+	return f
+}
+
 func (f *ProjectFilter) WithPaginator(limit, offset uint64) *ProjectFilter {
 	f.Paginator = db.NewPaginator(limit, offset)
 	return f
@@ -167,22 +181,34 @@ func conditionsFromProjectFilter(filter *ProjectFilter) sq.Sqlizer {
 		return sq.Eq{"p.id": filter.ID}
 	}
 
-	if !filter.isLike {
-		if filter.Name != "" {
-			return sq.Eq{"p.name": filter.Name}
-		}
-		return nil
+	conditions := sq.And{}
+	if filter.Course != "" {
+		conditions = append(conditions, sq.Eq{"p.course": filter.Course})
+	}
+	if filter.OwnerID != uuid.Nil {
+		conditions = append(conditions, sq.Expr("p.id IN (SELECT project_id FROM participants WHERE user_id = ? AND role = ?)",
+			filter.OwnerID, model.RoleOwner))
+	}
+
+	switch {
+	case filter.isLike:
+		conditions = append(conditions, sq.Like{"p.name": "%" + filter.Name + "%"})
+	case filter.Name != "":
+		conditions = append(conditions, sq.Eq{"p.name": filter.Name})
 	}
-	return sq.Like{"p.name": "%" + filter.Name + "%"}
+	return conditions
 }
 
 type TaskFilter struct {
 	ID            int
+	IDs           []int
 	ProjectID     int
 	ParticipantID *int
 	Name          *string
 	Status        model.TaskStatus
 	Approved      *bool
+	DoneFrom      time.Time
+	DoneTo        time.Time
 	*db.Paginator
 }
 
@@ -195,6 +221,11 @@ func (f *TaskFilter) ByID(id int) *TaskFilter {
 	return f
 }
 
+func (f *TaskFilter) ByIDs(ids []int) *TaskFilter {
+	f.IDs = ids
+	return f
+}
+
 func (f *TaskFilter) ByProjectID(id int) *TaskFilter {
 	f.ProjectID = id
 	return f
@@ -220,6 +251,12 @@ func (f *TaskFilter) ByApproved(approved bool) *TaskFilter {
 	return f
 }
 
+// ByDoneBetween finds the tasks moved to DONE in [from, to)
+func (f *TaskFilter) ByDoneBetween(from, to time.Time) *TaskFilter {
+	f.DoneFrom, f.DoneTo = from, to
+	return f
+}
+
 func (f *TaskFilter) WithPaginator(limit, offset uint64) *TaskFilter {
 	f.Paginator = db.NewPaginator(limit, offset)
 	return f
@@ -231,6 +268,9 @@ func conditionsFromTaskFilter(filter *TaskFilter) sq.Sqlizer {
 	}
 	eq := make(sq.Eq)
 	eq["t.project_id"] = filter.ProjectID
+	if len(filter.IDs) != 0 {
+		eq["t.id"] = filter.IDs
+	}
 	if filter.Name != nil {
 		eq["t.name"] = *filter.Name
 	}
@@ -240,6 +280,12 @@ func conditionsFromTaskFilter(filter *TaskFilter) sq.Sqlizer {
 	if filter.Approved != nil {
 		eq["t.approved"] = *filter.Approved
 	}
+	if filter.Status != "" {
+		eq["t.status"] = filter.Status
+	}
+	if !filter.DoneFrom.IsZero() {
+		return sq.And{eq, sq.GtOrEq{"t.done_at": filter.DoneFrom}, sq.Lt{"t.done_at": filter.DoneTo}}
+	}
 	return eq
 }
 
@@ -301,3 +347,609 @@ func conditionsFromParticipantFilter(filter *ParticipantFilter) sq.Sqlizer {
 	}
 	return eq
 }
+
+type WorklogFilter struct {
+	ID            int
+	TaskID        int
+	ParticipantID int
+	Running       *bool
+	*db.Paginator
+}
+
+func NewWorklogFilter() *WorklogFilter {
+	return &WorklogFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *WorklogFilter) ByID(id int) *WorklogFilter {
+	f.ID = id
+	return f
+}
+
+func (f *WorklogFilter) ByTaskID(id int) *WorklogFilter {
+	f.TaskID = id
+	return f
+}
+
+func (f *WorklogFilter) ByParticipantID(id int) *WorklogFilter {
+	f.ParticipantID = id
+	return f
+}
+
+func (f *WorklogFilter) ByRunning(running bool) *WorklogFilter {
+	f.Running = &running
+	return f
+}
+
+func (f *WorklogFilter) WithPaginator(limit, offset uint64) *WorklogFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromWorklogFilter(filter *WorklogFilter) sq.Sqlizer {
+	eq := make(sq.Eq)
+	if filter.ID > 0 {
+		eq["w.id"] = filter.ID
+	}
+	if filter.TaskID > 0 {
+		eq["w.task_id"] = filter.TaskID
+	}
+	if filter.ParticipantID > 0 {
+		eq["w.participant_id"] = filter.ParticipantID
+	}
+	if filter.Running != nil {
+		eq["w.running"] = *filter.Running
+	}
+	return eq
+}
+
+type NotificationFilter struct {
+	UserID uuid.UUID
+	Unread bool
+	*db.Paginator
+}
+
+func NewNotificationFilter() *NotificationFilter {
+	return &NotificationFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *NotificationFilter) ByUserID(id uuid.UUID) *NotificationFilter {
+	f.UserID = id
+	return f
+}
+
+func (f *NotificationFilter) ByUnread() *NotificationFilter {
+	f.Unread = true
+	return f
+}
+
+func (f *NotificationFilter) WithPaginator(limit, offset uint64) *NotificationFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromNotificationFilter(filter *NotificationFilter) sq.Sqlizer {
+	eq := sq.Eq{"n.user_id": filter.UserID}
+	if filter.Unread {
+		eq["n.read"] = false
+	}
+	return eq
+}
+
+type FileFilter struct {
+	ID        int
+	ProjectID int
+	TaskID    int
+	Kind      model.FileKind
+	*db.Paginator
+}
+
+func NewFileFilter() *FileFilter {
+	return &FileFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *FileFilter) ByID(id int) *FileFilter {
+	f.ID = id
+	return f
+}
+
+func (f *FileFilter) ByProjectID(id int) *FileFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *FileFilter) ByTaskID(id int) *FileFilter {
+	f.TaskID = id
+	return f
+}
+
+func (f *FileFilter) ByKind(kind model.FileKind) *FileFilter {
+	f.Kind = kind
+	return f
+}
+
+func (f *FileFilter) WithPaginator(limit, offset uint64) *FileFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromFileFilter(filter *FileFilter) sq.Sqlizer {
+	eq := sq.Eq{"f.deleted_at": nil}
+	if filter.ID > 0 {
+		eq["f.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["f.project_id"] = filter.ProjectID
+	}
+	if filter.TaskID > 0 {
+		eq["f.task_id"] = filter.TaskID
+	}
+	if filter.Kind != "" {
+		eq["f.kind"] = filter.Kind
+	}
+	return eq
+}
+
+type SubmissionFilter struct {
+	ID        int
+	ProjectID int
+	*db.Paginator
+}
+
+func NewSubmissionFilter() *SubmissionFilter {
+	return &SubmissionFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *SubmissionFilter) ByID(id int) *SubmissionFilter {
+	f.ID = id
+	return f
+}
+
+func (f *SubmissionFilter) ByProjectID(id int) *SubmissionFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *SubmissionFilter) WithPaginator(limit, offset uint64) *SubmissionFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromSubmissionFilter(filter *SubmissionFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["s.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["s.project_id"] = filter.ProjectID
+	}
+	return eq
+}
+
+type RubricFilter struct {
+	ID      int
+	OwnerID uuid.UUID
+	*db.Paginator
+}
+
+func NewRubricFilter() *RubricFilter {
+	return &RubricFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *RubricFilter) ByID(id int) *RubricFilter {
+	f.ID = id
+	return f
+}
+
+func (f *RubricFilter) ByOwnerID(id uuid.UUID) *RubricFilter {
+	f.OwnerID = id
+	return f
+}
+
+func (f *RubricFilter) WithPaginator(limit, offset uint64) *RubricFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromRubricFilter(filter *RubricFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["r.id"] = filter.ID
+	}
+	if filter.OwnerID != uuid.Nil {
+		eq["r.owner_id"] = filter.OwnerID
+	}
+	return eq
+}
+
+type PeerRoundFilter struct {
+	ID        int
+	ProjectID int
+	*db.Paginator
+}
+
+func NewPeerRoundFilter() *PeerRoundFilter {
+	return &PeerRoundFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *PeerRoundFilter) ByID(id int) *PeerRoundFilter {
+	f.ID = id
+	return f
+}
+
+func (f *PeerRoundFilter) ByProjectID(id int) *PeerRoundFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *PeerRoundFilter) WithPaginator(limit, offset uint64) *PeerRoundFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromPeerRoundFilter(filter *PeerRoundFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["r.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["r.project_id"] = filter.ProjectID
+	}
+	return eq
+}
+
+type PeerReviewFilter struct {
+	RoundID    int
+	ReviewerID int
+}
+
+func NewPeerReviewFilter() *PeerReviewFilter {
+	return &PeerReviewFilter{}
+}
+
+func (f *PeerReviewFilter) ByRoundID(id int) *PeerReviewFilter {
+	f.RoundID = id
+	return f
+}
+
+func (f *PeerReviewFilter) ByReviewerID(id int) *PeerReviewFilter {
+	f.ReviewerID = id
+	return f
+}
+
+func conditionsFromPeerReviewFilter(filter *PeerReviewFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.RoundID > 0 {
+		eq["pr.round_id"] = filter.RoundID
+	}
+	if filter.ReviewerID > 0 {
+		eq["pr.reviewer_id"] = filter.ReviewerID
+	}
+	return eq
+}
+
+type StatusReportFilter struct {
+	ID         int
+	ProjectID  int
+	ProjectIDs []int
+	WeekStart  time.Time
+	*db.Paginator
+}
+
+func NewStatusReportFilter() *StatusReportFilter {
+	return &StatusReportFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *StatusReportFilter) ByID(id int) *StatusReportFilter {
+	f.ID = id
+	return f
+}
+
+func (f *StatusReportFilter) ByProjectID(id int) *StatusReportFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *StatusReportFilter) ByProjectIDs(ids []int) *StatusReportFilter {
+	f.ProjectIDs = ids
+	return f
+}
+
+func (f *StatusReportFilter) ByWeekStart(weekStart time.Time) *StatusReportFilter {
+	f.WeekStart = weekStart
+	return f
+}
+
+func (f *StatusReportFilter) WithPaginator(limit, offset uint64) *StatusReportFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromStatusReportFilter(filter *StatusReportFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["s.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["s.project_id"] = filter.ProjectID
+	}
+	if filter.ProjectIDs != nil {
+		eq["s.project_id"] = filter.ProjectIDs
+	}
+	if !filter.WeekStart.IsZero() {
+		eq["s.week_start"] = filter.WeekStart
+	}
+	return eq
+}
+
+type MeetingFilter struct {
+	ID        int
+	ProjectID int
+	*db.Paginator
+}
+
+func NewMeetingFilter() *MeetingFilter {
+	return &MeetingFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *MeetingFilter) ByID(id int) *MeetingFilter {
+	f.ID = id
+	return f
+}
+
+func (f *MeetingFilter) ByProjectID(id int) *MeetingFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *MeetingFilter) WithPaginator(limit, offset uint64) *MeetingFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromMeetingFilter(filter *MeetingFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["m.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["m.project_id"] = filter.ProjectID
+	}
+	return eq
+}
+
+type ChecklistFilter struct {
+	ID        int
+	ProjectID int
+	*db.Paginator
+}
+
+func NewChecklistFilter() *ChecklistFilter {
+	return &ChecklistFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *ChecklistFilter) ByID(id int) *ChecklistFilter {
+	f.ID = id
+	return f
+}
+
+func (f *ChecklistFilter) ByProjectID(id int) *ChecklistFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *ChecklistFilter) WithPaginator(limit, offset uint64) *ChecklistFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromChecklistFilter(filter *ChecklistFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["l.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["l.project_id"] = filter.ProjectID
+	}
+	return eq
+}
+
+type ChecklistItemFilter struct {
+	ID          int
+	ChecklistID int
+	ProjectID   int
+}
+
+func NewChecklistItemFilter() *ChecklistItemFilter {
+	return &ChecklistItemFilter{}
+}
+
+func (f *ChecklistItemFilter) ByID(id int) *ChecklistItemFilter {
+	f.ID = id
+	return f
+}
+
+func (f *ChecklistItemFilter) ByChecklistID(id int) *ChecklistItemFilter {
+	f.ChecklistID = id
+	return f
+}
+
+func (f *ChecklistItemFilter) ByProjectID(id int) *ChecklistItemFilter {
+	f.ProjectID = id
+	return f
+}
+
+func conditionsFromChecklistItemFilter(filter *ChecklistItemFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["i.id"] = filter.ID
+	}
+	if filter.ChecklistID > 0 {
+		eq["i.checklist_id"] = filter.ChecklistID
+	}
+	if filter.ProjectID > 0 {
+		eq["i.project_id"] = filter.ProjectID
+	}
+	return eq
+}
+
+type ChecklistTemplateFilter struct {
+	ID      int
+	OwnerID uuid.UUID
+	*db.Paginator
+}
+
+func NewChecklistTemplateFilter() *ChecklistTemplateFilter {
+	return &ChecklistTemplateFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *ChecklistTemplateFilter) ByID(id int) *ChecklistTemplateFilter {
+	f.ID = id
+	return f
+}
+
+func (f *ChecklistTemplateFilter) ByOwnerID(id uuid.UUID) *ChecklistTemplateFilter {
+	f.OwnerID = id
+	return f
+}
+
+func (f *ChecklistTemplateFilter) WithPaginator(limit, offset uint64) *ChecklistTemplateFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromChecklistTemplateFilter(filter *ChecklistTemplateFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["t.id"] = filter.ID
+	}
+	if filter.OwnerID != uuid.Nil {
+		eq["t.owner_id"] = filter.OwnerID
+	}
+	return eq
+}
+
+type ProjectRepositoryFilter struct {
+	ID        int
+	ProjectID int
+	URLLike   string
+	*db.Paginator
+}
+
+func NewProjectRepositoryFilter() *ProjectRepositoryFilter {
+	return &ProjectRepositoryFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *ProjectRepositoryFilter) ByID(id int) *ProjectRepositoryFilter {
+	f.ID = id
+	return f
+}
+
+func (f *ProjectRepositoryFilter) ByProjectID(id int) *ProjectRepositoryFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *ProjectRepositoryFilter) ByURLLike(text string) *ProjectRepositoryFilter {
+	f.URLLike = text
+	return f
+}
+
+func (f *ProjectRepositoryFilter) WithPaginator(limit, offset uint64) *ProjectRepositoryFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromProjectRepositoryFilter(filter *ProjectRepositoryFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ID > 0 {
+		eq["r.id"] = filter.ID
+	}
+	if filter.ProjectID > 0 {
+		eq["r.project_id"] = filter.ProjectID
+	}
+	if filter.URLLike != "" {
+		return sq.And{eq, sq.ILike{"r.url": "%" + filter.URLLike + "%"}}
+	}
+	return eq
+}
+
+type RepositoryEventFilter struct {
+	ProjectID int
+	Kind      model.RepositoryEventKind
+	UserID    uuid.UUID
+	*db.Paginator
+}
+
+func NewRepositoryEventFilter() *RepositoryEventFilter {
+	return &RepositoryEventFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *RepositoryEventFilter) ByProjectID(id int) *RepositoryEventFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *RepositoryEventFilter) ByKind(kind model.RepositoryEventKind) *RepositoryEventFilter {
+	f.Kind = kind
+	return f
+}
+
+func (f *RepositoryEventFilter) ByUserID(id uuid.UUID) *RepositoryEventFilter {
+	f.UserID = id
+	return f
+}
+
+func (f *RepositoryEventFilter) WithPaginator(limit, offset uint64) *RepositoryEventFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromRepositoryEventFilter(filter *RepositoryEventFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ProjectID > 0 {
+		eq["e.project_id"] = filter.ProjectID
+	}
+	if filter.Kind != "" {
+		eq["e.kind"] = filter.Kind
+	}
+	if filter.UserID != uuid.Nil {
+		eq["e.user_id"] = filter.UserID
+	}
+	return eq
+}
+
+type IssueSyncLogFilter struct {
+	ProjectID int
+	TaskID    int
+	*db.Paginator
+}
+
+func NewIssueSyncLogFilter() *IssueSyncLogFilter {
+	return &IssueSyncLogFilter{Paginator: db.DefaultPaginator}
+}
+
+func (f *IssueSyncLogFilter) ByProjectID(id int) *IssueSyncLogFilter {
+	f.ProjectID = id
+	return f
+}
+
+func (f *IssueSyncLogFilter) ByTaskID(id int) *IssueSyncLogFilter {
+	f.TaskID = id
+	return f
+}
+
+func (f *IssueSyncLogFilter) WithPaginator(limit, offset uint64) *IssueSyncLogFilter {
+	f.Paginator = db.NewPaginator(limit, offset)
+	return f
+}
+
+func conditionsFromIssueSyncLogFilter(filter *IssueSyncLogFilter) sq.Sqlizer {
+	eq := sq.Eq{}
+	if filter.ProjectID > 0 {
+		eq["l.project_id"] = filter.ProjectID
+	}
+	if filter.TaskID > 0 {
+		eq["l.task_id"] = filter.TaskID
+	}
+	return eq
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+// ClaimGithubSyncs returns up to limit repositories whose statistics are due to be synced:
+// the ones synced successfully before syncedBefore and the others whose next attempt is due by now.
+// The returned repositories are leased until claimUntil, so the replicas do not sync the same one twice.
+func (r *Repository) ClaimGithubSyncs(ctx context.Context, now, claimUntil, syncedBefore time.Time,
+	limit int) ([]model.GithubSync, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO github_syncs (repository_id, project_id, next_sync_at)
+				SELECT r.id, r.project_id, $1
+				FROM project_repositories r
+				ON CONFLICT DO NOTHING`, now); err != nil {
+		return nil, fmt.Errorf("error while inserting github syncs: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `UPDATE github_syncs s
+				SET claimed_until = $2, attempted_at = $1
+				FROM (
+					SELECT s.repository_id
+					FROM github_syncs s
+					WHERE (s.claimed_until IS NULL OR s.claimed_until < $1)
+					  AND (s.status = $4 AND s.synced_at < $3 OR s.status <> $4 AND s.next_sync_at <= $1)
+					ORDER BY s.attempted_at NULLS FIRST
+					LIMIT $5
+					FOR UPDATE OF s SKIP LOCKED
+				) due
+				WHERE s.repository_id = due.repository_id
+				RETURNING s.repository_id, s.project_id, s.status, s.attempts,
+						  s.next_sync_at, s.attempted_at, s.synced_at, s.error`,
+		now, claimUntil, syncedBefore, model.SyncOK, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	syncs := make([]model.GithubSync, 0)
+	for rows.Next() {
+		sync := model.GithubSync{}
+		if err = rows.Scan(&sync.RepositoryID, &sync.ProjectID, &sync.Status, &sync.Attempts,
+			&sync.NextSyncAt, &sync.AttemptedAt, &sync.SyncedAt, &sync.Error); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, fmt.Errorf("error while closing sql rows: %w", err)
+	}
+
+	return syncs, tx.Commit()
+}
+
+// SaveGithubSync saves the result of the sync attempt and releases the repository
+func (r *Repository) SaveGithubSync(ctx context.Context, sync *model.GithubSync) error {
+	return r.saveGithubSync(ctx, r.db, sync)
+}
+
+func (r *Repository) saveGithubSync(ctx context.Context, runner sq.BaseRunner, sync *model.GithubSync) error {
+	_, err := r.sq.Insert("github_syncs").
+		Columns("repository_id", "project_id",
+			"status", "attempts",
+			"next_sync_at", "claimed_until",
+			"attempted_at", "synced_at",
+			"error").
+		Values(sync.RepositoryID, sync.ProjectID,
+			sync.Status, sync.Attempts,
+			sync.NextSyncAt, nil,
+			sync.AttemptedAt, sync.SyncedAt,
+			sync.Error).
+		Suffix(`ON CONFLICT (repository_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			attempts = EXCLUDED.attempts,
+			next_sync_at = EXCLUDED.next_sync_at,
+			claimed_until = NULL,
+			attempted_at = EXCLUDED.attempted_at,
+			synced_at = EXCLUDED.synced_at,
+			error = EXCLUDED.error`).
+		RunWith(runner).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while saving github sync: %w", err)
+	}
+	return nil
+}
+
+// ReplaceContributorStats replaces the statistics of the repository with the synced ones
+// and saves the sync in one transaction
+func (r *Repository) ReplaceContributorStats(ctx context.Context, weeks []model.ContributorWeek, sync *model.GithubSync) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Delete("contributor_stats").
+		Where(sq.Eq{"repository_id": sync.RepositoryID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting contributor stats: %w", err)
+	}
+
+	if len(weeks) > 0 {
+		insert := r.sq.Insert("contributor_stats").
+			Columns("repository_id", "project_id",
+				"github_username", "week_start",
+				"commits", "additions",
+				"deletions")
+		for _, week := range weeks {
+			insert = insert.Values(sync.RepositoryID, sync.ProjectID,
+				week.GithubUsername, week.WeekStart,
+				week.Commits, week.Additions,
+				week.Deletions)
+		}
+		if _, err = insert.RunWith(tx).ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while inserting contributor stats: %w", err)
+		}
+	}
+
+	if err = r.saveGithubSync(ctx, tx, sync); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ResetGithubSync drops the statistics and the pull requests of the repository and makes it due to be synced at now,
+// it is used when the url of the repository changes
+func (r *Repository) ResetGithubSync(ctx context.Context, repository *model.ProjectRepository, now time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Delete("contributor_stats").
+		Where(sq.Eq{"repository_id": repository.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting contributor stats: %w", err)
+	}
+	if _, err = r.sq.Delete("pull_requests").
+		Where(sq.Eq{"repository_id": repository.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting pull requests: %w", err)
+	}
+	if err = r.saveGithubSync(ctx, tx, &model.GithubSync{
+		RepositoryID: repository.ID,
+		ProjectID:    repository.ProjectID,
+		Status:       model.SyncPending,
+		NextSyncAt:   now,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetGithubSyncs returns the syncs of the repositories of the project or of all the projects
+// if projectID is zero, the failed ones first
+func (r *Repository) GetGithubSyncs(ctx context.Context, projectID int) ([]model.GithubSync, error) {
+	conditions := sq.Eq{}
+	if projectID > 0 {
+		conditions["s.project_id"] = projectID
+	}
+
+	rows, err := r.sq.Select(
+		"s.repository_id", "r.url",
+		"s.project_id", "p.name",
+		"s.status", "s.attempts",
+		"s.next_sync_at", "s.attempted_at",
+		"s.synced_at", "s.error").
+		From("github_syncs s").
+		Join("project_repositories r ON r.id = s.repository_id").
+		Join("projects p ON p.id = s.project_id").
+		Where(conditions).
+		OrderBy("s.error IS NULL", "s.project_id", "s.repository_id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	syncs := make([]model.GithubSync, 0)
+	for rows.Next() {
+		sync := model.GithubSync{}
+		if err = rows.Scan(
+			&sync.RepositoryID, &sync.RepositoryURL,
+			&sync.ProjectID, &sync.ProjectName,
+			&sync.Status, &sync.Attempts,
+			&sync.NextSyncAt, &sync.AttemptedAt,
+			&sync.SyncedAt, &sync.Error,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+	return syncs, nil
+}
+
+// GetContributorStats returns the totals of every contributor of every repository of the project
+// from the last synced statistics
+func (r *Repository) GetContributorStats(ctx context.Context, projectID int) ([]model.ContributorStats, error) {
+	rows, err := r.sq.Select(
+		"repository_id",
+		"github_username",
+		"SUM(commits)",
+		"SUM(additions)",
+		"SUM(deletions)").
+		From("contributor_stats").
+		Where(sq.Eq{"project_id": projectID}).
+		GroupBy("repository_id", "github_username").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	stats := make([]model.ContributorStats, 0)
+	for rows.Next() {
+		stat := model.ContributorStats{}
+		if err = rows.Scan(&stat.RepositoryID, &stat.GithubUsername,
+			&stat.Commits, &stat.Additions, &stat.Deletions); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
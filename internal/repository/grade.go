@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetRubric(ctx context.Context, filter *RubricFilter) (*model.Rubric, error) {
+	rubrics, err := r.GetRubrics(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get rubric: %w", err)
+	case len(rubrics) == 0:
+		return nil, ierr.ErrRubricNotFound
+	default:
+		return &rubrics[0], nil
+	}
+}
+
+// GetRubrics returns the rubrics with their criteria
+func (r *Repository) GetRubrics(ctx context.Context, filter *RubricFilter) ([]model.Rubric, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"r.id", "r.owner_id",
+		"r.name", "r.course",
+		"r.created_at").
+		From("rubrics r").
+		Where(conditionsFromRubricFilter(filter)).
+		OrderBy("r.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	rubrics := make([]model.Rubric, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		rubric := model.Rubric{}
+		if err = rows.Scan(
+			&rubric.ID, &rubric.OwnerID,
+			&rubric.Name, &rubric.Course,
+			&rubric.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		index[rubric.ID] = len(rubrics)
+		rubrics = append(rubrics, rubric)
+	}
+	if len(rubrics) == 0 {
+		return rubrics, nil
+	}
+
+	ids := make([]int64, 0, len(rubrics))
+	for _, rubric := range rubrics {
+		ids = append(ids, int64(rubric.ID))
+	}
+	criteria, err := r.getCriteria(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, criterion := range criteria {
+		i := index[criterion.RubricID]
+		rubrics[i].Criteria = append(rubrics[i].Criteria, criterion)
+	}
+	return rubrics, nil
+}
+
+func (r *Repository) getCriteria(ctx context.Context, rubricIDs []int64) ([]model.Criterion, error) {
+	rows, err := r.sq.Select(
+		"c.id", "c.rubric_id",
+		"c.name", "c.weight",
+		"c.max_points", "c.scope",
+		"c.metric", "c.metric_target",
+		"c.position").
+		From("rubric_criteria c").
+		Where("c.rubric_id = ANY (?)", pq.Int64Array(rubricIDs)).
+		OrderBy("c.position", "c.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	criteria := make([]model.Criterion, 0)
+	for rows.Next() {
+		criterion := model.Criterion{}
+		if err = rows.Scan(
+			&criterion.ID, &criterion.RubricID,
+			&criterion.Name, &criterion.Weight,
+			&criterion.MaxPoints, &criterion.Scope,
+			&criterion.Metric, &criterion.MetricTarget,
+			&criterion.Position,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria, nil
+}
+
+// InsertRubric saves the rubric with its criteria in one transaction
+func (r *Repository) InsertRubric(ctx context.Context, rubric *model.Rubric) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.sq.Insert("rubrics").
+		Columns("owner_id", "name",
+			"course", "created_at").
+		Values(rubric.OwnerID, rubric.Name,
+			rubric.Course, rubric.CreatedAt).
+		Suffix("RETURNING \"id\"").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&rubric.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if err = r.saveCriteria(ctx, tx, rubric); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateRubric saves the rubric and its criteria in one transaction. Criteria without ID
+// are added, the ones missing from the rubric are deleted together with their grades.
+func (r *Repository) UpdateRubric(ctx context.Context, rubric *model.Rubric) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Update("rubrics").
+		SetMap(map[string]interface{}{
+			"name":   rubric.Name,
+			"course": rubric.Course,
+		}).Where(sq.Eq{"id": rubric.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating rubric: %w", err)
+	}
+
+	kept := make([]int, 0, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		if criterion.ID != 0 {
+			kept = append(kept, criterion.ID)
+		}
+	}
+	if _, err = r.sq.Delete("rubric_criteria").
+		Where(sq.Eq{"rubric_id": rubric.ID}).
+		Where(sq.NotEq{"id": kept}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting criteria: %w", err)
+	}
+
+	if err = r.saveCriteria(ctx, tx, rubric); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) saveCriteria(ctx context.Context, tx *sql.Tx, rubric *model.Rubric) error {
+	for i := range rubric.Criteria {
+		criterion := &rubric.Criteria[i]
+		criterion.RubricID = rubric.ID
+		values := map[string]interface{}{
+			"rubric_id":     criterion.RubricID,
+			"name":          criterion.Name,
+			"weight":        criterion.Weight,
+			"max_points":    criterion.MaxPoints,
+			"scope":         criterion.Scope,
+			"metric":        criterion.Metric,
+			"metric_target": criterion.MetricTarget,
+			"position":      criterion.Position,
+		}
+
+		if criterion.ID != 0 {
+			if _, err := r.sq.Update("rubric_criteria").
+				SetMap(values).
+				Where(sq.Eq{"id": criterion.ID, "rubric_id": rubric.ID}).
+				RunWith(tx).
+				ExecContext(ctx); err != nil {
+				return fmt.Errorf("error while updating criterion %v: %w", criterion.ID, err)
+			}
+			continue
+		}
+
+		if err := r.sq.Insert("rubric_criteria").
+			SetMap(values).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&criterion.ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) DeleteRubric(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("rubrics").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
+
+// GetGrades returns the grades of the projects by the criteria of the rubric
+func (r *Repository) GetGrades(ctx context.Context, rubricID int, projectIDs []int) ([]model.Grade, error) {
+	rows, err := r.sq.Select(
+		"g.id", "g.criterion_id",
+		"g.project_id", "g.participant_id",
+		"g.points", "g.comment",
+		"g.auto", "g.graded_by",
+		"g.updated_at").
+		From("grades g").
+		Join("rubric_criteria c ON c.id = g.criterion_id").
+		Where(sq.Eq{"c.rubric_id": rubricID, "g.project_id": projectIDs}).
+		OrderBy("g.project_id", "g.participant_id", "c.position").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	grades := make([]model.Grade, 0)
+	for rows.Next() {
+		grade := model.Grade{}
+		if err = rows.Scan(
+			&grade.ID, &grade.CriterionID,
+			&grade.ProjectID, &grade.ParticipantID,
+			&grade.Points, &grade.Comment,
+			&grade.Auto, &grade.GradedBy,
+			&grade.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		grades = append(grades, grade)
+	}
+	return grades, nil
+}
+
+// UpsertGrades saves the grades, if onlyAuto is set the grades put by hand are kept
+func (r *Repository) UpsertGrades(ctx context.Context, grades []model.Grade, onlyAuto bool) error {
+	if len(grades) == 0 {
+		return nil
+	}
+
+	q := r.sq.Insert("grades").
+		Columns("criterion_id", "project_id",
+			"participant_id", "points",
+			"comment", "auto",
+			"graded_by", "updated_at")
+	for _, v := range grades {
+		q = q.Values(v.CriterionID, v.ProjectID,
+			v.ParticipantID, v.Points,
+			v.Comment, v.Auto,
+			v.GradedBy, v.UpdatedAt)
+	}
+
+	suffix := `ON CONFLICT (criterion_id, project_id, (COALESCE(participant_id, 0))) DO UPDATE
+		SET points = EXCLUDED.points, comment = EXCLUDED.comment, auto = EXCLUDED.auto,
+			graded_by = EXCLUDED.graded_by, updated_at = EXCLUDED.updated_at`
+	if onlyAuto {
+		suffix += " WHERE grades.auto"
+	}
+
+	_, err := q.Suffix(suffix).ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetIssueSync(ctx context.Context, projectID int) (*model.IssueSync, error) {
+	syncs, err := r.getIssueSyncs(ctx, sq.Eq{"s.project_id": projectID})
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get issue sync: %w", err)
+	case len(syncs) == 0:
+		return nil, ierr.ErrIssueSyncNotFound
+	default:
+		return &syncs[0], nil
+	}
+}
+
+// GetEnabledIssueSyncs returns the syncs of all the projects which have it enabled
+func (r *Repository) GetEnabledIssueSyncs(ctx context.Context) ([]model.IssueSync, error) {
+	return r.getIssueSyncs(ctx, sq.Eq{"s.enabled": true})
+}
+
+func (r *Repository) getIssueSyncs(ctx context.Context, conditions sq.Sqlizer) ([]model.IssueSync, error) {
+	rows, err := r.sq.Select(
+		"s.project_id", "s.repository_id",
+		"r.url", "s.enabled",
+		"s.synced_at").
+		From("issue_syncs s").
+		Join("project_repositories r ON r.id = s.repository_id").
+		Where(conditions).
+		OrderBy("s.project_id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	syncs := make([]model.IssueSync, 0)
+	for rows.Next() {
+		sync := model.IssueSync{}
+		if err = rows.Scan(
+			&sync.ProjectID, &sync.RepositoryID,
+			&sync.RepositoryURL, &sync.Enabled,
+			&sync.SyncedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+	return syncs, nil
+}
+
+// SaveIssueSync saves the setting of the project. The links to the issues of the other
+// repository are dropped if the repository changes, so its issues are imported anew.
+func (r *Repository) SaveIssueSync(ctx context.Context, sync *model.IssueSync) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Delete("task_issues").
+		Where("repository_id IN (SELECT repository_id FROM issue_syncs WHERE project_id = ? AND repository_id <> ?)",
+			sync.ProjectID, sync.RepositoryID).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting task issues: %w", err)
+	}
+	if err = r.saveIssueSync(ctx, tx, sync); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) saveIssueSync(ctx context.Context, runner sq.BaseRunner, sync *model.IssueSync) error {
+	_, err := r.sq.Insert("issue_syncs").
+		Columns("project_id", "repository_id",
+			"enabled", "synced_at").
+		Values(sync.ProjectID, sync.RepositoryID,
+			sync.Enabled, sync.SyncedAt).
+		Suffix(`ON CONFLICT (project_id) DO UPDATE SET
+			repository_id = EXCLUDED.repository_id,
+			enabled = EXCLUDED.enabled,
+			synced_at = EXCLUDED.synced_at`).
+		RunWith(runner).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while saving issue sync: %w", err)
+	}
+	return nil
+}
+
+// GetTaskIssues returns the links of the tasks to the issues of the repository
+func (r *Repository) GetTaskIssues(ctx context.Context, repositoryID int) ([]model.TaskIssue, error) {
+	rows, err := r.sq.Select(
+		"i.repository_id", "i.number",
+		"i.task_id", "i.url",
+		"i.task_updated_at", "i.issue_updated_at",
+		"i.synced_at").
+		From("task_issues i").
+		Where(sq.Eq{"i.repository_id": repositoryID}).
+		OrderBy("i.number").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	links := make([]model.TaskIssue, 0)
+	for rows.Next() {
+		link := model.TaskIssue{}
+		if err = rows.Scan(
+			&link.RepositoryID, &link.Number,
+			&link.TaskID, &link.URL,
+			&link.TaskUpdatedAt, &link.IssueUpdatedAt,
+			&link.SyncedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// SaveIssueSyncRun saves the links synced by the run, its log and the sync in one transaction
+func (r *Repository) SaveIssueSyncRun(ctx context.Context, sync *model.IssueSync,
+	links []model.TaskIssue, entries []model.IssueSyncEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	for _, link := range links {
+		if _, err = r.sq.Insert("task_issues").
+			Columns("repository_id", "number",
+				"task_id", "url",
+				"task_updated_at", "issue_updated_at",
+				"synced_at").
+			Values(link.RepositoryID, link.Number,
+				link.TaskID, link.URL,
+				link.TaskUpdatedAt, link.IssueUpdatedAt,
+				link.SyncedAt).
+			Suffix(`ON CONFLICT (repository_id, number) DO UPDATE SET
+				url = EXCLUDED.url,
+				task_updated_at = EXCLUDED.task_updated_at,
+				issue_updated_at = EXCLUDED.issue_updated_at,
+				synced_at = EXCLUDED.synced_at`).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while saving task issue %v: %w", link.Number, err)
+		}
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if err = r.sq.Insert("issue_sync_log").
+			Columns("project_id", "task_id",
+				"number", "direction",
+				"action", "conflict",
+				"message", "created_at").
+			Values(entry.ProjectID, entry.TaskID,
+				entry.Number, entry.Direction,
+				entry.Action, entry.Conflict,
+				entry.Message, entry.CreatedAt).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&entry.ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+
+	if err = r.saveIssueSync(ctx, tx, sync); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetIssueSyncLog returns the log of the project, the last runs first
+func (r *Repository) GetIssueSyncLog(ctx context.Context, filter *IssueSyncLogFilter) ([]model.IssueSyncEntry, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"l.id", "l.project_id",
+		"l.task_id", "l.number",
+		"l.direction", "l.action",
+		"l.conflict", "l.message",
+		"l.created_at").
+		From("issue_sync_log l").
+		Where(conditionsFromIssueSyncLogFilter(filter)).
+		OrderBy("l.created_at DESC", "l.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	entries := make([]model.IssueSyncEntry, 0)
+	for rows.Next() {
+		entry := model.IssueSyncEntry{}
+		if err = rows.Scan(
+			&entry.ID, &entry.ProjectID,
+			&entry.TaskID, &entry.Number,
+			&entry.Direction, &entry.Action,
+			&entry.Conflict, &entry.Message,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,353 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetMeeting(ctx context.Context, filter *MeetingFilter) (*model.Meeting, error) {
+	meetings, err := r.GetMeetings(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get meeting: %w", err)
+	case len(meetings) == 0:
+		return nil, ierr.ErrMeetingNotFound
+	default:
+		return &meetings[0], nil
+	}
+}
+
+// GetMeetings returns the meetings starting from the latest one, with their attendees and action items
+func (r *Repository) GetMeetings(ctx context.Context, filter *MeetingFilter) ([]model.Meeting, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"m.id", "m.project_id",
+		"m.kind", "m.title",
+		"m.held_at", "m.notes",
+		"m.decisions", "m.created_by",
+		"m.created_at", "m.updated_at").
+		From("meetings m").
+		Where(conditionsFromMeetingFilter(filter)).
+		OrderBy("m.held_at DESC", "m.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	meetings := make([]model.Meeting, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		meeting := model.Meeting{}
+		if err = rows.Scan(
+			&meeting.ID, &meeting.ProjectID,
+			&meeting.Kind, &meeting.Title,
+			&meeting.HeldAt, &meeting.Notes,
+			&meeting.Decisions, &meeting.CreatedBy,
+			&meeting.CreatedAt, &meeting.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		meeting.Attendees = make([]model.MeetingAttendee, 0)
+		meeting.ActionItems = make([]model.ActionItem, 0)
+		index[meeting.ID] = len(meetings)
+		meetings = append(meetings, meeting)
+	}
+	if len(meetings) == 0 {
+		return meetings, nil
+	}
+
+	ids := make([]int64, 0, len(meetings))
+	for _, meeting := range meetings {
+		ids = append(ids, int64(meeting.ID))
+	}
+	if err = r.fillMeetingAttendees(ctx, ids, meetings, index); err != nil {
+		return nil, err
+	}
+	if err = r.fillMeetingActionItems(ctx, ids, meetings, index); err != nil {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+func (r *Repository) fillMeetingAttendees(ctx context.Context, ids []int64, meetings []model.Meeting, index map[int]int) error {
+	rows, err := r.sq.Select("a.meeting_id", "a.participant_id", "a.present").
+		From("meeting_attendees a").
+		Where("a.meeting_id = ANY (?)", pq.Int64Array(ids)).
+		OrderBy("a.participant_id").
+		QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			meetingID int
+			attendee  model.MeetingAttendee
+		)
+		if err = rows.Scan(&meetingID, &attendee.ParticipantID, &attendee.Present); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		i := index[meetingID]
+		meetings[i].Attendees = append(meetings[i].Attendees, attendee)
+	}
+	return nil
+}
+
+func (r *Repository) fillMeetingActionItems(ctx context.Context, ids []int64, meetings []model.Meeting, index map[int]int) error {
+	rows, err := r.sq.Select(
+		"i.id", "i.meeting_id",
+		"i.text", "i.participant_id",
+		"i.due_date", "i.task_id",
+		"i.position").
+		From("meeting_action_items i").
+		Where("i.meeting_id = ANY (?)", pq.Int64Array(ids)).
+		OrderBy("i.position", "i.id").
+		QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		item := model.ActionItem{}
+		if err = rows.Scan(
+			&item.ID, &item.MeetingID,
+			&item.Text, &item.ParticipantID,
+			&item.DueDate, &item.TaskID,
+			&item.Position,
+		); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		i := index[item.MeetingID]
+		meetings[i].ActionItems = append(meetings[i].ActionItems, item)
+	}
+	return nil
+}
+
+// InsertMeeting saves the meeting with its attendees and action items in one transaction
+func (r *Repository) InsertMeeting(ctx context.Context, meeting *model.Meeting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.sq.Insert("meetings").
+		Columns("project_id", "kind",
+			"title", "held_at",
+			"notes", "decisions",
+			"created_by", "created_at",
+			"updated_at").
+		Values(meeting.ProjectID, meeting.Kind,
+			meeting.Title, meeting.HeldAt,
+			meeting.Notes, meeting.Decisions,
+			meeting.CreatedBy, meeting.CreatedAt,
+			meeting.UpdatedAt).
+		Suffix("RETURNING \"id\"").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&meeting.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if err = r.saveMeetingDetails(ctx, tx, meeting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateMeeting saves the meeting in one transaction, the attendees are replaced and the action
+// items missing from the meeting are deleted, the tasks made of them are kept
+func (r *Repository) UpdateMeeting(ctx context.Context, meeting *model.Meeting) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if _, err = r.sq.Update("meetings").
+		SetMap(map[string]interface{}{
+			"kind":       meeting.Kind,
+			"title":      meeting.Title,
+			"held_at":    meeting.HeldAt,
+			"notes":      meeting.Notes,
+			"decisions":  meeting.Decisions,
+			"updated_at": meeting.UpdatedAt,
+		}).Where(sq.Eq{"id": meeting.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating meeting: %w", err)
+	}
+
+	if _, err = r.sq.Delete("meeting_attendees").
+		Where(sq.Eq{"meeting_id": meeting.ID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting attendees: %w", err)
+	}
+
+	kept := make([]int, 0, len(meeting.ActionItems))
+	for _, item := range meeting.ActionItems {
+		if item.ID != 0 {
+			kept = append(kept, item.ID)
+		}
+	}
+	if _, err = r.sq.Delete("meeting_action_items").
+		Where(sq.Eq{"meeting_id": meeting.ID}).
+		Where(sq.NotEq{"id": kept}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting action items: %w", err)
+	}
+
+	if err = r.saveMeetingDetails(ctx, tx, meeting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) saveMeetingDetails(ctx context.Context, tx *sql.Tx, meeting *model.Meeting) error {
+	if len(meeting.Attendees) != 0 {
+		q := r.sq.Insert("meeting_attendees").
+			Columns("meeting_id", "participant_id", "present")
+		for _, attendee := range meeting.Attendees {
+			q = q.Values(meeting.ID, attendee.ParticipantID, attendee.Present)
+		}
+		if _, err := q.RunWith(tx).ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while inserting attendees: %w", err)
+		}
+	}
+
+	for i := range meeting.ActionItems {
+		item := &meeting.ActionItems[i]
+		item.MeetingID = meeting.ID
+		values := map[string]interface{}{
+			"meeting_id":     item.MeetingID,
+			"text":           item.Text,
+			"participant_id": item.ParticipantID,
+			"due_date":       item.DueDate,
+			"task_id":        item.TaskID,
+			"position":       item.Position,
+		}
+
+		if item.ID != 0 {
+			if _, err := r.sq.Update("meeting_action_items").
+				SetMap(values).
+				Where(sq.Eq{"id": item.ID, "meeting_id": meeting.ID}).
+				RunWith(tx).
+				ExecContext(ctx); err != nil {
+				return fmt.Errorf("error while updating action item %v: %w", item.ID, err)
+			}
+			continue
+		}
+
+		if err := r.sq.Insert("meeting_action_items").
+			SetMap(values).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&item.ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Repository) SetActionItemTask(ctx context.Context, itemID, taskID int) error {
+	_, err := r.sq.Update("meeting_action_items").
+		Set("task_id", taskID).
+		Where(sq.Eq{"id": itemID}).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) DeleteMeeting(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("meetings").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
+
+// GetAttendance counts the meetings of the project every participant was expected at and attended
+func (r *Repository) GetAttendance(ctx context.Context, projectID int) ([]model.Attendance, error) {
+	rows, err := r.sq.Select(
+		"p.id", "p.role",
+		"p.user_id", "p.project_id",
+		"u.role", "u.color_code",
+		"u.email", "u.username",
+		"u.first_name", "u.last_name",
+		"u.\"group\"", "u.github_username",
+		"COUNT(a.meeting_id)",
+		"COUNT(a.meeting_id) FILTER (WHERE a.present)").
+		From("participants p").
+		Join("users u ON u.id = p.user_id").
+		LeftJoin("meeting_attendees a ON a.participant_id = p.id").
+		Where(sq.Eq{"p.project_id": projectID}).
+		GroupBy("p.id", "u.id").
+		OrderBy("p.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	res := make([]model.Attendance, 0)
+	for rows.Next() {
+		a := model.Attendance{}
+		if err = rows.Scan(
+			&a.ID, &a.Role,
+			&a.ShortUser.ID, &a.ProjectID,
+			&a.ShortUser.Role, &a.ColorCode,
+			&a.Email, &a.Username,
+			&a.FirstName, &a.LastName,
+			&a.Group, &a.GithubUsername,
+			&a.Meetings, &a.Attended,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		res = append(res, a)
+	}
+	return res, nil
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetNotifications(ctx context.Context, filter *NotificationFilter) ([]model.Notification, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"n.id", "n.user_id",
+		"n.project_id", "n.task_id",
+		"n.event", "n.message",
+		"n.read", "n.created_at").
+		From("notifications n").
+		Where(conditionsFromNotificationFilter(filter)).
+		OrderBy("n.created_at DESC", "n.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	notifications := make([]model.Notification, 0)
+	for rows.Next() {
+		notification := model.Notification{}
+		if err = rows.Scan(
+			&notification.ID, &notification.UserID,
+			&notification.ProjectID, &notification.TaskID,
+			&notification.Event, &notification.Message,
+			&notification.Read, &notification.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+func (r *Repository) GetNotificationCountByFilter(ctx context.Context, filter *NotificationFilter) (int, error) {
+	var count int
+
+	if err := r.sq.Select("COUNT(1)").
+		From("notifications n").
+		Where(conditionsFromNotificationFilter(filter)).
+		QueryRowContext(ctx).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	return count, nil
+}
+
+// InsertNotifications saves the notifications and queues their deliveries
+// to the channels the users have enabled, in one transaction
+func (r *Repository) InsertNotifications(ctx context.Context, notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	q := r.sq.Insert("notifications").
+		Columns("user_id",
+			"project_id", "task_id",
+			"event", "message",
+			"created_at")
+	for _, v := range notifications {
+		q = q.Values(v.UserID,
+			v.ProjectID, v.TaskID,
+			v.Event, v.Message,
+			v.CreatedAt)
+	}
+
+	rows, err := q.Suffix("RETURNING \"id\"").
+		RunWith(tx).
+		QueryContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while performing sql request: %w", err)
+	}
+	ids := make(pq.Int64Array, 0, len(notifications))
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Close(); err != nil {
+		return fmt.Errorf("error while closing sql rows: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `INSERT INTO notification_deliveries
+				(notification_id, user_id, channel, digest, next_attempt_at, created_at)
+				SELECT n.id, n.user_id, uc.channel, uc.digest, n.created_at, n.created_at
+				FROM notifications n
+				  JOIN user_channels uc ON uc.user_id = n.user_id AND uc.enabled
+				WHERE n.id = ANY ($1)`, ids); err != nil {
+		return fmt.Errorf("error while queueing deliveries: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MarkNotificationsRead marks the user's notifications as read, all of them if ids are empty
+func (r *Repository) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids []int) error {
+	eq := sq.Eq{"user_id": userID, "read": false}
+	if len(ids) != 0 {
+		eq["id"] = ids
+	}
+
+	_, err := r.sq.Update("notifications").
+		Set("read", true).
+		Where(eq).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	rows, err := r.sq.Select("np.event", "np.enabled").
+		From("notification_preferences np").
+		Where(sq.Eq{"np.user_id": userID}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	preferences := make([]model.NotificationPreference, 0)
+	for rows.Next() {
+		preference := model.NotificationPreference{}
+		if err = rows.Scan(&preference.Event, &preference.Enabled); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (r *Repository) UpsertNotificationPreferences(ctx context.Context, userID uuid.UUID, preferences []model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	q := r.sq.Insert("notification_preferences").
+		Columns("user_id", "event", "enabled")
+	for _, v := range preferences {
+		q = q.Values(userID, v.Event, v.Enabled)
+	}
+
+	_, err := q.Suffix("ON CONFLICT (user_id, event) DO UPDATE SET enabled = EXCLUDED.enabled").
+		ExecContext(ctx)
+	return err
+}
+
+// GetMutedUserIDs returns those of the users who turned the event off
+func (r *Repository) GetMutedUserIDs(ctx context.Context, event model.NotificationEvent, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.sq.Select("np.user_id").
+		From("notification_preferences np").
+		Where(sq.Eq{"np.event": event, "np.enabled": false, "np.user_id": userIDs}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	muted := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err = rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		muted = append(muted, userID)
+	}
+	return muted, nil
+}
+
+func (r *Repository) AddTaskWatcher(ctx context.Context, taskID int, userID uuid.UUID) error {
+	_, err := r.sq.Insert("task_watchers").
+		Columns("task_id", "user_id").
+		Values(taskID, userID).
+		Suffix("ON CONFLICT DO NOTHING").
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) DeleteTaskWatcher(ctx context.Context, taskID int, userID uuid.UUID) error {
+	_, err := r.sq.Delete("task_watchers").
+		Where(sq.Eq{"task_id": taskID, "user_id": userID}).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) GetTaskWatchers(ctx context.Context, taskID int) ([]model.ShortUser, error) {
+	rows, err := r.sq.Select(
+		"u.id", "u.role",
+		"u.color_code", "u.email",
+		"u.username", "u.first_name",
+		"u.last_name", "u.\"group\"",
+		"u.github_username").
+		From("task_watchers tw").
+		Join("users u ON u.id = tw.user_id").
+		Where(sq.Eq{"tw.task_id": taskID}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	users := make([]model.ShortUser, 0)
+	for rows.Next() {
+		user := model.ShortUser{}
+		if err = rows.Scan(
+			&user.ID, &user.Role,
+			&user.ColorCode, &user.Email,
+			&user.Username, &user.FirstName,
+			&user.LastName, &user.Group,
+			&user.GithubUsername,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetPeerRound(ctx context.Context, filter *PeerRoundFilter) (*model.PeerRound, error) {
+	rounds, err := r.GetPeerRounds(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get peer round: %w", err)
+	case len(rounds) == 0:
+		return nil, ierr.ErrPeerRoundNotFound
+	default:
+		return &rounds[0], nil
+	}
+}
+
+// GetPeerRounds returns the rounds starting from the latest one
+func (r *Repository) GetPeerRounds(ctx context.Context, filter *PeerRoundFilter) ([]model.PeerRound, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"r.id", "r.project_id",
+		"r.name", "r.criteria",
+		"r.max_score", "r.opened_by",
+		"r.opened_at", "r.closes_at",
+		"r.closed_at").
+		From("peer_rounds r").
+		Where(conditionsFromPeerRoundFilter(filter)).
+		OrderBy("r.opened_at DESC", "r.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	rounds := make([]model.PeerRound, 0)
+	for rows.Next() {
+		round := model.PeerRound{}
+		if err = rows.Scan(
+			&round.ID, &round.ProjectID,
+			&round.Name, pq.Array(&round.Criteria),
+			&round.MaxScore, &round.OpenedBy,
+			&round.OpenedAt, &round.ClosesAt,
+			&round.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, nil
+}
+
+func (r *Repository) InsertPeerRound(ctx context.Context, round *model.PeerRound) error {
+	if err := r.sq.Insert("peer_rounds").
+		Columns("project_id", "name",
+			"criteria", "max_score",
+			"opened_by", "opened_at",
+			"closes_at").
+		Values(round.ProjectID, round.Name,
+			pq.Array(round.Criteria), round.MaxScore,
+			round.OpenedBy, round.OpenedAt,
+			round.ClosesAt).
+		Suffix("RETURNING \"id\"").
+		QueryRowContext(ctx).
+		Scan(&round.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ClosePeerRound(ctx context.Context, round *model.PeerRound) error {
+	_, err := r.sq.Update("peer_rounds").
+		Set("closed_at", round.ClosedAt).
+		Where(sq.Eq{"id": round.ID}).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) GetPeerReviews(ctx context.Context, filter *PeerReviewFilter) ([]model.PeerReview, error) {
+	rows, err := r.sq.Select(
+		"pr.id", "pr.round_id",
+		"pr.reviewer_id", "pr.reviewee_id",
+		"pr.scores", "pr.comment",
+		"pr.submitted_at").
+		From("peer_reviews pr").
+		Where(conditionsFromPeerReviewFilter(filter)).
+		OrderBy("pr.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	reviews := make([]model.PeerReview, 0)
+	for rows.Next() {
+		review := model.PeerReview{}
+		if err = rows.Scan(
+			&review.ID, &review.RoundID,
+			&review.ReviewerID, &review.RevieweeID,
+			(*pq.Int64Array)(&review.Scores), &review.Comment,
+			&review.SubmittedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+// UpsertPeerReviews saves the reviews, the ones submitted before by the same reviewer are replaced
+func (r *Repository) UpsertPeerReviews(ctx context.Context, reviews []model.PeerReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	q := r.sq.Insert("peer_reviews").
+		Columns("round_id", "reviewer_id",
+			"reviewee_id", "scores",
+			"comment", "submitted_at")
+	for _, v := range reviews {
+		q = q.Values(v.RoundID, v.ReviewerID,
+			v.RevieweeID, pq.Int64Array(v.Scores),
+			v.Comment, v.SubmittedAt)
+	}
+
+	_, err := q.Suffix(`ON CONFLICT (round_id, reviewer_id, reviewee_id) DO UPDATE
+		SET scores = EXCLUDED.scores, comment = EXCLUDED.comment, submitted_at = EXCLUDED.submitted_at`).
+		ExecContext(ctx)
+	return err
+}
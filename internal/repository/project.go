@@ -33,7 +33,8 @@ func (r *Repository) GetProjects(ctx context.Context, filter *ProjectFilter) ([]
 		"p.id", "p.name",
 		"p.description", "p.photo_url",
 		"p.report_url", "p.report_name",
-		"p.repo_url", "p.active_to").
+		"p.repo_url", "p.active_to",
+		"p.course", "p.auto_transition_tasks").
 		From("projects p").
 		Where(conditionsFromProjectFilter(filter)).
 		Limit(filter.Limit).
@@ -57,6 +58,7 @@ func (r *Repository) GetProjects(ctx context.Context, filter *ProjectFilter) ([]
 			&project.Description, &project.PhotoURL,
 			&project.ReportURL, &project.ReportName,
 			&project.RepoURL, &project.ActiveTo,
+			&project.Course, &project.AutoTransitionTasks,
 		); err != nil {
 			return nil, fmt.Errorf("error while scanning sql row: %w", err)
 		}
@@ -81,10 +83,10 @@ func (r *Repository) InsertProject(ctx context.Context, project *model.Project)
 	row := r.sq.Insert("projects").
 		Columns("name",
 			"description", "photo_url",
-			"active_to").
+			"active_to", "course").
 		Values(project.Name,
 			project.Description, project.PhotoURL,
-			project.ActiveTo).
+			project.ActiveTo, project.Course).
 		Suffix("RETURNING \"id\"").
 		QueryRowContext(ctx)
 
@@ -96,13 +98,15 @@ func (r *Repository) InsertProject(ctx context.Context, project *model.Project)
 func (r *Repository) UpdateProject(ctx context.Context, project *model.Project) error {
 	_, err := r.sq.Update("projects").
 		SetMap(map[string]interface{}{
-			"name":        project.Name,
-			"description": project.Description,
-			"photo_url":   project.PhotoURL,
-			"report_url":  project.ReportURL,
-			"report_name": project.ReportName,
-			"repo_url":    project.RepoURL,
-			"active_to":   project.ActiveTo,
+			"name":                  project.Name,
+			"description":           project.Description,
+			"photo_url":             project.PhotoURL,
+			"report_url":            project.ReportURL,
+			"report_name":           project.ReportName,
+			"repo_url":              project.RepoURL,
+			"active_to":             project.ActiveTo,
+			"course":                project.Course,
+			"auto_transition_tasks": project.AutoTransitionTasks,
 		}).Where(sq.Eq{"id": project.ID}).
 		ExecContext(ctx)
 	return err
@@ -116,7 +120,7 @@ func (r *Repository) DeleteProject(ctx context.Context, id int) error {
 
 func (r *Repository) GetProjectInfo(ctx context.Context, id int) (*model.ProjectInfo, error) {
 	query := `SELECT p.id, p.name, p.description, p.photo_url, p.report_url,
-	 			p.report_name, p.repo_url, p.active_to,
+	 			p.report_name, p.repo_url, p.active_to, p.course,
 				ARRAY_AGG (part.id) participants_ids,
 				ARRAY_AGG (part.role) participants_roles,
 				ARRAY_AGG (u.id) users_ids, ARRAY_AGG (u.role) users_roles,
@@ -133,7 +137,7 @@ func (r *Repository) GetProjectInfo(ctx context.Context, id int) (*model.Project
 				  LEFT JOIN tasks t ON t.project_id = p.id
 				  WHERE p.id = $1
 				  GROUP BY p.id, p.name, p.description, p.photo_url, p.report_url,
-			 	  p.report_name, p.repo_url, p.active_to
+			 	  p.report_name, p.repo_url, p.active_to, p.course
 				  `
 
 	rows, err := r.db.QueryContext(ctx, query, id)
@@ -169,6 +173,7 @@ func (r *Repository) GetProjectInfo(ctx context.Context, id int) (*model.Project
 			&projectInfo.Project.Description, &projectInfo.Project.PhotoURL,
 			&projectInfo.Project.ReportURL, &projectInfo.Project.ReportName,
 			&projectInfo.Project.RepoURL, &projectInfo.Project.ActiveTo,
+			&projectInfo.Project.Course,
 			&participantIDs, &participantRoles,
 			&usersIDs, &usersRoles, &usersColorCodes, &usersEmails,
 			&usersUsernames, &usersFirstNames, &usersLastNames, &usersGroups,
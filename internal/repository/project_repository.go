@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetProjectRepository(ctx context.Context, filter *ProjectRepositoryFilter) (*model.ProjectRepository, error) {
+	repositories, err := r.GetProjectRepositories(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get project repository: %w", err)
+	case len(repositories) == 0:
+		return nil, ierr.ErrProjectRepositoryNotFound
+	default:
+		return &repositories[0], nil
+	}
+}
+
+// GetProjectRepositories returns the repositories in order they were added
+func (r *Repository) GetProjectRepositories(ctx context.Context, filter *ProjectRepositoryFilter) ([]model.ProjectRepository, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"r.id", "r.project_id",
+		"r.url", "r.provider",
+		"r.default_branch", "r.role",
+		"r.created_at").
+		From("project_repositories r").
+		Where(conditionsFromProjectRepositoryFilter(filter)).
+		OrderBy("r.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	repositories := make([]model.ProjectRepository, 0)
+	for rows.Next() {
+		repository := model.ProjectRepository{}
+		if err = rows.Scan(
+			&repository.ID, &repository.ProjectID,
+			&repository.URL, &repository.Provider,
+			&repository.DefaultBranch, &repository.Role,
+			&repository.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		repositories = append(repositories, repository)
+	}
+	return repositories, nil
+}
+
+func (r *Repository) InsertProjectRepository(ctx context.Context, repository *model.ProjectRepository) error {
+	return r.inProjectRepositoriesTx(ctx, repository.ProjectID, func(tx *sql.Tx) error {
+		return r.sq.Insert("project_repositories").
+			Columns("project_id", "url",
+				"provider", "default_branch",
+				"role", "created_at").
+			Values(repository.ProjectID, repository.URL,
+				repository.Provider, repository.DefaultBranch,
+				repository.Role, repository.CreatedAt).
+			Suffix("RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&repository.ID)
+	})
+}
+
+func (r *Repository) UpdateProjectRepository(ctx context.Context, repository *model.ProjectRepository) error {
+	return r.inProjectRepositoriesTx(ctx, repository.ProjectID, func(tx *sql.Tx) error {
+		_, err := r.sq.Update("project_repositories").
+			SetMap(map[string]interface{}{
+				"url":            repository.URL,
+				"provider":       repository.Provider,
+				"default_branch": repository.DefaultBranch,
+				"role":           repository.Role,
+			}).Where(sq.Eq{"id": repository.ID}).
+			RunWith(tx).
+			ExecContext(ctx)
+		return err
+	})
+}
+
+// DeleteProjectRepository deletes the repository with its synced statistics
+func (r *Repository) DeleteProjectRepository(ctx context.Context, repository *model.ProjectRepository) error {
+	return r.inProjectRepositoriesTx(ctx, repository.ProjectID, func(tx *sql.Tx) error {
+		_, err := r.sq.Delete("project_repositories").
+			Where(sq.Eq{"id": repository.ID}).
+			RunWith(tx).
+			ExecContext(ctx)
+		return err
+	})
+}
+
+// inProjectRepositoriesTx changes the repositories of the project in a transaction
+// and keeps the url of the first one as the repo_url of the project
+func (r *Repository) inProjectRepositoriesTx(ctx context.Context, projectID int, change func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = change(tx); err != nil {
+		return fmt.Errorf("error while saving project repository: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE projects
+				SET repo_url = (SELECT r.url FROM project_repositories r
+								WHERE r.project_id = $1 ORDER BY r.id LIMIT 1)
+				WHERE id = $1`, projectID); err != nil {
+		return fmt.Errorf("error while updating project repository url: %w", err)
+	}
+	return tx.Commit()
+}
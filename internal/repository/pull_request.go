@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+// SavePullRequests saves the pull requests of the synced repository in one transaction,
+// the reviews of every saved pull request are replaced with the given ones
+func (r *Repository) SavePullRequests(ctx context.Context, sync *model.GithubSync,
+	prs []model.PullRequest, reviews []model.Review) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	numbers := make([]int, 0, len(prs))
+	for _, pr := range prs {
+		if _, err = r.sq.Insert("pull_requests").
+			Columns("repository_id", "number",
+				"project_id", "author_login",
+				"title", "url",
+				"state", "created_at",
+				"updated_at", "merged_at",
+				"closed_at").
+			Values(sync.RepositoryID, pr.Number,
+				sync.ProjectID, pr.AuthorLogin,
+				pr.Title, pr.URL,
+				pr.State, pr.CreatedAt,
+				pr.UpdatedAt, pr.MergedAt,
+				pr.ClosedAt).
+			Suffix(`ON CONFLICT (repository_id, number) DO UPDATE SET
+				title = EXCLUDED.title,
+				url = EXCLUDED.url,
+				state = EXCLUDED.state,
+				updated_at = EXCLUDED.updated_at,
+				merged_at = EXCLUDED.merged_at,
+				closed_at = EXCLUDED.closed_at`).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while saving pull request %v: %w", pr.Number, err)
+		}
+		numbers = append(numbers, pr.Number)
+	}
+
+	if _, err = r.sq.Delete("pull_request_reviews").
+		Where(sq.Eq{"repository_id": sync.RepositoryID, "number": numbers}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while deleting pull request reviews: %w", err)
+	}
+	if len(reviews) > 0 {
+		insert := r.sq.Insert("pull_request_reviews").
+			Columns("repository_id", "number",
+				"author_login", "state",
+				"comments", "submitted_at")
+		for _, review := range reviews {
+			insert = insert.Values(sync.RepositoryID, review.Number,
+				review.AuthorLogin, review.State,
+				review.Comments, review.SubmittedAt)
+		}
+		if _, err = insert.RunWith(tx).ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while inserting pull request reviews: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPullRequests returns the pull requests of all the repositories of the project
+func (r *Repository) GetPullRequests(ctx context.Context, projectID int) ([]model.PullRequest, error) {
+	rows, err := r.sq.Select(
+		"p.repository_id", "p.number",
+		"p.author_login", "p.title",
+		"p.url", "p.state",
+		"p.created_at", "p.updated_at",
+		"p.merged_at", "p.closed_at").
+		From("pull_requests p").
+		Where(sq.Eq{"p.project_id": projectID}).
+		OrderBy("p.repository_id", "p.number").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	prs := make([]model.PullRequest, 0)
+	for rows.Next() {
+		pr := model.PullRequest{}
+		if err = rows.Scan(
+			&pr.RepositoryID, &pr.Number,
+			&pr.AuthorLogin, &pr.Title,
+			&pr.URL, &pr.State,
+			&pr.CreatedAt, &pr.UpdatedAt,
+			&pr.MergedAt, &pr.ClosedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// GetPullRequestReviews returns the reviews of the pull requests of all the repositories of the project
+func (r *Repository) GetPullRequestReviews(ctx context.Context, projectID int) ([]model.Review, error) {
+	rows, err := r.sq.Select(
+		"v.repository_id", "v.number",
+		"v.author_login", "v.state",
+		"v.comments", "v.submitted_at").
+		From("pull_request_reviews v").
+		Join("pull_requests p ON p.repository_id = v.repository_id AND p.number = v.number").
+		Where(sq.Eq{"p.project_id": projectID}).
+		OrderBy("v.repository_id", "v.number", "v.submitted_at").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	reviews := make([]model.Review, 0)
+	for rows.Next() {
+		review := model.Review{}
+		if err = rows.Scan(
+			&review.RepositoryID, &review.Number,
+			&review.AuthorLogin, &review.State,
+			&review.Comments, &review.SubmittedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
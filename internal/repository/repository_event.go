@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	"go.uber.org/zap"
+)
+
+// InsertRepositoryEvents saves the delivery with its events in one transaction. The delivery received before
+// is not saved again, the commits already saved from a push to another branch are skipped.
+func (r *Repository) InsertRepositoryEvents(ctx context.Context, delivery *model.WebhookDelivery, events []model.RepositoryEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	res, err := r.sq.Insert("webhook_deliveries").
+		Columns("id", "event", "received_at").
+		Values(delivery.ID, delivery.Event, delivery.ReceivedAt).
+		Suffix("ON CONFLICT DO NOTHING").
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error while inserting webhook delivery: %w", err)
+	}
+	if inserted, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("error while inserting webhook delivery: %w", err)
+	} else if inserted == 0 {
+		return ierr.ErrWebhookDeliveryAlreadyReceived
+	}
+
+	for i := range events {
+		event := &events[i]
+		event.DeliveryID = delivery.ID
+		if err = r.sq.Insert("repository_events").
+			Columns("delivery_id", "repository_id",
+				"project_id", "kind",
+				"action", "github_username",
+				"user_id", "number",
+				"sha", "ref",
+				"title", "url",
+				"state", "additions",
+				"deletions", "occurred_at").
+			Values(event.DeliveryID, event.RepositoryID,
+				event.ProjectID, event.Kind,
+				event.Action, event.GithubUsername,
+				event.UserID, event.Number,
+				event.SHA, event.Ref,
+				event.Title, event.URL,
+				event.State, event.Additions,
+				event.Deletions, event.OccurredAt).
+			Suffix("ON CONFLICT DO NOTHING RETURNING \"id\"").
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&event.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("error while inserting repository event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRepositoryEvents returns the events from the latest one
+func (r *Repository) GetRepositoryEvents(ctx context.Context, filter *RepositoryEventFilter) ([]model.RepositoryEvent, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"e.id", "e.delivery_id",
+		"e.repository_id", "e.project_id",
+		"e.kind", "e.action",
+		"e.github_username", "e.user_id",
+		"e.number", "e.sha",
+		"e.ref", "e.title",
+		"e.url", "e.state",
+		"e.additions", "e.deletions",
+		"e.occurred_at").
+		From("repository_events e").
+		Where(conditionsFromRepositoryEventFilter(filter)).
+		OrderBy("e.occurred_at DESC", "e.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	events := make([]model.RepositoryEvent, 0)
+	for rows.Next() {
+		event := model.RepositoryEvent{}
+		if err = rows.Scan(
+			&event.ID, &event.DeliveryID,
+			&event.RepositoryID, &event.ProjectID,
+			&event.Kind, &event.Action,
+			&event.GithubUsername, &event.UserID,
+			&event.Number, &event.SHA,
+			&event.Ref, &event.Title,
+			&event.URL, &event.State,
+			&event.Additions, &event.Deletions,
+			&event.OccurredAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
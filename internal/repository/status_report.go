@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetStatusReport(ctx context.Context, filter *StatusReportFilter) (*model.StatusReport, error) {
+	reports, err := r.GetStatusReports(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get status report: %w", err)
+	case len(reports) == 0:
+		return nil, ierr.ErrStatusReportNotFound
+	default:
+		return &reports[0], nil
+	}
+}
+
+// GetStatusReports returns the reports starting from the latest week
+func (r *Repository) GetStatusReports(ctx context.Context, filter *StatusReportFilter) ([]model.StatusReport, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"s.id", "s.project_id",
+		"s.week_start", "s.done",
+		"s.planned", "s.blockers",
+		"s.tasks_done", "s.commits",
+		"s.submitted_by", "s.submitted_at",
+		"s.updated_at", "s.pm_comment",
+		"s.commented_by", "s.commented_at").
+		From("status_reports s").
+		Where(conditionsFromStatusReportFilter(filter)).
+		OrderBy("s.week_start DESC", "s.project_id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	reports := make([]model.StatusReport, 0)
+	for rows.Next() {
+		report := model.StatusReport{}
+		if err = rows.Scan(
+			&report.ID, &report.ProjectID,
+			&report.WeekStart, &report.Done,
+			&report.Planned, &report.Blockers,
+			&report.TasksDone, &report.Commits,
+			&report.SubmittedBy, &report.SubmittedAt,
+			&report.UpdatedAt, &report.PMComment,
+			&report.CommentedBy, &report.CommentedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// UpsertStatusReport saves the report of the week, a report submitted again replaces
+// the previous one but keeps its submission time and the PM comment
+func (r *Repository) UpsertStatusReport(ctx context.Context, report *model.StatusReport) error {
+	if err := r.sq.Insert("status_reports").
+		Columns("project_id", "week_start",
+			"done", "planned",
+			"blockers", "tasks_done",
+			"commits", "submitted_by",
+			"submitted_at", "updated_at").
+		Values(report.ProjectID, report.WeekStart,
+			report.Done, report.Planned,
+			report.Blockers, report.TasksDone,
+			report.Commits, report.SubmittedBy,
+			report.SubmittedAt, report.UpdatedAt).
+		Suffix(`ON CONFLICT (project_id, week_start) DO UPDATE
+			SET done = EXCLUDED.done, planned = EXCLUDED.planned, blockers = EXCLUDED.blockers,
+				tasks_done = EXCLUDED.tasks_done, commits = EXCLUDED.commits,
+				submitted_by = EXCLUDED.submitted_by, updated_at = EXCLUDED.updated_at
+			RETURNING "id", "submitted_at"`).
+		QueryRowContext(ctx).
+		Scan(&report.ID, &report.SubmittedAt); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateStatusReportComment(ctx context.Context, report *model.StatusReport) error {
+	_, err := r.sq.Update("status_reports").
+		SetMap(map[string]interface{}{
+			"pm_comment":   report.PMComment,
+			"commented_by": report.CommentedBy,
+			"commented_at": report.CommentedAt,
+		}).Where(sq.Eq{"id": report.ID}).
+		ExecContext(ctx)
+	return err
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetSubmission(ctx context.Context, filter *SubmissionFilter) (*model.ReportSubmission, error) {
+	submissions, err := r.GetSubmissions(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	case len(submissions) == 0:
+		return nil, ierr.ErrSubmissionNotFound
+	default:
+		return &submissions[0], nil
+	}
+}
+
+// GetSubmissions returns the submissions starting from the latest version
+func (r *Repository) GetSubmissions(ctx context.Context, filter *SubmissionFilter) ([]model.ReportSubmission, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"s.id", "s.project_id",
+		"s.version", "s.file_id",
+		"s.url", "s.name",
+		"s.comment", "s.submitted_by",
+		"s.submitted_at", "s.late",
+		"s.status", "s.reviewer_id",
+		"s.review_comment", "s.reviewed_at").
+		From("report_submissions s").
+		Where(conditionsFromSubmissionFilter(filter)).
+		OrderBy("s.version DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	submissions := make([]model.ReportSubmission, 0)
+	for rows.Next() {
+		submission := model.ReportSubmission{}
+		if err = rows.Scan(
+			&submission.ID, &submission.ProjectID,
+			&submission.Version, &submission.FileID,
+			&submission.URL, &submission.Name,
+			&submission.Comment, &submission.SubmittedBy,
+			&submission.SubmittedAt, &submission.Late,
+			&submission.Status, &submission.ReviewerID,
+			&submission.ReviewComment, &submission.ReviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		submissions = append(submissions, submission)
+	}
+	return submissions, nil
+}
+
+// InsertSubmission saves the submission as the next version of the project report
+// and makes it the report of the project in one transaction. The project row is locked,
+// so concurrent submissions get the versions one after another.
+func (r *Repository) InsertSubmission(ctx context.Context, submission *model.ReportSubmission) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	var projectID int
+	if err = r.sq.Select("id").
+		From("projects").
+		Where(sq.Eq{"id": submission.ProjectID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).Scan(&projectID); errors.Is(err, sql.ErrNoRows) {
+		return ierr.ErrProjectNotFound
+	} else if err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if err = r.sq.Insert("report_submissions").
+		Columns("project_id", "version",
+			"file_id", "url",
+			"name", "comment",
+			"submitted_by", "submitted_at",
+			"late", "status").
+		Values(submission.ProjectID,
+			sq.Expr("(SELECT COALESCE(MAX(version), 0) + 1 FROM report_submissions WHERE project_id = ?)",
+				submission.ProjectID),
+			submission.FileID, submission.URL,
+			submission.Name, submission.Comment,
+			submission.SubmittedBy, submission.SubmittedAt,
+			submission.Late, submission.Status).
+		Suffix("RETURNING \"id\", \"version\"").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&submission.ID, &submission.Version); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+
+	if _, err = r.sq.Update("projects").
+		SetMap(map[string]interface{}{
+			"report_url":  submission.URL,
+			"report_name": submission.Name,
+		}).Where(sq.Eq{"id": submission.ProjectID}).
+		RunWith(tx).
+		ExecContext(ctx); err != nil {
+		return fmt.Errorf("error while updating project report: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) UpdateSubmissionReview(ctx context.Context, submission *model.ReportSubmission) error {
+	_, err := r.sq.Update("report_submissions").
+		SetMap(map[string]interface{}{
+			"status":         submission.Status,
+			"reviewer_id":    submission.ReviewerID,
+			"review_comment": submission.ReviewComment,
+			"reviewed_at":    submission.ReviewedAt,
+		}).Where(sq.Eq{"id": submission.ID}).
+		ExecContext(ctx)
+	return err
+}
+
+// GetCourseSubmissions returns the latest submission of every project the user owns,
+// only the projects of the course if it is set
+func (r *Repository) GetCourseSubmissions(ctx context.Context, ownerID uuid.UUID, course string) ([]model.ProjectSubmissions, error) {
+	conditions := sq.Eq{"o.user_id": ownerID, "o.role": model.RoleOwner}
+	if course != "" {
+		conditions["p.course"] = course
+	}
+
+	rows, err := r.sq.Select(
+		"p.id", "p.name",
+		"p.course", "p.active_to",
+		"(SELECT COUNT(1) FROM report_submissions c WHERE c.project_id = p.id)",
+		"s.id", "s.version",
+		"s.name", "s.submitted_at",
+		"s.late", "s.status",
+		"s.reviewed_at").
+		From("projects p").
+		Join("participants o ON o.project_id = p.id").
+		LeftJoin(`LATERAL (SELECT * FROM report_submissions
+			WHERE project_id = p.id ORDER BY version DESC LIMIT 1) s ON true`).
+		Where(conditions).
+		OrderBy("p.course", "p.name").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	projects := make([]model.ProjectSubmissions, 0)
+	for rows.Next() {
+		var (
+			project     = model.ProjectSubmissions{}
+			id          sql.NullInt64
+			version     sql.NullInt64
+			name        sql.NullString
+			submittedAt sql.NullTime
+			late        sql.NullBool
+			status      sql.NullString
+			reviewedAt  sql.NullTime
+		)
+		if err = rows.Scan(
+			&project.Project.ID, &project.Project.Name,
+			&project.Project.Course, &project.Project.ActiveTo,
+			&project.Versions,
+			&id, &version,
+			&name, &submittedAt,
+			&late, &status,
+			&reviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+
+		if id.Valid {
+			project.Last = &model.ReportSubmission{
+				ID:          int(id.Int64),
+				ProjectID:   project.Project.ID,
+				Version:     int(version.Int64),
+				Name:        name.String,
+				SubmittedAt: submittedAt.Time,
+				Late:        late.Bool,
+				Status:      model.SubmissionStatus(status.String),
+				ReviewedAt:  reviewedAt,
+			}
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
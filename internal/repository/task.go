@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"be-project-monitoring/internal/db"
 	"be-project-monitoring/internal/domain/model"
@@ -11,6 +13,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -35,9 +38,13 @@ func (r *Repository) GetTasks(ctx context.Context, filter *TaskFilter) ([]model.
 		"t.participant_id",
 		"t.creator_id", "t.status",
 		"t.created_at", "t.updated_at",
-		"t.project_id", "t.approved").
+		"t.project_id", "t.approved",
+		"t.rank", "t.version",
+		"t.labels", "t.due_date",
+		"t.done_at").
 		From("tasks t").
 		Where(conditionsFromTaskFilter(filter)).
+		OrderBy("t.rank", "t.id").
 		Limit(filter.Limit).
 		Offset(filter.Offset).
 		QueryContext(ctx)
@@ -61,6 +68,9 @@ func (r *Repository) GetTasks(ctx context.Context, filter *TaskFilter) ([]model.
 			&task.CreatorID, &task.Status,
 			&task.CreatedAt, &task.UpdatedAt,
 			&task.ProjectID, &task.Approved,
+			&task.Rank, &task.Version,
+			pq.Array(&task.Labels), &task.DueDate,
+			&task.DoneAt,
 		); err != nil {
 			return nil, fmt.Errorf("error while scanning sql row: %w", err)
 		}
@@ -82,40 +92,74 @@ func (r *Repository) GetTaskCountByFilter(ctx context.Context, filter *TaskFilte
 	return count, nil
 }
 
+// InsertTask saves the task, it fails if the column of the task has no room like in MoveTask
 func (r *Repository) InsertTask(ctx context.Context, task *model.Task) error {
-	row := r.sq.Insert("tasks").
-		Columns("name",
-			"description", "suggested_estimate",
-			"participant_id", "creator_id",
-			"status", "created_at",
-			"updated_at", "project_id").
-		Values(task.Name,
-			task.Description, task.Estimate,
-			task.ParticipantID, task.CreatorID,
-			task.Status, task.CreatedAt,
-			task.UpdatedAt, task.ProjectID).
-		Suffix("RETURNING \"id\"").
-		QueryRowContext(ctx)
-
-	if err := row.Scan(&task.ID); err != nil {
-		return fmt.Errorf("error while scanning sql row: %w", err)
+	tasks := []model.Task{*task}
+	if err := r.InsertTasks(ctx, tasks); err != nil {
+		return err
 	}
+	task.ID = tasks[0].ID
 	return nil
 }
 
+// InsertTasks saves all the tasks in one transaction, nothing is saved if a column has no room for the new tasks
+func (r *Repository) InsertTasks(ctx context.Context, tasks []model.Task) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.checkTaskInserts(ctx, tx, tasks); err != nil {
+		return err
+	}
+	for i := range tasks {
+		if err = r.taskInsert(&tasks[i]).
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&tasks[i].ID); err != nil {
+			return fmt.Errorf("error while scanning sql row: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateTask saves the task, it fails if the task moves to a column without room like in MoveTask
 func (r *Repository) UpdateTask(ctx context.Context, task *model.Task) error {
-	_, err := r.sq.Update("tasks").
-		SetMap(map[string]interface{}{
-			"name":               task.Name,
-			"description":        task.Description,
-			"suggested_estimate": task.Estimate,
-			"participant_id":     task.ParticipantID,
-			"status":             task.Status,
-			"updated_at":         task.UpdatedAt,
-			"approved":           task.Approved,
-		}).Where(sq.Eq{"id": task.ID}).
-		ExecContext(ctx)
-	return err
+	return r.UpdateTasks(ctx, []model.Task{*task})
+}
+
+// UpdateTasks saves all the tasks in one transaction, nothing is saved if a column has no room for the moved tasks
+func (r *Repository) UpdateTasks(ctx context.Context, tasks []model.Task) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	if err = r.checkTaskStatusChanges(ctx, tx, tasks); err != nil {
+		return err
+	}
+	for i := range tasks {
+		if _, err = r.sq.Update("tasks").
+			SetMap(taskUpdateMap(&tasks[i])).
+			Where(sq.Eq{"id": tasks[i].ID}).
+			RunWith(tx).
+			ExecContext(ctx); err != nil {
+			return fmt.Errorf("error while updating task %v: %w", tasks[i].ID, err)
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *Repository) DeleteTask(ctx context.Context, id int) error {
@@ -124,6 +168,12 @@ func (r *Repository) DeleteTask(ctx context.Context, id int) error {
 	return err
 }
 
+func (r *Repository) DeleteTasks(ctx context.Context, ids []int) error {
+	_, err := r.sq.Delete("tasks").
+		Where(sq.Eq{"id": ids}).ExecContext(ctx)
+	return err
+}
+
 func (r *Repository) GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo, error) {
 
 	rows, err := r.sq.Select("t.id", "t.name", "t.description",
@@ -131,6 +181,8 @@ func (r *Repository) GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo,
 		"t.participant_id", "t.creator_id",
 		"t.status", "t.created_at",
 		"t.updated_at", "t.project_id", "t.approved",
+		"t.rank", "t.version", "t.labels",
+		"t.due_date", "t.done_at",
 		"u1.id", "u1.role",
 		"u1.color_code", "u1.email",
 		"u1.username", "u1.first_name",
@@ -167,6 +219,8 @@ func (r *Repository) GetTaskInfo(ctx context.Context, id int) (*model.TaskInfo,
 			&taskInfo.ParticipantID, &taskInfo.CreatorID,
 			&taskInfo.Status, &taskInfo.CreatedAt,
 			&taskInfo.UpdatedAt, &taskInfo.ProjectID, &taskInfo.Approved,
+			&taskInfo.Rank, &taskInfo.Version, pq.Array(&taskInfo.Labels),
+			&taskInfo.DueDate, &taskInfo.DoneAt,
 			&nullStrings[0], &nullStrings[1], &nullStrings[2],
 			&nullStrings[3], &nullStrings[4], &nullStrings[5],
 			&nullStrings[6], &nullStrings[7], &nullStrings[8],
@@ -227,20 +281,19 @@ func (r *Repository) DeleteParticipantsFromTask(ctx context.Context, participant
 	return err
 }
 
-func (r *Repository) GetCompletedTasksCountByGHUsername(ctx context.Context, projectID int) ([]model.TaskCount, error) {
+func (r *Repository) GetCompletedTasksCountByUser(ctx context.Context, projectID int) ([]model.TaskCount, error) {
 
-	rows, err := r.sq.Select("u.github_username",
+	rows, err := r.sq.Select("p.user_id",
 		"COUNT(1)",
 		"SUM(t.suggested_estimate)",
 	).
 		From("tasks t").
 		Join("participants p ON p.id = t.participant_id").
-		Join("users u ON u.id = p.user_id").
 		Where(sq.Eq{"t.status": model.Done,
 			"t.project_id": projectID,
 			"t.approved":   true,
 		}).
-		GroupBy("u.github_username").QueryContext(ctx)
+		GroupBy("p.user_id").QueryContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +308,7 @@ func (r *Repository) GetCompletedTasksCountByGHUsername(ctx context.Context, pro
 	for rows.Next() {
 		var taskCount model.TaskCount
 		if err = rows.Scan(
-			&taskCount.GithubUsername,
+			&taskCount.UserID,
 			&taskCount.TotalDone,
 			&taskCount.TotalEstimate,
 		); err != nil {
@@ -266,3 +319,67 @@ func (r *Repository) GetCompletedTasksCountByGHUsername(ctx context.Context, pro
 
 	return res, nil
 }
+
+func (r *Repository) taskInsert(task *model.Task) sq.InsertBuilder {
+	return r.sq.Insert("tasks").
+		Columns("name",
+			"description", "suggested_estimate",
+			"participant_id", "creator_id",
+			"status", "created_at",
+			"updated_at", "project_id",
+			"rank", "labels",
+			"due_date", "done_at").
+		Values(task.Name,
+			task.Description, task.Estimate,
+			task.ParticipantID, task.CreatorID,
+			task.Status, task.CreatedAt,
+			task.UpdatedAt, task.ProjectID,
+			task.Rank, labelsArray(task.Labels),
+			task.DueDate, insertedDoneAt(task)).
+		Suffix("RETURNING \"id\"")
+}
+
+func taskUpdateMap(task *model.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               task.Name,
+		"description":        task.Description,
+		"suggested_estimate": task.Estimate,
+		"participant_id":     task.ParticipantID,
+		"status":             task.Status,
+		"updated_at":         task.UpdatedAt,
+		"approved":           task.Approved,
+		"labels":             labelsArray(task.Labels),
+		"due_date":           task.DueDate,
+		"done_at":            doneAt(task.Status, task.UpdatedAt),
+		"rank":               task.Rank,
+		"version":            sq.Expr("version + 1"),
+	}
+}
+
+// doneAt keeps the time the task was first moved to DONE and clears it when the task is reopened
+func doneAt(status model.TaskStatus, now time.Time) interface{} {
+	if status != model.Done {
+		return nil
+	}
+	return sq.Expr("COALESCE(done_at, ?)", now)
+}
+
+// insertedDoneAt is the done time of the new task, the column can not be referenced in the values of the insert
+func insertedDoneAt(task *model.Task) interface{} {
+	switch {
+	case task.Status != model.Done:
+		return nil
+	case task.DoneAt.Valid:
+		return task.DoneAt.Time
+	default:
+		return task.CreatedAt
+	}
+}
+
+// labelsArray never returns NULL, as tasks.labels is not nullable
+func labelsArray(labels []string) interface{} {
+	if labels == nil {
+		labels = []string{}
+	}
+	return pq.Array(labels)
+}
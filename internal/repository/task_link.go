@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+// UpsertTaskLinks saves the links in one transaction, the link saved before gets
+// the title, the state and the lines of the new one
+func (r *Repository) UpsertTaskLinks(ctx context.Context, links []model.TaskLink) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	for i := range links {
+		link := &links[i]
+		if err = r.sq.Insert("task_links").
+			Columns("task_id", "repository_id",
+				"kind", "sha",
+				"number", "title",
+				"url", "github_username",
+				"user_id", "state",
+				"additions", "deletions",
+				"linked_at", "updated_at").
+			Values(link.TaskID, link.RepositoryID,
+				link.Kind, link.SHA,
+				link.Number, link.Title,
+				link.URL, link.GithubUsername,
+				link.UserID, link.State,
+				link.Additions, link.Deletions,
+				link.LinkedAt, link.UpdatedAt).
+			Suffix(`ON CONFLICT (task_id, repository_id, kind, sha, number) DO UPDATE
+				SET title = EXCLUDED.title, url = EXCLUDED.url,
+					state = EXCLUDED.state, additions = EXCLUDED.additions,
+					deletions = EXCLUDED.deletions, updated_at = EXCLUDED.updated_at
+				RETURNING "id"`).
+			RunWith(tx).
+			QueryRowContext(ctx).
+			Scan(&link.ID); err != nil {
+			return fmt.Errorf("error while upserting task link: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetTaskLinks returns the commits and the pull requests of the task in order they were linked
+func (r *Repository) GetTaskLinks(ctx context.Context, taskID int) ([]model.TaskLink, error) {
+	rows, err := r.sq.Select(
+		"l.id", "l.task_id",
+		"l.repository_id", "l.kind",
+		"l.sha", "l.number",
+		"l.title", "l.url",
+		"l.github_username", "l.user_id",
+		"l.state", "l.additions",
+		"l.deletions", "l.linked_at",
+		"l.updated_at").
+		From("task_links l").
+		Where(sq.Eq{"l.task_id": taskID}).
+		OrderBy("l.linked_at", "l.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	links := make([]model.TaskLink, 0)
+	for rows.Next() {
+		link := model.TaskLink{}
+		if err = rows.Scan(
+			&link.ID, &link.TaskID,
+			&link.RepositoryID, &link.Kind,
+			&link.SHA, &link.Number,
+			&link.Title, &link.URL,
+			&link.GithubUsername, &link.UserID,
+			&link.State, &link.Additions,
+			&link.Deletions, &link.LinkedAt,
+			&link.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// GetTaskChurn returns the linked code of the tasks of the project, the tasks without links are skipped
+func (r *Repository) GetTaskChurn(ctx context.Context, projectID int) ([]model.TaskChurn, error) {
+	rows, err := r.sq.Select("t.id", "t.name", "t.status").
+		Column(sq.Expr("COUNT(*) FILTER (WHERE l.kind = ?)", model.EventCommit)).
+		Column(sq.Expr("COUNT(*) FILTER (WHERE l.kind = ?)", model.EventPullRequest)).
+		Column("SUM(l.additions)").
+		Column("SUM(l.deletions)").
+		From("tasks t").
+		Join("task_links l ON l.task_id = t.id").
+		Where(sq.Eq{"t.project_id": projectID}).
+		GroupBy("t.id", "t.name", "t.status").
+		OrderBy("t.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	churn := make([]model.TaskChurn, 0)
+	for rows.Next() {
+		task := model.TaskChurn{}
+		if err = rows.Scan(&task.TaskID, &task.Name, &task.Status,
+			&task.Commits, &task.PullRequests,
+			&task.Additions, &task.Deletions); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		churn = append(churn, task)
+	}
+	return churn, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"be-project-monitoring/internal/domain/model"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetVCSAccounts(ctx context.Context, userID uuid.UUID) ([]model.VCSAccount, error) {
+	rows, err := r.sq.Select("a.host", "a.username").
+		From("vcs_accounts a").
+		Where(sq.Eq{"a.user_id": userID}).
+		OrderBy("a.host").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	accounts := make([]model.VCSAccount, 0)
+	for rows.Next() {
+		account := model.VCSAccount{}
+		if err = rows.Scan(&account.Host, &account.Username); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// UpsertVCSAccounts saves the accounts of the user, the accounts with an empty username are deleted
+func (r *Repository) UpsertVCSAccounts(ctx context.Context, userID uuid.UUID, accounts []model.VCSAccount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error("error while rolling back transaction", zap.Error(err))
+		}
+	}()
+
+	for _, account := range accounts {
+		if account.Username == "" {
+			_, err = r.sq.Delete("vcs_accounts").
+				Where(sq.Eq{"user_id": userID, "host": account.Host}).
+				RunWith(tx).
+				ExecContext(ctx)
+		} else {
+			_, err = r.sq.Insert("vcs_accounts").
+				Columns("user_id", "host", "username").
+				Values(userID, account.Host, account.Username).
+				Suffix("ON CONFLICT (user_id, host) DO UPDATE SET username = EXCLUDED.username").
+				RunWith(tx).
+				ExecContext(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("error while saving vcs account at %v: %w", account.Host, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetVCSAccountUserID returns the user who has the username at host, uuid.Nil if nobody has it
+func (r *Repository) GetVCSAccountUserID(ctx context.Context, host, username string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.sq.Select("a.user_id").
+		From("vcs_accounts a").
+		Where(sq.Eq{"a.host": host, "LOWER(a.username)": strings.ToLower(username)}).
+		QueryRowContext(ctx).
+		Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return userID, nil
+}
+
+// GetVCSUsernames returns the usernames of the users at host, the users without an account are missing
+func (r *Repository) GetVCSUsernames(ctx context.Context, host string, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	res := make(map[uuid.UUID]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+
+	rows, err := r.sq.Select("a.user_id", "a.username").
+		From("vcs_accounts a").
+		Where(sq.Eq{"a.host": host, "a.user_id": userIDs}).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var (
+			userID   uuid.UUID
+			username string
+		)
+		if err = rows.Scan(&userID, &username); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		res[userID] = username
+	}
+	return res, nil
+}
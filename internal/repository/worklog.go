@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-project-monitoring/internal/db"
+	"be-project-monitoring/internal/domain/model"
+	ierr "be-project-monitoring/internal/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+func (r *Repository) GetWorklog(ctx context.Context, filter *WorklogFilter) (*model.Worklog, error) {
+	worklogs, err := r.GetWorklogs(ctx, filter.WithPaginator(1, 0))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to get worklog: %w", err)
+	case len(worklogs) == 0:
+		return nil, ierr.ErrWorklogNotFound
+	default:
+		return &worklogs[0], nil
+	}
+}
+
+func (r *Repository) GetWorklogs(ctx context.Context, filter *WorklogFilter) ([]model.Worklog, error) {
+	filter.Limit = db.NormalizeLimit(filter.Limit)
+
+	rows, err := r.sq.Select(
+		"w.id", "w.task_id",
+		"w.participant_id", "w.duration",
+		"w.date", "w.note",
+		"w.started_at", "w.running",
+		"w.created_at").
+		From("worklogs w").
+		Where(conditionsFromWorklogFilter(filter)).
+		OrderBy("w.date DESC", "w.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	worklogs := make([]model.Worklog, 0)
+	for rows.Next() {
+		worklog := model.Worklog{}
+		if err = rows.Scan(
+			&worklog.ID, &worklog.TaskID,
+			&worklog.ParticipantID, &worklog.Duration,
+			&worklog.Date, &worklog.Note,
+			&worklog.StartedAt, &worklog.Running,
+			&worklog.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		worklogs = append(worklogs, worklog)
+	}
+	return worklogs, nil
+}
+
+func (r *Repository) InsertWorklog(ctx context.Context, worklog *model.Worklog) error {
+	row := r.sq.Insert("worklogs").
+		Columns("task_id",
+			"participant_id", "duration",
+			"date", "note",
+			"started_at", "running",
+			"created_at").
+		Values(worklog.TaskID,
+			worklog.ParticipantID, worklog.Duration,
+			worklog.Date, worklog.Note,
+			worklog.StartedAt, worklog.Running,
+			worklog.CreatedAt).
+		Suffix("RETURNING \"id\"").
+		QueryRowContext(ctx)
+
+	if err := row.Scan(&worklog.ID); err != nil {
+		return fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdateWorklog(ctx context.Context, worklog *model.Worklog) error {
+	_, err := r.sq.Update("worklogs").
+		SetMap(map[string]interface{}{
+			"duration": worklog.Duration,
+			"date":     worklog.Date,
+			"note":     worklog.Note,
+			"running":  worklog.Running,
+		}).Where(sq.Eq{"id": worklog.ID}).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Repository) DeleteWorklog(ctx context.Context, id int) error {
+	_, err := r.sq.Delete("worklogs").
+		Where(sq.Eq{"id": id}).ExecContext(ctx)
+	return err
+}
+
+// GetTaskWorklogTotal sums the minutes of all the stopped worklogs of the task
+func (r *Repository) GetTaskWorklogTotal(ctx context.Context, taskID int) (int, error) {
+	var total int
+	if err := r.sq.Select("COALESCE(SUM(duration), 0)").
+		From("worklogs").
+		Where(sq.Eq{"task_id": taskID, "running": false}).
+		QueryRowContext(ctx).
+		Scan(&total); err != nil {
+		return 0, fmt.Errorf("error while scanning sql row: %w", err)
+	}
+	return total, nil
+}
+
+func (r *Repository) GetWeeklyWorklogs(ctx context.Context, projectID int) ([]model.WeeklyWorklog, error) {
+	rows, err := r.sq.Select(
+		"p.id", "u.id", "u.role",
+		"u.color_code", "u.email",
+		"u.username", "u.first_name",
+		"u.last_name", "u.\"group\"",
+		"u.github_username",
+		"DATE_TRUNC('week', w.date)::date week_start",
+		"SUM(w.duration)").
+		From("worklogs w").
+		Join("tasks t ON t.id = w.task_id").
+		Join("participants p ON p.id = w.participant_id").
+		Join("users u ON u.id = p.user_id").
+		Where(sq.Eq{"t.project_id": projectID, "w.running": false}).
+		GroupBy("p.id", "u.id", "week_start").
+		OrderBy("week_start", "p.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing sql request: %w", err)
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Error("error while closing sql rows", zap.Error(err))
+		}
+	}()
+
+	res := make([]model.WeeklyWorklog, 0)
+	for rows.Next() {
+		weekly := model.WeeklyWorklog{}
+		if err = rows.Scan(
+			&weekly.ParticipantID, &weekly.ID, &weekly.Role,
+			&weekly.ColorCode, &weekly.Email,
+			&weekly.Username, &weekly.FirstName,
+			&weekly.LastName, &weekly.Group,
+			&weekly.GithubUsername,
+			&weekly.WeekStart, &weekly.TotalMinutes,
+		); err != nil {
+			return nil, fmt.Errorf("error while scanning sql row: %w", err)
+		}
+		res = append(res, weekly)
+	}
+	return res, nil
+}
+
+func (r *Repository) GetWorklogTotalsByUser(ctx context.Context, projectID int) ([]model.WorklogCount, error) {
+	rows, err := r.sq.Select("p.user_id",
+		"COALESCE(SUM(w.duration), 0)",
+	).
+		From("worklogs w").
+		Join("tasks t ON t.id = w.task_id").
+		Join("participants p ON p.id = w.participant_id").
+		Where(sq.Eq{"t.project_id": projectID, "w.running": false}).
+		GroupBy("p.user_id").QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err = rows.Close(); err != nil {
+			r.logger.Errorf("error while closing rows: %v", err)
+		}
+	}()
+
+	res := make([]model.WorklogCount, 0)
+	for rows.Next() {
+		var count model.WorklogCount
+		if err = rows.Scan(&count.UserID, &count.TotalMinutes); err != nil {
+			return nil, err
+		}
+		res = append(res, count)
+	}
+
+	return res, nil
+}
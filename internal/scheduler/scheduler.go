@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/oklog/run"
+	"go.uber.org/zap"
+)
+
+type (
+	// Job is run every interval, the first time right after the start. Jobs have to be
+	// safe to run on several replicas at once, e.g. deadline reminders are recorded
+	// in the database, so restarts and replicas do not send them twice.
+	Job struct {
+		Name     string
+		Interval time.Duration
+		Run      func(ctx context.Context) error
+	}
+
+	Scheduler struct {
+		logger *zap.SugaredLogger
+		jobs   []Job
+	}
+
+	OptionFunc func(s *Scheduler)
+)
+
+func New(opts ...OptionFunc) *Scheduler {
+	s := &Scheduler{
+		logger: zap.NewNop().Sugar(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Scheduler) Run(g *run.Group) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g.Add(func() error {
+		s.logger.Info("[scheduler] started")
+
+		wg := sync.WaitGroup{}
+		for _, job := range s.jobs {
+			wg.Add(1)
+			go func(job Job) {
+				defer wg.Done()
+				s.loop(ctx, job)
+			}(job)
+		}
+		wg.Wait()
+		// without the jobs the actor returns at once and run.Group would stop the whole server
+		<-ctx.Done()
+		return nil
+	}, func(err error) {
+		cancel()
+		s.logger.Info("[scheduler] stopped")
+	})
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		if err := job.Run(ctx); err != nil {
+			s.logger.Errorf("[scheduler] job %s failed: %v", job.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func WithLogger(logger *zap.SugaredLogger) OptionFunc {
+	return func(s *Scheduler) {
+		s.logger = logger
+	}
+}
+
+// WithJob adds the job, jobs with a non-positive interval are skipped
+func WithJob(name string, interval time.Duration, job func(ctx context.Context) error) OptionFunc {
+	return func(s *Scheduler) {
+		if interval > 0 {
+			s.jobs = append(s.jobs, Job{Name: name, Interval: interval, Run: job})
+		}
+	}
+}
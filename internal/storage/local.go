@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps files in a directory. It serves the signed urls itself,
+// so it has to be mounted as an http.Handler at baseURL. Without a secret
+// a random one is used, so the urls are valid until the restart.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+func NewLocalStorage(root, baseURL, secret string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("error while creating storage directory: %w", err)
+	}
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("error while generating storage secret: %w", err)
+		}
+		secret = string(random)
+	}
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+func (s *LocalStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("error while creating storage directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error while creating file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("error while writing file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("error while writing file: %w", err)
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error while deleting file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(_ context.Context, key, name string, ttl time.Duration) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"name":      {name},
+		"signature": {s.sign(key, name, expires)},
+	}
+	return s.baseURL + "/" + (&url.URL{Path: key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// ServeHTTP serves the files by the signed urls, the request path is the file key
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		key     = strings.TrimPrefix(r.URL.Path, "/")
+		query   = r.URL.Query()
+		expires = query.Get("expires")
+		name    = query.Get("name")
+	)
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(s.sign(key, name, expires)), []byte(query.Get("signature"))) {
+		http.Error(w, "link is invalid or expired", http.StatusForbidden)
+		return
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	// the type is taken from the extension of the key, the browser must not guess another one
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if name != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	}
+	http.ServeFile(w, r, path)
+}
+
+func (s *LocalStorage) sign(key, name, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + name + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path keeps the file inside the root, keys are generated by the application
+// but are checked anyway
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, filepath.Clean(s.root)+string(filepath.Separator)) {
+		return "", ErrNotFound
+	}
+	return path, nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalStorage(t *testing.T) (*LocalStorage, *httptest.Server) {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	s, err := NewLocalStorage(t.TempDir(), server.URL+"/files/", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Handle("/files/", http.StripPrefix("/files", s))
+	return s, server
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, server := newTestLocalStorage(t)
+	ctx := context.Background()
+	key := "attachment/1/file v1.txt"
+
+	if err := s.Put(ctx, key, strings.NewReader("first"), 5, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	// the file is replaced as a whole
+	if err := s.Put(ctx, key, strings.NewReader("second"), 6, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	signedURL, err := s.SignedURL(ctx, key, "Заметки.txt", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Get(signedURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "second" {
+		t.Fatalf("signed url responded with %d: %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	if got := resp.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "attachment;") {
+		t.Errorf("Content-Disposition = %q, want attachment", got)
+	}
+
+	if err = s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(s.root, filepath.FromSlash(key))); !os.IsNotExist(err) {
+		t.Errorf("file is not deleted: %v", err)
+	}
+	if err = s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of missing file = %v", err)
+	}
+	if resp, err = server.Client().Get(signedURL); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("deleted file responded with %d", resp.StatusCode)
+	}
+}
+
+func TestLocalStorageRejectsInvalidLinks(t *testing.T) {
+	s, server := newTestLocalStorage(t)
+	ctx := context.Background()
+	if err := s.Put(ctx, "report/1/a.pdf", strings.NewReader("report"), 6, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	signedURL, err := s.SignedURL(ctx, "report/1/a.pdf", "a.pdf", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredURL, err := s.SignedURL(ctx, "report/1/a.pdf", "a.pdf", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signedURL)
+	query := u.Query()
+	query.Set("name", "b.html")
+	u.RawQuery = query.Encode()
+	renamedURL := u.String()
+	otherKeyURL := strings.Replace(signedURL, "/report/1/a.pdf", "/report/2/a.pdf", 1)
+
+	for name, link := range map[string]string{
+		"expired":   expiredURL,
+		"renamed":   renamedURL,
+		"other key": otherKeyURL,
+	} {
+		resp, err := server.Client().Get(link)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s link responded with %d, want %d", name, resp.StatusCode, http.StatusForbidden)
+		}
+	}
+}
+
+func TestLocalStorageKeepsFilesInRoot(t *testing.T) {
+	s, _ := newTestLocalStorage(t)
+	for _, key := range []string{"", "../outside", "report/../../outside"} {
+		if err := s.Put(context.Background(), key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Put(%q) = %v, want %v", key, err, ErrNotFound)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(s.root), "outside")); !os.IsNotExist(err) {
+		t.Errorf("file is written outside the root: %v", err)
+	}
+}
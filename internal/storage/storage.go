@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("file not found in storage")
+
+// Storage keeps uploaded files by key. Files are downloaded by signed urls,
+// so the storage itself is never exposed without the application's permission.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns the url which lets to download the file as name until it expires
+	SignedURL(ctx context.Context, key, name string, ttl time.Duration) (string, error)
+}
@@ -0,0 +1,229 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+// FakeProvider keeps the users and the activity of the repositories in memory, so the service
+// can be run without network access. The repositories are keyed by Repo.FullName.
+// If Err is set every call fails with it.
+type FakeProvider struct {
+	mu sync.RWMutex
+
+	Users        map[string]model.VCSUser
+	Stats        map[string][]model.ContributorWeek
+	Commits      map[string][]model.Commit
+	PullRequests map[string][]model.PullRequest
+	Issues       map[string][]model.Issue
+	Reviews      map[string][]model.Review
+	Err          error
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		Users:        make(map[string]model.VCSUser),
+		Stats:        make(map[string][]model.ContributorWeek),
+		Commits:      make(map[string][]model.Commit),
+		PullRequests: make(map[string][]model.PullRequest),
+		Issues:       make(map[string][]model.Issue),
+		Reviews:      make(map[string][]model.Review),
+	}
+}
+
+// SetErr makes every following call fail with err, nil restores the provider
+func (p *FakeProvider) SetErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Err = err
+}
+
+func (p *FakeProvider) AddUser(user model.VCSUser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Users[user.Login] = user
+}
+
+func (p *FakeProvider) AddCommits(repo Repo, commits ...model.Commit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Commits[repo.FullName()] = append(p.Commits[repo.FullName()], commits...)
+}
+
+func (p *FakeProvider) AddPullRequests(repo Repo, prs ...model.PullRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.PullRequests[repo.FullName()] = append(p.PullRequests[repo.FullName()], prs...)
+}
+
+func (p *FakeProvider) AddIssues(repo Repo, issues ...model.Issue) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Issues[repo.FullName()] = append(p.Issues[repo.FullName()], issues...)
+}
+
+func (p *FakeProvider) AddReviews(repo Repo, reviews ...model.Review) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Reviews[repo.FullName()] = append(p.Reviews[repo.FullName()], reviews...)
+}
+
+func (p *FakeProvider) SetStats(repo Repo, weeks ...model.ContributorWeek) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Stats[repo.FullName()] = weeks
+}
+
+func (p *FakeProvider) GetUser(_ context.Context, username string) (*model.VCSUser, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	user, ok := p.Users[username]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (p *FakeProvider) ContributorStats(_ context.Context, repo Repo) ([]model.ContributorWeek, Rate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, Rate{}, p.Err
+	}
+	return append([]model.ContributorWeek{}, p.Stats[repo.FullName()]...), Rate{}, nil
+}
+
+func (p *FakeProvider) ListCommits(_ context.Context, repo Repo, since, until time.Time) ([]model.Commit, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	res := make([]model.Commit, 0)
+	for _, commit := range p.Commits[repo.FullName()] {
+		if !commit.CommittedAt.Before(since) && commit.CommittedAt.Before(until) {
+			res = append(res, commit)
+		}
+	}
+	return res, nil
+}
+
+func (p *FakeProvider) ListPullRequests(_ context.Context, repo Repo, since time.Time) ([]model.PullRequest, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	res := make([]model.PullRequest, 0)
+	for _, pr := range p.PullRequests[repo.FullName()] {
+		if !pr.UpdatedAt.Before(since) {
+			res = append(res, pr)
+		}
+	}
+	return res, nil
+}
+
+func (p *FakeProvider) ListIssues(_ context.Context, repo Repo, since time.Time) ([]model.Issue, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	res := make([]model.Issue, 0)
+	for _, issue := range p.Issues[repo.FullName()] {
+		if !issue.UpdatedAt.Before(since) {
+			res = append(res, issue)
+		}
+	}
+	return res, nil
+}
+
+func (p *FakeProvider) ListReviews(_ context.Context, repo Repo, number int) ([]model.Review, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	res := make([]model.Review, 0)
+	for _, review := range p.Reviews[repo.FullName()] {
+		if review.Number == number {
+			res = append(res, review)
+		}
+	}
+	return res, nil
+}
+
+// CreateIssue numbers the issue after the issues and the pull requests of the repository, like github does
+func (p *FakeProvider) CreateIssue(_ context.Context, repo Repo, issue model.Issue) (*model.Issue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	issue.Number = 1
+	for _, existing := range p.Issues[repo.FullName()] {
+		if existing.Number >= issue.Number {
+			issue.Number = existing.Number + 1
+		}
+	}
+	for _, pr := range p.PullRequests[repo.FullName()] {
+		if pr.Number >= issue.Number {
+			issue.Number = pr.Number + 1
+		}
+	}
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	issue.URL = fmt.Sprintf("https://%v/%v/issues/%v", repo.Host, repo.FullName(), issue.Number)
+	issue.CreatedAt = time.Now()
+	issue.UpdatedAt = issue.CreatedAt
+	if issue.State == "closed" {
+		issue.ClosedAt.Scan(issue.UpdatedAt)
+	}
+	p.Issues[repo.FullName()] = append(p.Issues[repo.FullName()], issue)
+	return &issue, nil
+}
+
+func (p *FakeProvider) UpdateIssue(_ context.Context, repo Repo, issue model.Issue) (*model.Issue, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	issues := p.Issues[repo.FullName()]
+	for i := range issues {
+		if issues[i].Number != issue.Number {
+			continue
+		}
+		existing := &issues[i]
+		existing.Title = issue.Title
+		existing.Body = issue.Body
+		existing.Assignees = append([]string{}, issue.Assignees...)
+		existing.Labels = append([]string{}, issue.Labels...)
+		existing.UpdatedAt = time.Now()
+		if issue.State != "" && issue.State != existing.State {
+			existing.State = issue.State
+			existing.ClosedAt.Valid = false
+			if existing.State == "closed" {
+				existing.ClosedAt.Scan(existing.UpdatedAt)
+			}
+		}
+		res := *existing
+		return &res, nil
+	}
+	return nil, ErrRepositoryNotFound
+}
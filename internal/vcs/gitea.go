@@ -0,0 +1,317 @@
+package vcs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+// giteaPerPage is the default maximum page size of gitea
+const giteaPerPage = 50
+
+// giteaReviewStates translates the states of the submitted reviews to the github ones
+var giteaReviewStates = map[string]string{
+	"APPROVED":        model.ReviewApproved,
+	"REQUEST_CHANGES": model.ReviewChangesRequested,
+	"COMMENT":         model.ReviewCommented,
+}
+
+// GiteaProvider reads a gitea or a forgejo instance with an access token
+type GiteaProvider struct {
+	rest *restClient
+}
+
+type (
+	giteaUser struct {
+		Login     string `json:"login"`
+		FullName  string `json:"full_name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+
+	giteaCommit struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+		Author *giteaUser `json:"author"`
+		Stats  *struct {
+			Additions int `json:"additions"`
+			Deletions int `json:"deletions"`
+		} `json:"stats"`
+	}
+
+	giteaPullRequest struct {
+		Number  int       `json:"number"`
+		Title   string    `json:"title"`
+		Body    string    `json:"body"`
+		State   string    `json:"state"`
+		User    giteaUser `json:"user"`
+		HTMLURL string    `json:"html_url"`
+		Head    struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		MergedAt  *time.Time `json:"merged_at"`
+		ClosedAt  *time.Time `json:"closed_at"`
+	}
+
+	giteaReview struct {
+		User          *giteaUser `json:"user"`
+		State         string     `json:"state"`
+		Dismissed     bool       `json:"dismissed"`
+		CommentsCount int        `json:"comments_count"`
+		SubmittedAt   time.Time  `json:"submitted_at"`
+	}
+
+	giteaIssue struct {
+		Number    int         `json:"number"`
+		Title     string      `json:"title"`
+		Body      string      `json:"body"`
+		State     string      `json:"state"`
+		User      giteaUser   `json:"user"`
+		Assignees []giteaUser `json:"assignees"`
+		Labels    []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		HTMLURL   string     `json:"html_url"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		ClosedAt  *time.Time `json:"closed_at"`
+	}
+)
+
+// NewGiteaProvider creates the provider of the gitea instance at baseURL like https://git.example.com
+func NewGiteaProvider(baseURL, token string, client *http.Client) (*GiteaProvider, error) {
+	if token != "" {
+		token = "token " + token
+	}
+	rest, err := newRestClient("gitea", baseURL, "/api/v1", "Authorization", token, client)
+	if err != nil {
+		return nil, err
+	}
+	return &GiteaProvider{rest: rest}, nil
+}
+
+// Host is the host of the repository urls the provider serves
+func (p *GiteaProvider) Host() string {
+	return p.rest.host
+}
+
+func (p *GiteaProvider) GetUser(ctx context.Context, username string) (*model.VCSUser, error) {
+	user := giteaUser{}
+	_, err := p.rest.get(ctx, "users/"+url.PathEscape(username), nil, &user)
+	if err == errNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.VCSUser{
+		Login:     user.Login,
+		Name:      user.FullName,
+		AvatarURL: user.AvatarURL,
+	}, nil
+}
+
+func (p *GiteaProvider) ContributorStats(ctx context.Context, repo Repo) ([]model.ContributorWeek, Rate, error) {
+	commits, header, err := p.listCommits(ctx, repo, url.Values{})
+	if err != nil {
+		return nil, Rate{}, err
+	}
+	return commitWeeks(commits), rateFromHeader(header), nil
+}
+
+func (p *GiteaProvider) ListCommits(ctx context.Context, repo Repo, since, until time.Time) ([]model.Commit, error) {
+	commits, _, err := p.listCommits(ctx, repo, url.Values{
+		"since": {since.UTC().Format(time.RFC3339)},
+		"until": {until.UTC().Format(time.RFC3339)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the older versions of gitea ignore the period
+	res := make([]model.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if !commit.CommittedAt.Before(since) && commit.CommittedAt.Before(until) {
+			res = append(res, commit)
+		}
+	}
+	return res, nil
+}
+
+func (p *GiteaProvider) listCommits(ctx context.Context, repo Repo, query url.Values) ([]model.Commit, http.Header, error) {
+	query.Set("stat", "true")
+	query.Set("verification", "false")
+	query.Set("files", "false")
+	query.Set("limit", strconv.Itoa(giteaPerPage))
+
+	res := make([]model.Commit, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var commits []giteaCommit
+		header, err := p.rest.get(ctx, giteaRepoPath(repo)+"/commits", query, &commits)
+		if err != nil {
+			return nil, nil, giteaError(err)
+		}
+		for _, commit := range commits {
+			c := model.Commit{
+				SHA:         commit.SHA,
+				AuthorName:  commit.Commit.Author.Name,
+				AuthorEmail: commit.Commit.Author.Email,
+				Message:     commit.Commit.Message,
+				URL:         commit.HTMLURL,
+				CommittedAt: commit.Commit.Author.Date,
+			}
+			if commit.Author != nil {
+				c.AuthorLogin = commit.Author.Login
+			}
+			if commit.Stats != nil {
+				c.Additions = commit.Stats.Additions
+				c.Deletions = commit.Stats.Deletions
+			}
+			res = append(res, c)
+		}
+		if len(commits) < giteaPerPage {
+			return res, header, nil
+		}
+	}
+}
+
+func (p *GiteaProvider) ListPullRequests(ctx context.Context, repo Repo, since time.Time) ([]model.PullRequest, error) {
+	query := url.Values{
+		"state": {"all"},
+		"sort":  {"recentupdate"},
+		"limit": {strconv.Itoa(giteaPerPage)},
+	}
+
+	res := make([]model.PullRequest, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var prs []giteaPullRequest
+		if _, err := p.rest.get(ctx, giteaRepoPath(repo)+"/pulls", query, &prs); err != nil {
+			return nil, giteaError(err)
+		}
+		for _, pr := range prs {
+			// the pull requests come from the last updated, so the rest are older
+			if pr.UpdatedAt.Before(since) {
+				return res, nil
+			}
+			res = append(res, model.PullRequest{
+				Number:      pr.Number,
+				Title:       pr.Title,
+				Body:        pr.Body,
+				State:       openState(pr.State),
+				AuthorLogin: pr.User.Login,
+				HeadBranch:  pr.Head.Ref,
+				URL:         pr.HTMLURL,
+				CreatedAt:   pr.CreatedAt,
+				UpdatedAt:   pr.UpdatedAt,
+				MergedAt:    nullTime(pr.MergedAt),
+				ClosedAt:    nullTime(pr.ClosedAt),
+			})
+		}
+		if len(prs) < giteaPerPage {
+			return res, nil
+		}
+	}
+}
+
+func (p *GiteaProvider) ListReviews(ctx context.Context, repo Repo, number int) ([]model.Review, error) {
+	query := url.Values{"limit": {strconv.Itoa(giteaPerPage)}}
+
+	res := make([]model.Review, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var reviews []giteaReview
+		if _, err := p.rest.get(ctx, giteaRepoPath(repo)+"/pulls/"+strconv.Itoa(number)+"/reviews",
+			query, &reviews); err != nil {
+			return nil, giteaError(err)
+		}
+		for _, review := range reviews {
+			state, ok := giteaReviewStates[review.State]
+			if !ok || review.User == nil {
+				continue
+			}
+			if review.Dismissed {
+				state = model.ReviewDismissed
+			}
+			res = append(res, model.Review{
+				Number:      number,
+				AuthorLogin: review.User.Login,
+				State:       state,
+				Comments:    review.CommentsCount,
+				SubmittedAt: review.SubmittedAt,
+			})
+		}
+		if len(reviews) < giteaPerPage {
+			return res, nil
+		}
+	}
+}
+
+func (p *GiteaProvider) ListIssues(ctx context.Context, repo Repo, since time.Time) ([]model.Issue, error) {
+	query := url.Values{
+		"state": {"all"},
+		"type":  {"issues"},
+		"since": {since.UTC().Format(time.RFC3339)},
+		"limit": {strconv.Itoa(giteaPerPage)},
+	}
+
+	res := make([]model.Issue, 0)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var issues []giteaIssue
+		if _, err := p.rest.get(ctx, giteaRepoPath(repo)+"/issues", query, &issues); err != nil {
+			return nil, giteaError(err)
+		}
+		for _, issue := range issues {
+			assignees := make([]string, 0, len(issue.Assignees))
+			for _, assignee := range issue.Assignees {
+				assignees = append(assignees, assignee.Login)
+			}
+			labels := make([]string, 0, len(issue.Labels))
+			for _, label := range issue.Labels {
+				labels = append(labels, label.Name)
+			}
+			res = append(res, model.Issue{
+				Number:      issue.Number,
+				Title:       issue.Title,
+				Body:        issue.Body,
+				State:       openState(issue.State),
+				AuthorLogin: issue.User.Login,
+				Assignees:   assignees,
+				Labels:      labels,
+				URL:         issue.HTMLURL,
+				CreatedAt:   issue.CreatedAt,
+				UpdatedAt:   issue.UpdatedAt,
+				ClosedAt:    nullTime(issue.ClosedAt),
+			})
+		}
+		if len(issues) < giteaPerPage {
+			return res, nil
+		}
+	}
+}
+
+func giteaRepoPath(repo Repo) string {
+	return "repos/" + url.PathEscape(repo.Owner) + "/" + url.PathEscape(repo.Name)
+}
+
+func giteaError(err error) error {
+	if err == errNotFound {
+		return ErrRepositoryNotFound
+	}
+	return err
+}
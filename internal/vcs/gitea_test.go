@@ -0,0 +1,170 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+func newTestGitea(t *testing.T, handler http.HandlerFunc) *GiteaProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := NewGiteaProvider(server.URL, "secret", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestGiteaGetUser(t *testing.T) {
+	provider := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Errorf("token is not sent")
+		}
+		if r.URL.Path != "/api/v1/users/alice" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"login":"alice","full_name":"Alice A","avatar_url":"https://a/a.png"}`)
+	})
+
+	user, err := provider.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Login != "alice" || user.Name != "Alice A" || user.AvatarURL != "https://a/a.png" {
+		t.Errorf("GetUser() = %+v", user)
+	}
+	if _, err = provider.GetUser(context.Background(), "bob"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUser() error = %v, want %v", err, ErrUserNotFound)
+	}
+}
+
+func TestGiteaListCommits(t *testing.T) {
+	since := time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+	pages := 0
+	provider := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/owner/repo/commits" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("stat") != "true" || r.URL.Query().Get("limit") != strconv.Itoa(giteaPerPage) {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		pages++
+		commits := make([]string, 0, giteaPerPage)
+		if r.URL.Query().Get("page") == "1" {
+			// the full page is followed by the next one
+			for i := 0; i < giteaPerPage; i++ {
+				commits = append(commits, fmt.Sprintf(`{"sha":"a%d","author":{"login":"alice"},
+					"commit":{"author":{"email":"alice@example.com","date":"2023-03-07T10:00:00Z"}},
+					"stats":{"additions":1,"deletions":1}}`, i))
+			}
+		} else {
+			// the older versions ignore the period
+			commits = append(commits,
+				`{"sha":"b1","author":null,"commit":{"author":{"name":"Bob","email":"bob@example.com",
+					"date":"2023-03-08T10:00:00Z"}}}`,
+				`{"sha":"old","author":{"login":"alice"},"commit":{"author":{"date":"2023-03-01T10:00:00Z"}}}`)
+		}
+		fmt.Fprint(w, "["+strings.Join(commits, ",")+"]")
+	})
+
+	commits, err := provider.ListCommits(context.Background(), Repo{Owner: "owner", Name: "repo"}, since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages != 2 {
+		t.Errorf("%d pages are read, want 2", pages)
+	}
+	if len(commits) != giteaPerPage+1 {
+		t.Fatalf("ListCommits() returned %d commits, want %d", len(commits), giteaPerPage+1)
+	}
+	if commits[0].AuthorLogin != "alice" || commits[0].Additions != 1 || commits[0].Deletions != 1 {
+		t.Errorf("commit = %+v", commits[0])
+	}
+	last := commits[len(commits)-1]
+	if last.SHA != "b1" || last.AuthorLogin != "" || last.AuthorEmail != "bob@example.com" || last.Additions != 0 {
+		t.Errorf("commit without author = %+v", last)
+	}
+}
+
+func TestGiteaListPullRequests(t *testing.T) {
+	provider := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sort") != "recentupdate" {
+			t.Errorf("pull requests are not sorted by update")
+		}
+		fmt.Fprint(w, `[
+			{"number":3,"state":"open","user":{"login":"alice"},"head":{"ref":"feature"},
+				"updated_at":"2023-03-08T10:00:00Z"},
+			{"number":2,"state":"closed","user":{"login":"bob"},"updated_at":"2023-03-07T10:00:00Z",
+				"merged_at":"2023-03-07T10:00:00Z"},
+			{"number":1,"state":"closed","user":{"login":"bob"},"updated_at":"2023-03-01T10:00:00Z"}]`)
+	})
+
+	prs, err := provider.ListPullRequests(context.Background(), Repo{Owner: "owner", Name: "repo"},
+		time.Date(2023, 3, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("ListPullRequests() = %+v, want the pull requests updated since", prs)
+	}
+	if prs[0].Number != 3 || prs[0].State != "open" || prs[0].HeadBranch != "feature" || prs[0].MergedAt.Valid {
+		t.Errorf("open pull request = %+v", prs[0])
+	}
+	if prs[1].Number != 2 || prs[1].State != "closed" || !prs[1].MergedAt.Valid {
+		t.Errorf("merged pull request = %+v", prs[1])
+	}
+}
+
+func TestGiteaListReviews(t *testing.T) {
+	provider := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/owner/repo/pulls/5/reviews" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[
+			{"user":{"login":"bob"},"state":"APPROVED","comments_count":1},
+			{"user":{"login":"cid"},"state":"REQUEST_CHANGES","dismissed":true},
+			{"user":{"login":"cid"},"state":"PENDING"},
+			{"user":null,"state":"COMMENT"}]`)
+	})
+
+	reviews, err := provider.ListReviews(context.Background(), Repo{Owner: "owner", Name: "repo"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.Review{
+		{Number: 5, AuthorLogin: "bob", State: model.ReviewApproved, Comments: 1},
+		{Number: 5, AuthorLogin: "cid", State: model.ReviewDismissed},
+	}
+	if len(reviews) != len(want) {
+		t.Fatalf("ListReviews() = %+v", reviews)
+	}
+	for i := range want {
+		if reviews[i] != want[i] {
+			t.Errorf("review = %+v, want %+v", reviews[i], want[i])
+		}
+	}
+}
+
+func TestGiteaRepositoryNotFound(t *testing.T) {
+	provider := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := provider.ContributorStats(context.Background(), Repo{Owner: "owner", Name: "missing"})
+	if !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("ContributorStats() error = %v, want %v", err, ErrRepositoryNotFound)
+	}
+}
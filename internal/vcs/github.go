@@ -0,0 +1,299 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/google/go-github/v49/github"
+)
+
+const (
+	githubPerPage = 100
+	// githubRetryAfter is used when github limits the secondary rate without telling for how long
+	githubRetryAfter = time.Minute
+)
+
+// GithubProvider reads github.com with the client of the application token
+type GithubProvider struct {
+	client *github.Client
+}
+
+func NewGithubProvider(client *github.Client) *GithubProvider {
+	return &GithubProvider{client: client}
+}
+
+func (p *GithubProvider) GetUser(ctx context.Context, username string) (*model.VCSUser, error) {
+	user, resp, err := p.client.Users.Get(ctx, username)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, githubError(err)
+	}
+	return &model.VCSUser{
+		Login:     user.GetLogin(),
+		Name:      user.GetName(),
+		AvatarURL: user.GetAvatarURL(),
+	}, nil
+}
+
+func (p *GithubProvider) ContributorStats(ctx context.Context, repo Repo) ([]model.ContributorWeek, Rate, error) {
+	stats, resp, err := p.client.Repositories.ListContributorsStats(ctx, repo.Owner, repo.Name)
+	if err != nil {
+		return nil, Rate{}, githubError(err)
+	}
+
+	weeks := make([]model.ContributorWeek, 0)
+	for _, stat := range stats {
+		login := stat.GetAuthor().GetLogin()
+		if login == "" {
+			continue
+		}
+		for _, week := range stat.Weeks {
+			if week.GetCommits() == 0 && week.GetAdditions() == 0 && week.GetDeletions() == 0 {
+				continue
+			}
+			weeks = append(weeks, model.ContributorWeek{
+				GithubUsername: login,
+				WeekStart:      week.GetWeek().UTC(),
+				Commits:        week.GetCommits(),
+				Additions:      week.GetAdditions(),
+				Deletions:      week.GetDeletions(),
+			})
+		}
+	}
+	return weeks, Rate{
+		Limit:     resp.Rate.Limit,
+		Remaining: resp.Rate.Remaining,
+		Reset:     resp.Rate.Reset.Time,
+	}, nil
+}
+
+func (p *GithubProvider) ListCommits(ctx context.Context, repo Repo, since, until time.Time) ([]model.Commit, error) {
+	opts := &github.CommitsListOptions{
+		Since:       since,
+		Until:       until,
+		ListOptions: github.ListOptions{PerPage: githubPerPage},
+	}
+
+	res := make([]model.Commit, 0)
+	for {
+		commits, resp, err := p.client.Repositories.ListCommits(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, commit := range commits {
+			res = append(res, model.Commit{
+				SHA:         commit.GetSHA(),
+				AuthorLogin: commit.GetAuthor().GetLogin(),
+				AuthorName:  commit.GetCommit().GetAuthor().GetName(),
+				AuthorEmail: commit.GetCommit().GetAuthor().GetEmail(),
+				Message:     commit.GetCommit().GetMessage(),
+				URL:         commit.GetHTMLURL(),
+				CommittedAt: commit.GetCommit().GetAuthor().GetDate(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (p *GithubProvider) ListPullRequests(ctx context.Context, repo Repo, since time.Time) ([]model.PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: githubPerPage},
+	}
+
+	res := make([]model.PullRequest, 0)
+	for {
+		prs, resp, err := p.client.PullRequests.List(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, pr := range prs {
+			// the pull requests come from the last updated, so the rest are older
+			if pr.GetUpdatedAt().Before(since) {
+				return res, nil
+			}
+			res = append(res, model.PullRequest{
+				Number:      pr.GetNumber(),
+				Title:       pr.GetTitle(),
+				Body:        pr.GetBody(),
+				State:       pr.GetState(),
+				AuthorLogin: pr.GetUser().GetLogin(),
+				HeadBranch:  pr.GetHead().GetRef(),
+				URL:         pr.GetHTMLURL(),
+				CreatedAt:   pr.GetCreatedAt(),
+				UpdatedAt:   pr.GetUpdatedAt(),
+				MergedAt:    nullTime(pr.MergedAt),
+				ClosedAt:    nullTime(pr.ClosedAt),
+			})
+		}
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// ListReviews counts the comments on the code of every review, the pending ones are seen by their authors only
+func (p *GithubProvider) ListReviews(ctx context.Context, repo Repo, number int) ([]model.Review, error) {
+	comments := make(map[int64]int)
+	commentOpts := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: githubPerPage}}
+	for {
+		page, resp, err := p.client.PullRequests.ListComments(ctx, repo.Owner, repo.Name, number, commentOpts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, comment := range page {
+			comments[comment.GetPullRequestReviewID()]++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		commentOpts.Page = resp.NextPage
+	}
+
+	opts := &github.ListOptions{PerPage: githubPerPage}
+	res := make([]model.Review, 0)
+	for {
+		reviews, resp, err := p.client.PullRequests.ListReviews(ctx, repo.Owner, repo.Name, number, opts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, review := range reviews {
+			if review.GetState() == "PENDING" {
+				continue
+			}
+			res = append(res, model.Review{
+				Number:      number,
+				AuthorLogin: review.GetUser().GetLogin(),
+				State:       review.GetState(),
+				Comments:    comments[review.GetID()],
+				SubmittedAt: review.GetSubmittedAt(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (p *GithubProvider) ListIssues(ctx context.Context, repo Repo, since time.Time) ([]model.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: githubPerPage},
+	}
+
+	res := make([]model.Issue, 0)
+	for {
+		issues, resp, err := p.client.Issues.ListByRepo(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			res = append(res, githubIssue(issue))
+		}
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (p *GithubProvider) CreateIssue(ctx context.Context, repo Repo, issue model.Issue) (*model.Issue, error) {
+	created, _, err := p.client.Issues.Create(ctx, repo.Owner, repo.Name, githubIssueRequest(issue))
+	if err != nil {
+		return nil, githubError(err)
+	}
+	res := githubIssue(created)
+	return &res, nil
+}
+
+func (p *GithubProvider) UpdateIssue(ctx context.Context, repo Repo, issue model.Issue) (*model.Issue, error) {
+	updated, _, err := p.client.Issues.Edit(ctx, repo.Owner, repo.Name, issue.Number, githubIssueRequest(issue))
+	if err != nil {
+		return nil, githubError(err)
+	}
+	res := githubIssue(updated)
+	return &res, nil
+}
+
+func githubIssue(issue *github.Issue) model.Issue {
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.GetLogin())
+	}
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.GetName())
+	}
+	return model.Issue{
+		Number:      issue.GetNumber(),
+		Title:       issue.GetTitle(),
+		Body:        issue.GetBody(),
+		State:       issue.GetState(),
+		AuthorLogin: issue.GetUser().GetLogin(),
+		Assignees:   assignees,
+		Labels:      labels,
+		URL:         issue.GetHTMLURL(),
+		CreatedAt:   issue.GetCreatedAt(),
+		UpdatedAt:   issue.GetUpdatedAt(),
+		ClosedAt:    nullTime(issue.ClosedAt),
+	}
+}
+
+// githubIssueRequest always sends the assignees and the labels, so the empty lists clear them
+func githubIssueRequest(issue model.Issue) *github.IssueRequest {
+	assignees := append([]string{}, issue.Assignees...)
+	labels := append([]string{}, issue.Labels...)
+	req := &github.IssueRequest{
+		Title:     &issue.Title,
+		Body:      &issue.Body,
+		Assignees: &assignees,
+		Labels:    &labels,
+	}
+	if issue.State != "" {
+		req.State = &issue.State
+	}
+	return req
+}
+
+// githubError translates the errors which the callers have to react to
+func githubError(err error) error {
+	var (
+		acceptedErr  *github.AcceptedError
+		rateLimitErr *github.RateLimitError
+		abuseErr     *github.AbuseRateLimitError
+		responseErr  *github.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &acceptedErr):
+		return ErrStatsComputing
+	case errors.As(err, &rateLimitErr):
+		return &RateLimitError{Reset: rateLimitErr.Rate.Reset.Time}
+	case errors.As(err, &abuseErr):
+		retryAfter := abuseErr.GetRetryAfter()
+		if retryAfter <= 0 {
+			retryAfter = githubRetryAfter
+		}
+		return &RateLimitError{Reset: time.Now().Add(retryAfter)}
+	case errors.As(err, &responseErr) && responseErr.Response.StatusCode == http.StatusNotFound:
+		return ErrRepositoryNotFound
+	default:
+		return err
+	}
+}
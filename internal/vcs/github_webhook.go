@@ -0,0 +1,144 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"be-project-monitoring/internal/domain/model"
+
+	"github.com/google/go-github/v49/github"
+)
+
+// ErrEventNotSupported means the webhook event carries no activity to save, like the ping event
+var ErrEventNotSupported = errors.New("webhook event is not supported")
+
+// githubWebhookEvents are the values of the X-GitHub-Event header of the events saved as activity
+var githubWebhookEvents = map[string]bool{
+	"push":                true,
+	"pull_request":        true,
+	"pull_request_review": true,
+	"issues":              true,
+}
+
+// Webhook is the activity of the repository received in one delivery of the webhook
+type Webhook struct {
+	Repo   Repo
+	Events []model.RepositoryEvent
+}
+
+// ParseGithubWebhook parses the payload of the push, pull_request, pull_request_review and issues events.
+// The events are returned without the project, the repository and the user, OccurredAt is zero
+// if the payload has no time of the event.
+func ParseGithubWebhook(event string, payload []byte) (*Webhook, error) {
+	if !githubWebhookEvents[event] {
+		return nil, ErrEventNotSupported
+	}
+	parsed, err := github.ParseWebHook(event, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github webhook: %w", err)
+	}
+
+	var (
+		repoURL string
+		events  []model.RepositoryEvent
+	)
+	switch e := parsed.(type) {
+	case *github.PushEvent:
+		repoURL = e.GetRepo().GetHTMLURL()
+		events = pushEvents(e)
+	case *github.PullRequestEvent:
+		repoURL = e.GetRepo().GetHTMLURL()
+		events = []model.RepositoryEvent{pullRequestEvent(e)}
+	case *github.PullRequestReviewEvent:
+		repoURL = e.GetRepo().GetHTMLURL()
+		events = []model.RepositoryEvent{reviewEvent(e)}
+	case *github.IssuesEvent:
+		repoURL = e.GetRepo().GetHTMLURL()
+		events = []model.RepositoryEvent{issueEvent(e)}
+	default:
+		return nil, ErrEventNotSupported
+	}
+
+	repo, ok := ParseRepoURL(repoURL)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse github webhook: wrong repository url %q", repoURL)
+	}
+	return &Webhook{Repo: repo, Events: events}, nil
+}
+
+// pushEvents returns the pushed commits, the author unknown to github is kept by the email
+func pushEvents(e *github.PushEvent) []model.RepositoryEvent {
+	branch := strings.TrimPrefix(e.GetRef(), "refs/heads/")
+	events := make([]model.RepositoryEvent, 0, len(e.Commits))
+	for _, commit := range e.Commits {
+		login := commit.GetAuthor().GetLogin()
+		if login == "" {
+			login = strings.ToLower(commit.GetAuthor().GetEmail())
+		}
+		event := model.RepositoryEvent{
+			Kind:           model.EventCommit,
+			Action:         "pushed",
+			GithubUsername: login,
+			Ref:            branch,
+			Title:          commit.GetMessage(),
+			URL:            commit.GetURL(),
+			OccurredAt:     commit.GetTimestamp().Time,
+		}
+		event.SHA.Scan(commit.GetID())
+		events = append(events, event)
+	}
+	return events
+}
+
+func pullRequestEvent(e *github.PullRequestEvent) model.RepositoryEvent {
+	pr := e.GetPullRequest()
+	event := model.RepositoryEvent{
+		Kind:           model.EventPullRequest,
+		Action:         e.GetAction(),
+		GithubUsername: e.GetSender().GetLogin(),
+		Ref:            pr.GetHead().GetRef(),
+		Title:          pr.GetTitle(),
+		URL:            pr.GetHTMLURL(),
+		State:          pr.GetState(),
+		Additions:      pr.GetAdditions(),
+		Deletions:      pr.GetDeletions(),
+		OccurredAt:     pr.GetUpdatedAt(),
+	}
+	if pr.GetMerged() {
+		event.State = "merged"
+	}
+	event.Number.Scan(int64(pr.GetNumber()))
+	return event
+}
+
+func reviewEvent(e *github.PullRequestReviewEvent) model.RepositoryEvent {
+	pr, review := e.GetPullRequest(), e.GetReview()
+	event := model.RepositoryEvent{
+		Kind:           model.EventReview,
+		Action:         e.GetAction(),
+		GithubUsername: review.GetUser().GetLogin(),
+		Ref:            pr.GetHead().GetRef(),
+		Title:          pr.GetTitle(),
+		URL:            review.GetHTMLURL(),
+		State:          strings.ToLower(review.GetState()),
+		OccurredAt:     review.GetSubmittedAt(),
+	}
+	event.Number.Scan(int64(pr.GetNumber()))
+	return event
+}
+
+func issueEvent(e *github.IssuesEvent) model.RepositoryEvent {
+	issue := e.GetIssue()
+	event := model.RepositoryEvent{
+		Kind:           model.EventIssue,
+		Action:         e.GetAction(),
+		GithubUsername: e.GetSender().GetLogin(),
+		Title:          issue.GetTitle(),
+		URL:            issue.GetHTMLURL(),
+		State:          issue.GetState(),
+		OccurredAt:     issue.GetUpdatedAt(),
+	}
+	event.Number.Scan(int64(issue.GetNumber()))
+	return event
+}
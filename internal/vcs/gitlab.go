@@ -0,0 +1,331 @@
+package vcs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+const gitlabPerPage = 100
+
+// GitlabProvider reads a gitlab instance with a personal or a group access token.
+// Gitlab commits carry only the author's email, it is resolved into the username by the users search,
+// which finds the public emails or any email with an administrator token.
+type GitlabProvider struct {
+	rest *restClient
+
+	mu     sync.Mutex
+	logins map[string]string
+}
+
+type (
+	gitlabUser struct {
+		Username  string `json:"username"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+
+	gitlabCommit struct {
+		ID           string    `json:"id"`
+		Message      string    `json:"message"`
+		AuthorName   string    `json:"author_name"`
+		AuthorEmail  string    `json:"author_email"`
+		AuthoredDate time.Time `json:"authored_date"`
+		WebURL       string    `json:"web_url"`
+		Stats        struct {
+			Additions int `json:"additions"`
+			Deletions int `json:"deletions"`
+		} `json:"stats"`
+	}
+
+	gitlabMergeRequest struct {
+		IID          int        `json:"iid"`
+		Title        string     `json:"title"`
+		Description  string     `json:"description"`
+		State        string     `json:"state"`
+		Author       gitlabUser `json:"author"`
+		SourceBranch string     `json:"source_branch"`
+		WebURL       string     `json:"web_url"`
+		CreatedAt    time.Time  `json:"created_at"`
+		UpdatedAt    time.Time  `json:"updated_at"`
+		MergedAt     *time.Time `json:"merged_at"`
+		ClosedAt     *time.Time `json:"closed_at"`
+	}
+
+	gitlabNote struct {
+		Body      string     `json:"body"`
+		Author    gitlabUser `json:"author"`
+		System    bool       `json:"system"`
+		Type      string     `json:"type"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+
+	gitlabIssue struct {
+		IID         int          `json:"iid"`
+		Title       string       `json:"title"`
+		Description string       `json:"description"`
+		State       string       `json:"state"`
+		Author      gitlabUser   `json:"author"`
+		Assignees   []gitlabUser `json:"assignees"`
+		Labels      []string     `json:"labels"`
+		WebURL      string       `json:"web_url"`
+		CreatedAt   time.Time    `json:"created_at"`
+		UpdatedAt   time.Time    `json:"updated_at"`
+		ClosedAt    *time.Time   `json:"closed_at"`
+	}
+)
+
+// NewGitlabProvider creates the provider of the gitlab instance at baseURL like https://gitlab.example.com
+func NewGitlabProvider(baseURL, token string, client *http.Client) (*GitlabProvider, error) {
+	rest, err := newRestClient("gitlab", baseURL, "/api/v4", "PRIVATE-TOKEN", token, client)
+	if err != nil {
+		return nil, err
+	}
+	return &GitlabProvider{
+		rest:   rest,
+		logins: make(map[string]string),
+	}, nil
+}
+
+// Host is the host of the repository urls the provider serves
+func (p *GitlabProvider) Host() string {
+	return p.rest.host
+}
+
+func (p *GitlabProvider) GetUser(ctx context.Context, username string) (*model.VCSUser, error) {
+	var users []gitlabUser
+	if _, err := p.rest.get(ctx, "users", url.Values{"username": {username}}, &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	return &model.VCSUser{
+		Login:     users[0].Username,
+		Name:      users[0].Name,
+		AvatarURL: users[0].AvatarURL,
+	}, nil
+}
+
+func (p *GitlabProvider) ContributorStats(ctx context.Context, repo Repo) ([]model.ContributorWeek, Rate, error) {
+	commits, header, err := p.listCommits(ctx, repo, url.Values{"with_stats": {"true"}})
+	if err != nil {
+		return nil, Rate{}, err
+	}
+	return commitWeeks(commits), rateFromHeader(header), nil
+}
+
+func (p *GitlabProvider) ListCommits(ctx context.Context, repo Repo, since, until time.Time) ([]model.Commit, error) {
+	commits, _, err := p.listCommits(ctx, repo, url.Values{
+		"since": {since.UTC().Format(time.RFC3339)},
+		"until": {until.UTC().Format(time.RFC3339)},
+	})
+	return commits, err
+}
+
+func (p *GitlabProvider) listCommits(ctx context.Context, repo Repo, query url.Values) ([]model.Commit, http.Header, error) {
+	query.Set("per_page", strconv.Itoa(gitlabPerPage))
+
+	var (
+		res    = make([]model.Commit, 0)
+		header http.Header
+		err    error
+	)
+	for page := "1"; page != ""; page = header.Get("X-Next-Page") {
+		query.Set("page", page)
+		var commits []gitlabCommit
+		if header, err = p.rest.get(ctx, gitlabProjectPath(repo)+"/repository/commits", query, &commits); err != nil {
+			return nil, nil, gitlabError(err)
+		}
+		for _, commit := range commits {
+			login, err := p.login(ctx, commit.AuthorEmail)
+			if err != nil {
+				return nil, nil, err
+			}
+			res = append(res, model.Commit{
+				SHA:         commit.ID,
+				AuthorLogin: login,
+				AuthorName:  commit.AuthorName,
+				AuthorEmail: commit.AuthorEmail,
+				Message:     commit.Message,
+				URL:         commit.WebURL,
+				CommittedAt: commit.AuthoredDate,
+				Additions:   commit.Stats.Additions,
+				Deletions:   commit.Stats.Deletions,
+			})
+		}
+	}
+	return res, header, nil
+}
+
+func (p *GitlabProvider) ListPullRequests(ctx context.Context, repo Repo, since time.Time) ([]model.PullRequest, error) {
+	query := url.Values{
+		"state":         {"all"},
+		"updated_after": {since.UTC().Format(time.RFC3339)},
+		"order_by":      {"updated_at"},
+		"per_page":      {strconv.Itoa(gitlabPerPage)},
+	}
+
+	res := make([]model.PullRequest, 0)
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var mrs []gitlabMergeRequest
+		header, err := p.rest.get(ctx, gitlabProjectPath(repo)+"/merge_requests", query, &mrs)
+		if err != nil {
+			return nil, gitlabError(err)
+		}
+		for _, mr := range mrs {
+			res = append(res, model.PullRequest{
+				Number:      mr.IID,
+				Title:       mr.Title,
+				Body:        mr.Description,
+				State:       openState(mr.State),
+				AuthorLogin: mr.Author.Username,
+				HeadBranch:  mr.SourceBranch,
+				URL:         mr.WebURL,
+				CreatedAt:   mr.CreatedAt,
+				UpdatedAt:   mr.UpdatedAt,
+				MergedAt:    nullTime(mr.MergedAt),
+				ClosedAt:    nullTime(mr.ClosedAt),
+			})
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return res, nil
+}
+
+// ListReviews reads the notes of the merge request: every approval is a review, the other notes of every
+// reviewer make one commented review, the notes on the diff are its comments on the code
+func (p *GitlabProvider) ListReviews(ctx context.Context, repo Repo, number int) ([]model.Review, error) {
+	query := url.Values{
+		"sort":     {"asc"},
+		"per_page": {strconv.Itoa(gitlabPerPage)},
+	}
+
+	res := make([]model.Review, 0)
+	commented := make(map[string]int)
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var notes []gitlabNote
+		header, err := p.rest.get(ctx, gitlabProjectPath(repo)+"/merge_requests/"+strconv.Itoa(number)+"/notes",
+			query, &notes)
+		if err != nil {
+			return nil, gitlabError(err)
+		}
+		for _, note := range notes {
+			switch {
+			case note.System && note.Body == "approved this merge request":
+				res = append(res, model.Review{
+					Number:      number,
+					AuthorLogin: note.Author.Username,
+					State:       model.ReviewApproved,
+					SubmittedAt: note.CreatedAt,
+				})
+			case !note.System:
+				i, ok := commented[note.Author.Username]
+				if !ok {
+					i = len(res)
+					commented[note.Author.Username] = i
+					res = append(res, model.Review{
+						Number:      number,
+						AuthorLogin: note.Author.Username,
+						State:       model.ReviewCommented,
+						SubmittedAt: note.CreatedAt,
+					})
+				}
+				if note.Type == "DiffNote" {
+					res[i].Comments++
+				}
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return res, nil
+}
+
+func (p *GitlabProvider) ListIssues(ctx context.Context, repo Repo, since time.Time) ([]model.Issue, error) {
+	query := url.Values{
+		"state":         {"all"},
+		"scope":         {"all"},
+		"updated_after": {since.UTC().Format(time.RFC3339)},
+		"per_page":      {strconv.Itoa(gitlabPerPage)},
+	}
+
+	res := make([]model.Issue, 0)
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var issues []gitlabIssue
+		header, err := p.rest.get(ctx, gitlabProjectPath(repo)+"/issues", query, &issues)
+		if err != nil {
+			return nil, gitlabError(err)
+		}
+		for _, issue := range issues {
+			assignees := make([]string, 0, len(issue.Assignees))
+			for _, assignee := range issue.Assignees {
+				assignees = append(assignees, assignee.Username)
+			}
+			res = append(res, model.Issue{
+				Number:      issue.IID,
+				Title:       issue.Title,
+				Body:        issue.Description,
+				State:       openState(issue.State),
+				AuthorLogin: issue.Author.Username,
+				Assignees:   assignees,
+				Labels:      append([]string{}, issue.Labels...),
+				URL:         issue.WebURL,
+				CreatedAt:   issue.CreatedAt,
+				UpdatedAt:   issue.UpdatedAt,
+				ClosedAt:    nullTime(issue.ClosedAt),
+			})
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return res, nil
+}
+
+// login returns the username of the commit author's email, empty if gitlab does not tell it.
+// The answers are kept for the lifetime of the provider.
+func (p *GitlabProvider) login(ctx context.Context, email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", nil
+	}
+
+	p.mu.Lock()
+	login, ok := p.logins[email]
+	p.mu.Unlock()
+	if ok {
+		return login, nil
+	}
+
+	var users []gitlabUser
+	if _, err := p.rest.get(ctx, "users", url.Values{"search": {email}}, &users); err != nil {
+		return "", gitlabError(err)
+	}
+	if len(users) == 1 {
+		login = users[0].Username
+	}
+
+	p.mu.Lock()
+	p.logins[email] = login
+	p.mu.Unlock()
+	return login, nil
+}
+
+// gitlabProjectPath addresses the project by its full path, the groups may be nested
+func gitlabProjectPath(repo Repo) string {
+	return "projects/" + url.PathEscape(repo.FullName())
+}
+
+func gitlabError(err error) error {
+	if err == errNotFound {
+		return ErrRepositoryNotFound
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+func newTestGitlab(t *testing.T, handler http.HandlerFunc) *GitlabProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := NewGitlabProvider(server.URL, "secret", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestGitlabGetUser(t *testing.T) {
+	provider := newTestGitlab(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/users" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("token is not sent")
+		}
+		if r.URL.Query().Get("username") == "alice" {
+			fmt.Fprint(w, `[{"username":"Alice","name":"Alice A","avatar_url":"https://a/a.png"}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	user, err := provider.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Login != "Alice" || user.Name != "Alice A" || user.AvatarURL != "https://a/a.png" {
+		t.Errorf("GetUser() = %+v", user)
+	}
+	if _, err = provider.GetUser(context.Background(), "bob"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("GetUser() error = %v, want %v", err, ErrUserNotFound)
+	}
+}
+
+func TestGitlabContributorStats(t *testing.T) {
+	searches := 0
+	provider := newTestGitlab(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fsub%2Frepo/repository/commits":
+			if r.URL.Query().Get("with_stats") != "true" {
+				t.Errorf("stats are not requested")
+			}
+			w.Header().Set("RateLimit-Limit", "600")
+			w.Header().Set("RateLimit-Remaining", "599")
+			w.Header().Set("RateLimit-Reset", "1700000000")
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[
+					{"id":"a1","author_email":"Alice@Example.com","authored_date":"2023-03-06T10:00:00Z",
+						"stats":{"additions":10,"deletions":2}},
+					{"id":"a2","author_email":"alice@example.com","authored_date":"2023-03-07T10:00:00Z",
+						"stats":{"additions":5,"deletions":1}}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":"b1","author_email":"stranger@example.com","authored_date":"2023-03-14T10:00:00Z",
+				"stats":{"additions":1,"deletions":0}}]`)
+		case "/api/v4/users":
+			searches++
+			if r.URL.Query().Get("search") == "alice@example.com" {
+				fmt.Fprint(w, `[{"username":"alice"}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	repo, ok := Providers{provider.Host(): provider}.ParseRepoURL("https://" + provider.Host() + "/group/sub/repo")
+	if !ok {
+		t.Fatal("repository url is not parsed")
+	}
+	weeks, rate, err := provider.ContributorStats(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Limit != 600 || rate.Remaining != 599 || !rate.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("rate = %+v", rate)
+	}
+	if searches != 2 {
+		t.Errorf("users are searched %d times, want once for every email", searches)
+	}
+
+	want := map[string]model.ContributorWeek{
+		"alice": {GithubUsername: "alice", WeekStart: time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC),
+			Commits: 2, Additions: 15, Deletions: 3},
+		"stranger@example.com": {GithubUsername: "stranger@example.com",
+			WeekStart: time.Date(2023, 3, 12, 0, 0, 0, 0, time.UTC), Commits: 1, Additions: 1},
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("ContributorStats() = %+v", weeks)
+	}
+	for _, week := range weeks {
+		if week != want[week.GithubUsername] {
+			t.Errorf("week = %+v, want %+v", week, want[week.GithubUsername])
+		}
+	}
+}
+
+func TestGitlabListReviews(t *testing.T) {
+	provider := newTestGitlab(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Frepo/merge_requests/7/notes" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		fmt.Fprint(w, `[
+			{"body":"approved this merge request","system":true,"author":{"username":"bob"},
+				"created_at":"2023-03-06T10:00:00Z"},
+			{"body":"added 1 commit","system":true,"author":{"username":"alice"}},
+			{"body":"nit","type":"DiffNote","author":{"username":"cid"},"created_at":"2023-03-06T11:00:00Z"},
+			{"body":"and here","type":"DiffNote","author":{"username":"cid"}},
+			{"body":"looks fine","author":{"username":"cid"}}]`)
+	})
+
+	reviews, err := provider.ListReviews(context.Background(), Repo{Owner: "group", Name: "repo"}, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("ListReviews() = %+v", reviews)
+	}
+	if reviews[0].AuthorLogin != "bob" || reviews[0].State != model.ReviewApproved || reviews[0].Number != 7 {
+		t.Errorf("approval = %+v", reviews[0])
+	}
+	if reviews[1].AuthorLogin != "cid" || reviews[1].State != model.ReviewCommented || reviews[1].Comments != 2 {
+		t.Errorf("comments = %+v", reviews[1])
+	}
+}
+
+func TestGitlabErrors(t *testing.T) {
+	provider := newTestGitlab(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/api/v4/projects/group%2Fmissing/merge_requests" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := provider.ListPullRequests(context.Background(), Repo{Owner: "group", Name: "missing"}, time.Time{})
+	if !errors.Is(err, ErrRepositoryNotFound) {
+		t.Errorf("ListPullRequests() error = %v, want %v", err, ErrRepositoryNotFound)
+	}
+
+	_, err = provider.ListIssues(context.Background(), Repo{Owner: "group", Name: "repo"}, time.Time{})
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("ListIssues() error = %v, want rate limit", err)
+	}
+	if wait := time.Until(rateLimitErr.Reset); wait < 25*time.Second || wait > 30*time.Second {
+		t.Errorf("rate limit resets in %v, want 30s", wait)
+	}
+}
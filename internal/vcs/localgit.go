@@ -0,0 +1,314 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+// localFetchInterval is how long the fetched mirror is read without fetching it again
+const localFetchInterval = time.Minute
+
+// DefaultGitExcludes are the lockfiles and the generated code whose lines are not counted.
+// The patterns ending with a slash match the directories at any depth, the others match
+// the file names or the whole paths.
+var DefaultGitExcludes = []string{
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock",
+	"poetry.lock", "Pipfile.lock", "composer.lock", "Gemfile.lock",
+	"*.min.js", "*.min.css", "*.map", "*.pb.go", "*_pb2.py", "*_generated.go", "*.generated.*",
+	"vendor/", "node_modules/", "dist/", "build/",
+}
+
+// LocalGitProvider analyses the repositories in the local bare mirrors with the git binary.
+// Unlike the github statistics it counts the merge commits and every author, and it skips the lines
+// of the excluded files. The repositories of the other hosts are fetched with the tokens of their hosts,
+// the local repositories are read only under localRoot. It knows nothing about the users,
+// the pull requests and the issues. The commits have no urls.
+type LocalGitProvider struct {
+	dir       string
+	localRoot string
+	tokens    map[string]string
+	excludes  []string
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	fetched map[string]time.Time
+}
+
+// NewLocalGitProvider keeps the mirrors in dir, the local repositories are not read if localRoot is empty.
+// The default excludes are used if excludes is empty.
+func NewLocalGitProvider(dir, localRoot string, tokens map[string]string, excludes []string) (*LocalGitProvider, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required for the local analysis: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create git mirror dir: %w", err)
+	}
+	if len(excludes) == 0 {
+		excludes = DefaultGitExcludes
+	}
+	if localRoot != "" {
+		localRoot = filepath.Clean(localRoot)
+	}
+	return &LocalGitProvider{
+		dir:       dir,
+		localRoot: localRoot,
+		tokens:    tokens,
+		excludes:  excludes,
+		locks:     make(map[string]*sync.Mutex),
+		fetched:   make(map[string]time.Time),
+	}, nil
+}
+
+func (p *LocalGitProvider) GetUser(context.Context, string) (*model.VCSUser, error) {
+	return nil, ErrUserNotFound
+}
+
+func (p *LocalGitProvider) ContributorStats(ctx context.Context, repo Repo) ([]model.ContributorWeek, Rate, error) {
+	commits, err := p.commits(ctx, repo)
+	if err != nil {
+		return nil, Rate{}, err
+	}
+	return commitWeeks(commits), Rate{}, nil
+}
+
+func (p *LocalGitProvider) ListCommits(ctx context.Context, repo Repo, since, until time.Time) ([]model.Commit, error) {
+	commits, err := p.commits(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Commit, 0, len(commits))
+	for _, commit := range commits {
+		if !commit.CommittedAt.Before(since) && commit.CommittedAt.Before(until) {
+			res = append(res, commit)
+		}
+	}
+	return res, nil
+}
+
+func (p *LocalGitProvider) ListPullRequests(context.Context, Repo, time.Time) ([]model.PullRequest, error) {
+	return make([]model.PullRequest, 0), nil
+}
+
+func (p *LocalGitProvider) ListIssues(context.Context, Repo, time.Time) ([]model.Issue, error) {
+	return make([]model.Issue, 0), nil
+}
+
+func (p *LocalGitProvider) ListReviews(context.Context, Repo, int) ([]model.Review, error) {
+	return make([]model.Review, 0), nil
+}
+
+// commits returns the history of the default branch of the fetched mirror
+func (p *LocalGitProvider) commits(ctx context.Context, repo Repo) ([]model.Commit, error) {
+	dir, err := p.mirror(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// the empty repository has no HEAD to read
+	if _, err = p.git(ctx, nil, "-C", dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return make([]model.Commit, 0), nil
+	}
+	out, err := p.git(ctx, nil, "-C", dir, "-c", "core.quotepath=off", "log",
+		"--no-renames", "--numstat", "--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1d", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return p.parseLog(out), nil
+}
+
+// mirror clones the repository into the bare mirror or fetches its branches if it was not fetched lately.
+// The url and the token are passed to every call, so they are never saved in the mirror.
+func (p *LocalGitProvider) mirror(ctx context.Context, repo Repo) (string, error) {
+	remote, auth, err := p.remoteURL(repo)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(repo.Host + "/" + repo.FullName()))
+	dir := filepath.Join(p.dir, hex.EncodeToString(sum[:])+".git")
+
+	p.mu.Lock()
+	lock, ok := p.locks[dir]
+	if !ok {
+		lock = &sync.Mutex{}
+		p.locks[dir] = lock
+	}
+	p.mu.Unlock()
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err = os.Stat(dir); os.IsNotExist(err) {
+		if _, err = p.git(ctx, auth, "clone", "--bare", "--quiet", "--", remote, dir); err != nil {
+			return "", err
+		}
+		if _, err = p.git(ctx, nil, "-C", dir, "remote", "remove", "origin"); err != nil {
+			return "", err
+		}
+	} else {
+		p.mu.Lock()
+		fetched := p.fetched[dir]
+		p.mu.Unlock()
+		if time.Since(fetched) < localFetchInterval {
+			return dir, nil
+		}
+		if _, err = p.git(ctx, auth, "-C", dir, "fetch", "--prune", "--quiet", "--", remote,
+			"+refs/heads/*:refs/heads/*"); err != nil {
+			return "", err
+		}
+	}
+
+	p.mu.Lock()
+	p.fetched[dir] = time.Now()
+	p.mu.Unlock()
+	return dir, nil
+}
+
+// gitAuth is the config passed to git in the environment, so the token is not seen in the arguments
+// of the process. secret is hidden in the errors.
+type gitAuth struct {
+	env    []string
+	secret string
+}
+
+// remoteURL returns the url to fetch the repository from and the token of its host.
+// The token is sent in the header of the requests to the host only.
+func (p *LocalGitProvider) remoteURL(repo Repo) (string, *gitAuth, error) {
+	if repo.Host == LocalHost {
+		dir := filepath.Clean(repo.FullName())
+		rel, err := filepath.Rel(p.localRoot, dir)
+		if p.localRoot == "" || err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", nil, ErrRepositoryNotFound
+		}
+		return dir, nil, nil
+	}
+
+	u := url.URL{Scheme: "https", Host: repo.Host, Path: "/" + repo.FullName() + ".git"}
+	token := p.tokens[repo.Host]
+	if token == "" {
+		return u.String(), nil, nil
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("oauth2:" + token))
+	return u.String(), &gitAuth{
+		env: []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.https://" + repo.Host + "/.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic " + credentials,
+		},
+		secret: credentials,
+	}, nil
+}
+
+// git runs the command without asking for the credentials
+func (p *LocalGitProvider) git(ctx context.Context, auth *gitAuth, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if auth != nil {
+		cmd.Env = append(cmd.Env, auth.env...)
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if auth != nil {
+			msg = strings.ReplaceAll(msg, auth.secret, "***")
+		}
+		if strings.Contains(msg, "not found") || strings.Contains(msg, "does not exist") ||
+			strings.Contains(msg, "does not appear to be a git repository") {
+			return nil, ErrRepositoryNotFound
+		}
+		return nil, fmt.Errorf("git failed: %v: %s", err, msg)
+	}
+	return out, nil
+}
+
+// parseLog reads the commits of git log --numstat in the format of commits,
+// the binary files have no line counts
+func (p *LocalGitProvider) parseLog(out []byte) []model.Commit {
+	res := make([]model.Commit, 0)
+	for _, record := range strings.Split(string(out), "\x1e") {
+		end := strings.Index(record, "\x1d")
+		if end < 0 {
+			continue
+		}
+		fields := strings.SplitN(record[:end], "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		committedAt, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			continue
+		}
+
+		commit := model.Commit{
+			SHA:         fields[0],
+			AuthorLogin: noreplyLogin(fields[2]),
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			Message:     strings.TrimSpace(fields[4]),
+			CommittedAt: committedAt,
+		}
+		for _, line := range strings.Split(record[end+1:], "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) < 3 || parts[0] == "-" || p.excluded(parts[2]) {
+				continue
+			}
+			additions, _ := strconv.Atoi(parts[0])
+			deletions, _ := strconv.Atoi(parts[1])
+			commit.Additions += additions
+			commit.Deletions += deletions
+		}
+		res = append(res, commit)
+	}
+	return res
+}
+
+func (p *LocalGitProvider) excluded(file string) bool {
+	for _, pattern := range p.excludes {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(file, pattern) || strings.Contains(file, "/"+pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// noreplyLogin returns the login of the private emails like 12345+login@users.noreply.github.com,
+// the other emails have no login
+func noreplyLogin(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || !strings.HasPrefix(strings.ToLower(email[at+1:]), "users.noreply.") {
+		return ""
+	}
+	login := email[:at]
+	if i := strings.IndexAny(login, "+-"); i > 0 {
+		if _, err := strconv.Atoi(login[:i]); err == nil {
+			login = login[i+1:]
+		}
+	}
+	return login
+}
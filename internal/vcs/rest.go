@@ -0,0 +1,171 @@
+package vcs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+// restRetryAfter is used when the provider limits the rate without telling for how long
+const restRetryAfter = time.Minute
+
+var errNotFound = errors.New("not found")
+
+// restClient calls the json api of the self-hosted providers
+type restClient struct {
+	name   string
+	host   string
+	apiURL string
+	header string
+	token  string
+	client *http.Client
+}
+
+func newRestClient(name, baseURL, apiPath, header, token string, client *http.Client) (*restClient, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%s url %q is not valid", name, baseURL)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &restClient{
+		name:   name,
+		host:   strings.TrimPrefix(strings.ToLower(u.Host), "www."),
+		apiURL: u.String() + apiPath,
+		header: header,
+		token:  token,
+		client: client,
+	}, nil
+}
+
+// get decodes the response of the api path, which must be escaped, into dst
+func (c *restClient) get(ctx context.Context, path string, query url.Values, dst interface{}) (http.Header, error) {
+	reqURL := c.apiURL + "/" + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set(c.header, c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while performing %s request: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return resp.Header, errNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return resp.Header, &RateLimitError{Reset: retryAt(resp.Header)}
+	case resp.StatusCode >= 300:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.Header, fmt.Errorf("%s responded with status %d: %s", c.name, resp.StatusCode, body)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return resp.Header, fmt.Errorf("error while decoding %s response: %w", c.name, err)
+	}
+	return resp.Header, nil
+}
+
+// retryAt reads Retry-After in seconds or the reset time of the rate limit
+func retryAt(header http.Header) time.Time {
+	now := time.Now()
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if rate := rateFromHeader(header); !rate.Reset.IsZero() && rate.Reset.After(now) {
+		return rate.Reset
+	}
+	return now.Add(restRetryAfter)
+}
+
+// rateFromHeader reads the RateLimit-* headers of gitlab or the X-RateLimit-* ones
+func rateFromHeader(header http.Header) Rate {
+	rate := Rate{}
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		limit, err := strconv.Atoi(header.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		rate.Limit = limit
+		rate.Remaining, _ = strconv.Atoi(header.Get(prefix + "Remaining"))
+		if reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64); err == nil {
+			rate.Reset = time.Unix(reset, 0)
+		}
+		return rate
+	}
+	return rate
+}
+
+// weekStart returns the sunday of the week of t like github does
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+// commitWeeks sums the statistics of the commits by authors and weeks,
+// the authors without a login are identified by their email
+func commitWeeks(commits []model.Commit) []model.ContributorWeek {
+	type key struct {
+		login string
+		week  time.Time
+	}
+
+	index := make(map[key]int)
+	weeks := make([]model.ContributorWeek, 0)
+	for _, commit := range commits {
+		login := commit.AuthorLogin
+		if login == "" {
+			login = strings.ToLower(commit.AuthorEmail)
+		}
+		if login == "" {
+			continue
+		}
+
+		k := key{login: login, week: weekStart(commit.CommittedAt)}
+		i, ok := index[k]
+		if !ok {
+			i = len(weeks)
+			index[k] = i
+			weeks = append(weeks, model.ContributorWeek{GithubUsername: login, WeekStart: k.week})
+		}
+		weeks[i].Commits++
+		weeks[i].Additions += commit.Additions
+		weeks[i].Deletions += commit.Deletions
+	}
+	return weeks
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// openState translates the states of the self-hosted providers to the github ones
+func openState(state string) string {
+	if state == "opened" || state == "open" || state == "locked" {
+		return "open"
+	}
+	return "closed"
+}
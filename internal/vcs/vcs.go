@@ -0,0 +1,151 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"be-project-monitoring/internal/domain/model"
+)
+
+const (
+	GithubHost = "github.com"
+	// LocalHost is the host of the local repositories with the urls like file:///srv/git/demo.git
+	LocalHost = "local"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found in vcs")
+	ErrRepositoryNotFound = errors.New("repository not found in vcs")
+	// ErrStatsComputing means the statistics are not ready yet, the request should be repeated later
+	ErrStatsComputing = errors.New("repository statistics are being computed")
+)
+
+// VCSProvider reads the users and the activity of the repositories of a version control system
+type VCSProvider interface {
+	GetUser(ctx context.Context, username string) (*model.VCSUser, error)
+	// ContributorStats returns the weeks with any activity of every contributor of the repository
+	ContributorStats(ctx context.Context, repo Repo) ([]model.ContributorWeek, Rate, error)
+	// ListCommits returns the commits of the default branch made in [since, until)
+	ListCommits(ctx context.Context, repo Repo, since, until time.Time) ([]model.Commit, error)
+	// ListPullRequests returns the pull requests updated after since
+	ListPullRequests(ctx context.Context, repo Repo, since time.Time) ([]model.PullRequest, error)
+	// ListIssues returns the issues updated after since, pull requests excluded
+	ListIssues(ctx context.Context, repo Repo, since time.Time) ([]model.Issue, error)
+	// ListReviews returns the submitted reviews of the pull request
+	ListReviews(ctx context.Context, repo Repo, number int) ([]model.Review, error)
+}
+
+// IssueTracker is the provider which can change the issues of the repositories.
+// Only some providers support it, the caller checks the provider with a type assertion.
+type IssueTracker interface {
+	VCSProvider
+	// CreateIssue opens the issue and returns it as it was saved by the provider
+	CreateIssue(ctx context.Context, repo Repo, issue model.Issue) (*model.Issue, error)
+	// UpdateIssue replaces the title, the body, the state, the assignees and the labels of the issue
+	UpdateIssue(ctx context.Context, repo Repo, issue model.Issue) (*model.Issue, error)
+}
+
+// Providers selects the provider of a repository by the host of its url
+type Providers map[string]VCSProvider
+
+type (
+	Repo struct {
+		Host  string
+		Owner string
+		Name  string
+	}
+
+	// Rate is the state of the rate limit after the request, zero if the provider has no limit
+	Rate struct {
+		Limit     int
+		Remaining int
+		Reset     time.Time
+	}
+
+	// RateLimitError means the provider must not be called until Reset
+	RateLimitError struct {
+		Reset time.Time
+	}
+)
+
+// ParseRepoURL parses the repository url like https://github.com/Exresist/be-project-monitoring,
+// the path must be exactly the owner and the name of the repository with an optional .git suffix.
+// The local repository url like file:///srv/git/demo.git is kept as the path of the repository on LocalHost.
+// The nested groups of gitlab are parsed only by Providers.ParseRepoURL.
+func ParseRepoURL(rawURL string) (Repo, bool) {
+	return parseRepoURL(rawURL, func(string) bool { return false })
+}
+
+// ParseRepoURL parses the repository url like the package function, but the owner of a repository at a gitlab host
+// may be a nested group like https://gitlab.example.com/group/subgroup/repo and the page urls
+// like https://gitlab.example.com/group/repo/-/tree/main are cut to the repository.
+func (p Providers) ParseRepoURL(rawURL string) (Repo, bool) {
+	return parseRepoURL(rawURL, func(host string) bool {
+		_, ok := p[host].(*GitlabProvider)
+		return ok
+	})
+}
+
+// parseRepoURL allows the nested owners only at the hosts accepted by nested
+func parseRepoURL(rawURL string, nested func(host string) bool) (Repo, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err == nil && u.Scheme == "file" && u.Host == "" {
+		dir, name := path.Split(path.Clean(u.Path))
+		if !path.IsAbs(u.Path) || name == "" || dir == "/" {
+			return Repo{}, false
+		}
+		return Repo{Host: LocalHost, Owner: strings.TrimSuffix(dir, "/"), Name: name}, true
+	}
+	if err != nil || u.Host == "" {
+		return Repo{}, false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+
+	repoPath := u.Path
+	isNested := nested(host)
+	if i := strings.Index(repoPath, "/-/"); i >= 0 && isNested {
+		repoPath = repoPath[:i]
+	}
+	parts := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if len(parts) < 2 || len(parts) > 2 && !isNested {
+		return Repo{}, false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Repo{}, false
+		}
+	}
+	name := strings.TrimSuffix(parts[len(parts)-1], ".git")
+	if name == "" {
+		return Repo{}, false
+	}
+	return Repo{
+		Host:  host,
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Name:  name,
+	}, true
+}
+
+func (r Repo) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("vcs rate limit exceeded until %v", e.Reset.Format(time.RFC3339))
+}
+
+var (
+	_ VCSProvider = (*GithubProvider)(nil)
+	_ VCSProvider = (*GitlabProvider)(nil)
+	_ VCSProvider = (*GiteaProvider)(nil)
+	_ VCSProvider = (*FakeProvider)(nil)
+	_ VCSProvider = (*LocalGitProvider)(nil)
+
+	_ IssueTracker = (*GithubProvider)(nil)
+	_ IssueTracker = (*FakeProvider)(nil)
+)
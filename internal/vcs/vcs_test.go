@@ -0,0 +1,67 @@
+package vcs
+
+import "testing"
+
+func TestParseRepoURL(t *testing.T) {
+	providers := Providers{
+		GithubHost:           &FakeProvider{},
+		"gitlab.example.com": &GitlabProvider{},
+		"git.example.com":    &GiteaProvider{},
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want Repo
+		ok   bool
+	}{
+		{"github", "https://github.com/Exresist/be-project-monitoring",
+			Repo{Host: GithubHost, Owner: "Exresist", Name: "be-project-monitoring"}, true},
+		{"github www and trailing slash", " https://www.GitHub.com/owner/repo/ ",
+			Repo{Host: GithubHost, Owner: "owner", Name: "repo"}, true},
+		{"github git suffix", "https://github.com/owner/repo.git",
+			Repo{Host: GithubHost, Owner: "owner", Name: "repo"}, true},
+		{"github page", "https://github.com/owner/repo/tree/main", Repo{}, false},
+		{"github dash suffix", "https://github.com/owner/repo/-/tree/main", Repo{}, false},
+		{"github owner only", "https://github.com/owner", Repo{}, false},
+		{"github empty part", "https://github.com/owner//repo", Repo{}, false},
+		{"github only git suffix", "https://github.com/owner/.git", Repo{}, false},
+		{"gitlab", "https://gitlab.example.com/group/repo.git",
+			Repo{Host: "gitlab.example.com", Owner: "group", Name: "repo"}, true},
+		{"gitlab nested groups", "https://gitlab.example.com/group/subgroup/team/repo",
+			Repo{Host: "gitlab.example.com", Owner: "group/subgroup/team", Name: "repo"}, true},
+		{"gitlab page", "https://gitlab.example.com/group/subgroup/repo/-/tree/main",
+			Repo{Host: "gitlab.example.com", Owner: "group/subgroup", Name: "repo"}, true},
+		{"gitlab merge requests", "https://gitlab.example.com/group/repo/-/merge_requests/1",
+			Repo{Host: "gitlab.example.com", Owner: "group", Name: "repo"}, true},
+		{"gitea", "https://git.example.com/owner/repo.git",
+			Repo{Host: "git.example.com", Owner: "owner", Name: "repo"}, true},
+		{"gitea page", "https://git.example.com/owner/repo/src/branch/main", Repo{}, false},
+		{"unknown host", "https://code.example.com/owner/repo",
+			Repo{Host: "code.example.com", Owner: "owner", Name: "repo"}, true},
+		{"unknown host nested", "https://code.example.com/group/subgroup/repo", Repo{}, false},
+		{"local", "file:///srv/git/demo.git",
+			Repo{Host: LocalHost, Owner: "/srv/git", Name: "demo.git"}, true},
+		{"local root", "file:///demo.git", Repo{}, false},
+		{"no host", "github.com/owner/repo", Repo{}, false},
+		{"empty", "", Repo{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := providers.ParseRepoURL(tt.url)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseRepoURL(%q) = %+v, %v, want %+v, %v", tt.url, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseRepoURLWithoutProviders(t *testing.T) {
+	if repo, ok := ParseRepoURL("https://gitlab.example.com/group/subgroup/repo"); ok {
+		t.Errorf("nested owner is parsed without the gitlab provider: %+v", repo)
+	}
+	repo, ok := ParseRepoURL("https://github.com/owner/repo")
+	if !ok || repo.FullName() != "owner/repo" {
+		t.Errorf("ParseRepoURL() = %+v, %v", repo, ok)
+	}
+}